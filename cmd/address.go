@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// resolveAddress returns an address used for matching.
+// If --ignore-case is given, block types and labels in the address are
+// rewritten to match case-insensitively. If attribute is true, the last
+// segment is an attribute name and left as it is. An empty address is
+// returned as it is, because it means the top level in some commands.
+func resolveAddress(cmd *cobra.Command, address string, attribute bool) (string, error) {
+	// The --ignore-case is defined only in the root command.
+	ignoreCase, err := cmd.Flags().GetBool("ignore-case")
+	if err != nil || !ignoreCase || len(address) == 0 {
+		return address, nil
+	}
+
+	return editor.IgnoreCaseAddress(address, attribute)
+}
+
+// resolveAddresses is the same as resolveAddress, but for multiple addresses.
+func resolveAddresses(cmd *cobra.Command, addresses []string, attribute bool) ([]string, error) {
+	resolved := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		a, err := resolveAddress(cmd, address, attribute)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, a)
+	}
+
+	return resolved, nil
+}
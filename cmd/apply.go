@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newApplyCmd())
+}
+
+func newApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a batch script",
+		Long: `Apply a batch script of operations in a single pass
+
+The input is parsed and formatted only once, and operations are applied in
+order, so intermediate results feed later operations. If any of operations
+fails, nothing is written.
+
+Each line of the script is an operation in the form of the subcommand:
+
+  [attribute|block] <OPERATION> <ADDRESS> [<VALUE>]
+
+If the leading attribute or block is omitted, attribute is assumed.
+The supported operations are attribute set, rm, append, mv, and block mv,
+append, rm. Blank lines and lines starting with # are ignored.
+e.g.)
+  set resource.foo.bar.attr1 "val1"
+  rm resource.foo.bar.attr2
+  block mv resource.foo.bar resource.foo.baz
+`,
+		RunE: runApplyCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("script", "s", "", "A path of script file. The - means stdin, which requires --file")
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runApplyCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	path, err := cmd.Flags().GetString("script")
+	if err != nil {
+		return err
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("--script is required")
+	}
+
+	src, err := readFlagFile(cmd, "script", path)
+	if err != nil {
+		return err
+	}
+
+	script, err := editor.ParseScript(strings.NewReader(src))
+	if err != nil {
+		return err
+	}
+
+	return editor.ApplyScript(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), script)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	src := `resource "foo" "bar" {
+  attr1 = "val1"
+  attr2 = "val2"
+}
+`
+
+	cases := []struct {
+		name   string
+		script string
+		args   []string
+		ok     bool
+		want   string
+	}{
+		{
+			name: "simple",
+			script: `# rename and edit
+block mv resource.foo.bar resource.foo.baz
+set resource.foo.baz.attr1 "val3"
+rm resource.foo.baz.attr2
+append resource.foo.baz.attr3 "val4"
+`,
+			args: []string{},
+			ok:   true,
+			want: `resource "foo" "baz" {
+  attr1 = "val3"
+  attr3 = "val4"
+}
+`,
+		},
+		{
+			name:   "parse error",
+			script: "foo resource.foo.bar",
+			args:   []string{},
+			ok:     false,
+			want:   "",
+		},
+		{
+			name:   "too many args",
+			script: "rm resource.foo.bar.attr1",
+			args:   []string{"foo"},
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, cleanup := newTempFile(t, tc.script)
+			defer cleanup()
+
+			cmd := setMockStreams(newApplyCmd(), src)
+			cmd.SetArgs(append([]string{"--script", path}, tc.args...))
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/minamijoyo/hcledit/editor"
 	"github.com/spf13/cobra"
@@ -24,6 +28,16 @@ func newAttributeCmd() *cobra.Command {
 		newAttributeGetCmd(),
 		newAttributeSetCmd(),
 		newAttributeRmCmd(),
+		newAttributeAuditCmd(),
+		newAttributeAppendCmd(),
+		newAttributeMvCmd(),
+		newAttributeExistsCmd(),
+		newAttributeListCmd(),
+		newAttributeAddElementCmd(),
+		newAttributeRmElementCmd(),
+		newAttributeEditCmd(),
+		newAttributeReplaceCmd(),
+		newAttributeFilterCmd(),
 	)
 
 	return cmd
@@ -31,32 +45,148 @@ func newAttributeCmd() *cobra.Command {
 
 func newAttributeGetCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "get <ADDRESS>",
+		Use:   "get <ADDRESS>...",
 		Short: "Get attribute",
 		Long: `Get matched attribute at a given address
 
 Arguments:
   ADDRESS          An address of attribute to get.
+                   If multiple addresses are given, values are written as
+                   address=value lines, or a JSON array with --output json,
+                   in one parse.
+                   If the address contains a recursive descent (**) such as
+                   **.tags and matches the attribute in multiple blocks, an
+                   error lists all matches instead of writing the first one.
+
+The JSON syntax such as *.tf.json is also supported, where the value is
+written as JSON. Only a single ADDRESS with --strict, --exit-status and --raw
+is available for it.
 `,
 		RunE: runAttributeGetCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.StringArray("var", nil, `A known variable NAME=VALUE used for resolving a simple var.NAME reference.
+The value is written as it is. e.g.) --var ami='"ami-123"'`)
+	flags.Bool("strict", false, `Return an error if the attribute is not found, or found in multiple matched blocks.
+The error lists all matches with their positions`)
+	flags.Bool("with-comments", false, "Write leading and trailing comments of the attribute along with the value")
+	flags.Bool("exit-status", false, `Exit with status 1 if the attribute is not found, and 2 for other errors.
+It implies --strict but nothing is printed for the not found`)
+	flags.Bool("raw", false, `Write a string literal value without quotes and with escape sequences decoded.
+Any other expression is written as it is`)
+	flags.Bool("evaluate", false, `Evaluate the value as a constant expression such as 5 * 60 and write the result.
+A value given by --var can be referred as var.NAME in the expression`)
+	addOutputFlag(cmd)
+	addTemplateFlag(cmd)
+
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
 func runAttributeGetCmd(cmd *cobra.Command, args []string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	if len(args) == 0 {
+		return fmt.Errorf("expected at least 1 argument, but got %d arguments", len(args))
+	}
+
+	addresses, err := resolveAddresses(cmd, args, true)
+	if err != nil {
+		return err
+	}
+
+	address := addresses[0]
+	varFlags, err := cmd.Flags().GetStringArray("var")
+	if err != nil {
+		return err
+	}
+
+	vars, err := parseVarFlags(varFlags)
+	if err != nil {
+		return err
+	}
+
+	strict, err := cmd.Flags().GetBool("strict")
+	if err != nil {
+		return err
+	}
+
+	exitStatus, err := cmd.Flags().GetBool("exit-status")
+	if err != nil {
+		return err
 	}
+	// the exit status is converted in preRunRootCmd.
+	strict = strict || exitStatus
 
-	address := args[0]
+	withComments, err := cmd.Flags().GetBool("with-comments")
+	if err != nil {
+		return err
+	}
+
+	output, err := getOutputFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := getTemplateFlag(cmd)
+	if err != nil {
+		return err
+	}
 
-	return editor.GetAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), "-", address)
+	raw, err := cmd.Flags().GetBool("raw")
+	if err != nil {
+		return err
+	}
+
+	evaluate, err := cmd.Flags().GetBool("evaluate")
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 1 {
+		if len(vars) != 0 || withComments || tmpl != nil || raw || evaluate {
+			return fmt.Errorf("multiple addresses cannot be used with --var, --with-comments, --template, --raw or --evaluate")
+		}
+		if output == outputJSON {
+			return editor.GetAttributesJSON(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), addresses, strict)
+		}
+		return editor.GetAttributes(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), addresses, strict)
+	}
+
+	opts := []editor.Option{}
+	if strict {
+		opts = append(opts, editor.WithStrict())
+	}
+	if len(vars) != 0 {
+		opts = append(opts, editor.WithVars(vars))
+	}
+	if evaluate {
+		opts = append(opts, editor.WithEvaluate())
+	}
+	if raw {
+		opts = append(opts, editor.WithRaw())
+	}
+	if withComments {
+		opts = append(opts, editor.WithComments())
+	}
+
+	if output == outputJSON {
+		if tmpl != nil {
+			return fmt.Errorf("--output json cannot be used with --template")
+		}
+		opts = append(opts, editor.WithSink(editor.NewJSONSink()))
+	}
+
+	if tmpl != nil {
+		opts = append(opts, editor.WithSink(editor.NewTemplateSink(tmpl)))
+	}
+
+	return editor.GetAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
 }
 
 func newAttributeSetCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "set <ADDRESS> <VALUE>",
+		Use:   "set <ADDRESS> [<VALUE>]",
 		Short: "Set attribute",
 		Long: `Set a value of matched attribute at a given address
 
@@ -67,45 +197,738 @@ Arguments:
                    Thus, if you want to set a string literal "hoge", be sure to
                    escape double quotes so that they are not discarded by your shell.
                    e.g.) hcledit attribute set aaa.bbb.ccc '"hoge"'
+                   Alternatively, use the --type flag to quote it for you.
+                   e.g.) hcledit attribute set aaa.bbb.ccc hoge --type string
+                   The VALUE must be omitted when the --value-file or --null flag
+                   is given. With the --heredoc flag, the VALUE is content of
+                   heredoc, and it is read from stdin if omitted, which
+                   requires --file for the HCL input.
+
+The JSON syntax such as *.tf.json is also supported, where the VALUE must be
+a JSON value. The --value-file, --heredoc, --after, --if-value and --stream
+flags are not available for it.
 `,
 		RunE: runAttributeSetCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.String("type", "raw", `A type of value. Valid values are string, number, bool, list, map and raw.
+The list and map are given in JSON. The raw sets the value literally.`)
+	flags.String("after", "", `Create the attribute if it doesn't exist, and insert it after a given attribute name.
+If the given attribute doesn't exist, the new attribute is appended at the end of the block.`)
+	flags.String("value-file", "", `Read a value from a given file instead of the VALUE argument.
+The contents are set as a raw expression which may span multiple lines such as a heredoc.
+The - means stdin, which requires --file for the HCL input.`)
+	flags.String("heredoc", "", `Wrap the value in a heredoc with a given delimiter such as EOF.
+The content is given by the VALUE argument, --value-file or stdin, and set literally
+with template sequences such as ${ escaped`)
+	flags.Bool("heredoc-indent", false, "Use an indented heredoc (<<-) whose content is indented to the nesting level. Requires --heredoc")
+	flags.Bool("null", false, `Set the value to a literal null instead of the VALUE argument.
+Unlike removing the attribute, it explicitly unsets the value in Terraform`)
+	flags.String("if-value", "", `Set the value only if the current expression equals a given one like compare-and-swap.
+The expression is compared as text in the same form as attribute get writes.
+It returns an error if the current value doesn't match or the attribute is not found`)
+	flags.Bool("skip-mismatch", false, "Leave the input as it is instead of returning an error if the current value doesn't match. Requires --if-value")
+	addStreamFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
 func runAttributeSetCmd(cmd *cobra.Command, args []string) error {
-	if len(args) != 2 {
+	valueFile, err := cmd.Flags().GetString("value-file")
+	if err != nil {
+		return err
+	}
+
+	null, err := cmd.Flags().GetBool("null")
+	if err != nil {
+		return err
+	}
+
+	heredoc, err := cmd.Flags().GetString("heredoc")
+	if err != nil {
+		return err
+	}
+	heredocIndent, err := cmd.Flags().GetBool("heredoc-indent")
+	if err != nil {
+		return err
+	}
+	if heredocIndent && len(heredoc) == 0 {
+		return fmt.Errorf("--heredoc-indent requires --heredoc")
+	}
+
+	if null {
+		if len(valueFile) != 0 || len(heredoc) != 0 {
+			return fmt.Errorf("--null cannot be used with --value-file or --heredoc")
+		}
+		if len(args) != 1 {
+			return fmt.Errorf("expected 1 argument with --null, but got %d arguments", len(args))
+		}
+	} else if len(valueFile) != 0 {
+		if len(args) != 1 {
+			return fmt.Errorf("expected 1 argument with --value-file, but got %d arguments", len(args))
+		}
+	} else if len(heredoc) != 0 {
+		if len(args) != 1 && len(args) != 2 {
+			return fmt.Errorf("expected 1 or 2 arguments with --heredoc, but got %d arguments", len(args))
+		}
+	} else if len(args) != 2 {
 		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
 	}
 
-	address := args[0]
-	value := args[1]
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	after, err := cmd.Flags().GetString("after")
+	if err != nil {
+		return err
+	}
+	valueType, err := cmd.Flags().GetString("type")
+	if err != nil {
+		return err
+	}
+	opts, err := getStreamOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	ifValue, err := cmd.Flags().GetString("if-value")
+	if err != nil {
+		return err
+	}
+	skipMismatch, err := cmd.Flags().GetBool("skip-mismatch")
+	if err != nil {
+		return err
+	}
+	if skipMismatch && len(ifValue) == 0 {
+		return fmt.Errorf("--skip-mismatch requires --if-value")
+	}
+	if len(ifValue) != 0 && (len(heredoc) != 0 || len(valueFile) != 0 || len(after) != 0) {
+		return fmt.Errorf("--if-value cannot be used with --heredoc, --value-file or --after")
+	}
 
-	return editor.SetAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), "-", address, value)
+	if len(heredoc) != 0 {
+		if len(after) != 0 {
+			return fmt.Errorf("--heredoc and --after cannot be used together")
+		}
+		if valueType != editor.ValueTypeRaw {
+			return fmt.Errorf("--heredoc and --type cannot be used together")
+		}
+
+		var content string
+		switch {
+		case len(valueFile) != 0:
+			content, err = readFlagFile(cmd, "value-file", valueFile)
+		case len(args) == 2:
+			content = args[1]
+		default:
+			content, err = readFlagFile(cmd, "heredoc", "-")
+		}
+		if err != nil {
+			return err
+		}
+
+		value, err := editor.HeredocValue(content, heredoc, heredocIndent)
+		if err != nil {
+			return err
+		}
+
+		return editor.SetAttributeRawMultiline(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, opts...)
+	}
+
+	if len(valueFile) != 0 {
+		if len(after) != 0 {
+			return fmt.Errorf("--value-file and --after cannot be used together")
+		}
+
+		value, err := readFlagFile(cmd, "value-file", valueFile)
+		if err != nil {
+			return err
+		}
+
+		if valueType != editor.ValueTypeRaw {
+			value, err = editor.TypedValue(strings.TrimRight(value, "\r\n"), valueType)
+			if err != nil {
+				return err
+			}
+		}
+
+		return editor.SetAttributeRawMultiline(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, opts...)
+	}
+
+	var value string
+	if null {
+		if valueType != editor.ValueTypeRaw {
+			return fmt.Errorf("--null and --type cannot be used together")
+		}
+		value = "null"
+	} else {
+		value, err = editor.TypedValue(args[1], valueType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(after) != 0 {
+		return editor.SetAttributeAfter(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, after, opts...)
+	}
+
+	if len(ifValue) != 0 {
+		return editor.SetAttributeIfValue(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, ifValue, skipMismatch, opts...)
+	}
+
+	return editor.SetAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, opts...)
 }
 
 func newAttributeRmCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "rm <ADDRESS>",
+		Use:   "rm <ADDRESS>...",
 		Short: "Remove attribute",
-		Long: `Remove a matched attribute at a given address
+		Long: `Remove matched attributes at given addresses
 
 Arguments:
   ADDRESS          An address of attribute to remove.
+                   Multiple addresses can be given and all of them are
+                   removed in a single pass.
+                   Only one address can be given with --stream.
 `,
 		RunE: runAttributeRmCmd,
 	}
 
+	addStreamFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
 func runAttributeRmCmd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected at least 1 argument, but got %d arguments", len(args))
+	}
+
+	addresses, err := resolveAddresses(cmd, args, true)
+	if err != nil {
+		return err
+	}
+
+	opts, err := getStreamOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	if len(addresses) == 1 {
+		return editor.RemoveAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), addresses[0], opts...)
+	}
+
+	return editor.RemoveAttributes(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), addresses, opts...)
+}
+
+func newAttributeAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit attributes",
+		Long: `Print a TSV of all attributes
+
+Each row consists of a block address, an attribute name, an inferred type and
+a raw value of the attribute. The first row is a header.
+`,
+		RunE: runAttributeAuditCmd,
+	}
+
+	return cmd
+}
+
+func runAttributeAuditCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	return editor.AuditAttributes(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd))
+}
+
+func newAttributeAppendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "append <ADDRESS> <VALUE>",
+		Short: "Append attribute",
+		Long: `Append a new attribute at a given address only if it doesn't exist
+
+Arguments:
+  ADDRESS          An address of attribute to append.
+                   The last element is an attribute name and the rest is
+                   an address of parent blocks.
+  VALUE            A value of the new attribute.
+                   The value is set literally, even if references or expressions.
+                   e.g.) hcledit attribute append aaa.bbb.ccc '"hoge"'
+`,
+		RunE: runAttributeAppendCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("newline", false, "Append a new line before a new attribute")
+	addNormalizeNewlinesFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeAppendCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	value := args[1]
+	newline, err := cmd.Flags().GetBool("newline")
+	if err != nil {
+		return err
+	}
+
+	opts, err := getNormalizeNewlinesOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	return editor.AppendAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, newline, opts...)
+}
+
+func newAttributeMvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mv <FROM_ADDRESS> <TO_ADDRESS>",
+		Short: "Move attribute (Rename attribute)",
+		Long: `Move a matched attribute to a new name or a new block address
+
+Arguments:
+  FROM_ADDRESS     An old address of attribute.
+  TO_ADDRESS       A new address of attribute.
+                   The destination block must exist.
+`,
+		RunE: runAttributeMvCmd,
+	}
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeMvCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	from, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+	// The last segment of the destination is a new name, and the rest is an
+	// address of an existing block.
+	to, err := resolveAddress(cmd, args[1], true)
+	if err != nil {
+		return err
+	}
+
+	return editor.MoveAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), from, to)
+}
+
+// parseVarFlags parses a list of NAME=VALUE strings and returns a map.
+// We don't use the StringToString flag type because it parses values as CSV
+// and discards double quotes which are significant in HCL expressions.
+func parseVarFlags(flags []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, f := range flags {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return nil, fmt.Errorf("failed to parse var flag. expected NAME=VALUE, but got: %s", f)
+		}
+		vars[kv[0]] = kv[1]
+	}
+
+	return vars, nil
+}
+
+func newAttributeExistsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exists <ADDRESS>",
+		Short: "Check if attribute exists",
+		Long: `Check if an attribute exists at a given address
+
+Nothing is written to the output. The result is reported by the exit status.
+It exits with status 0 if the attribute exists, 1 if not found, and 2 for
+other errors such as parse errors.
+An attribute set to a literal null exists unless the --not-null flag is given.
+
+Arguments:
+  ADDRESS          An address of attribute to check.
+                   It can also refer to an element in a value of attribute
+                   such as aaa.bbb[0] or aaa.tags.key.
+`,
+		RunE: runAttributeExistsCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("not-null", false, "Treat an attribute set to a literal null as not found")
+
+	setExitStatus(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeExistsCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	notNull, err := cmd.Flags().GetBool("not-null")
+	if err != nil {
+		return err
+	}
+
+	state, err := editor.GetAttributeState(cmd.InOrStdin(), inputFilename(cmd), address)
+	if err != nil {
+		return err
+	}
+	if state == editor.AttributeAbsent || (notNull && state == editor.AttributeNull) {
+		return &editor.NotFoundError{Address: address}
+	}
+
+	return nil
+}
+
+func newAttributeAddElementCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-element <ADDRESS> <VALUE>",
+		Short: "Append element to list attribute",
+		Long: `Append a value to a list of matched attribute at a given address
+
+The value is inserted after the last element in the same style as the list.
+If the list spans multiple lines, the value is placed on a new line.
+
+Arguments:
+  ADDRESS          An address of attribute whose value is a list.
+                   It can also refer to a list in a value of attribute
+                   such as aaa.bbb[0] or aaa.tags.key.
+  VALUE            A value of the new element.
+                   The value is set literally, even if references or expressions.
+                   e.g.) hcledit attribute add-element aaa.bbb.ccc '"hoge"'
+`,
+		RunE: runAttributeAddElementCmd,
+	}
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeAddElementCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+	value := args[1]
+
+	return editor.AddElement(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value)
+}
+
+func newAttributeRmElementCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm-element <ADDRESS>",
+		Short: "Remove element from list attribute",
+		Long: `Remove elements from a list of matched attribute at a given address
+
+Either --value or --index is required.
+Separators around the removed element are cleaned up.
+
+Arguments:
+  ADDRESS          An address of attribute whose value is a list.
+                   It can also refer to a list in a value of attribute
+                   such as aaa.bbb[0] or aaa.tags.key.
+`,
+		RunE: runAttributeRmElementCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.String("value", "", `Remove all elements equal to a given value.
+A string literal matches with or without quotes. e.g.) --value sg-123`)
+	flags.Int("index", -1, "Remove an element at a given index (0-based)")
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeRmElementCmd(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
 	}
 
-	address := args[0]
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+	value, err := cmd.Flags().GetString("value")
+	if err != nil {
+		return err
+	}
+	index, err := cmd.Flags().GetInt("index")
+	if err != nil {
+		return err
+	}
+
+	hasValue := cmd.Flags().Changed("value")
+	hasIndex := cmd.Flags().Changed("index")
+	if hasValue == hasIndex {
+		return fmt.Errorf("either --value or --index is required")
+	}
+	if hasIndex && index < 0 {
+		return fmt.Errorf("--index must not be negative: %d", index)
+	}
+
+	return editor.RemoveElement(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, value, index)
+}
+
+func newAttributeListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list [<ADDRESS>]",
+		Short: "List attributes",
+		Long: `List names of all attributes in matched blocks at a given address
+
+Names are written one per line in source order. Nested blocks are not listed.
+
+Arguments:
+  ADDRESS          An address of block containing attributes.
+                   It accepts the same address notation as block get --all.
+                   If omitted, attributes in the top level are listed.
+`,
+		RunE: runAttributeListCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("with-value", false, "Print each name with its value such as name = value")
+
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeListCmd(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most 1 argument, but got %d arguments", len(args))
+	}
+
+	address := ""
+	if len(args) == 1 {
+		var err error
+		address, err = resolveAddress(cmd, args[0], false)
+		if err != nil {
+			return err
+		}
+	}
+	withValue, err := cmd.Flags().GetBool("with-value")
+	if err != nil {
+		return err
+	}
+
+	return editor.ListAttributes(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, withValue)
+}
+
+func newAttributeEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <ADDRESS>",
+		Short: "Edit attribute with $EDITOR",
+		Long: `Edit a value of matched attribute at a given address with $EDITOR
+
+The current expression is written to a temporary file, which is opened with
+the command in the EDITOR environment variable, or vi if not set. After the
+editor exits, the edited content is set as a new raw expression, which may
+span multiple lines such as a heredoc.
+It requires --file, because stdin is used by the editor.
+
+Arguments:
+  ADDRESS          An address of attribute to edit.
+`,
+		RunE: runAttributeEditCmd,
+	}
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeEditCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	if readsStdin(cmd) {
+		return fmt.Errorf("attribute edit requires --file, because stdin is used by the editor")
+	}
+
+	parallel, err := cmd.Flags().GetInt("parallel")
+	if err != nil {
+		return err
+	}
+	if parallel > 1 {
+		return fmt.Errorf("attribute edit cannot be used with --parallel")
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	return editor.EditAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, editWithEditor)
+}
+
+// editWithEditor writes a given value to a temporary file, opens it with
+// $EDITOR, and returns the edited content.
+// It returns an error if the editor fails or the content is empty.
+func editWithEditor(value string) (string, error) {
+	f, err := ioutil.TempFile("", "hcledit-*.hcl")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(value + "\n")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temp file: %s", err)
+	}
+
+	command := strings.Fields(os.Getenv("EDITOR"))
+	if len(command) == 0 {
+		command = []string{"vi"}
+	}
+
+	c := exec.Command(command[0], append(command[1:], f.Name())...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("failed to run editor: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %s", err)
+	}
+
+	edited := string(b)
+	if len(strings.TrimSpace(edited)) == 0 {
+		return "", fmt.Errorf("aborted: the edited value is empty")
+	}
+
+	return edited, nil
+}
+
+func newAttributeReplaceCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replace <ADDRESS> <SUBSTITUTION>",
+		Short: "Replace a part of attribute value with regex",
+		Long: `Apply a regex substitution to a value of matched attributes at a given address
+
+The value is edited as text of the expression, and the name and comments of
+the attribute are kept as they are.
+
+Arguments:
+  ADDRESS          An address of attribute to edit.
+  SUBSTITUTION     A substitution in the form of s/pattern/replacement/ like sed.
+                   Any character can be used as a delimiter instead of /, and
+                   the trailing g flag replaces all occurrences.
+                   The pattern is a regular expression in the Go syntax, and
+                   the replacement can refer to submatches such as ${1}.
+                   e.g.) hcledit attribute replace module.vpc.source 's/ref=v1.2.3/ref=v1.3.0/'
+`,
+		RunE: runAttributeReplaceCmd,
+	}
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeReplaceCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+	substitution := args[1]
+
+	return editor.ReplaceAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, substitution)
+}
+
+func newAttributeFilterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter <ADDRESS>",
+		Short: "Filter attribute with external program",
+		Long: `Transform matched attributes at a given address with an external program
+
+Each matched attribute including comments is written to stdin of the program
+given by --filter-exec as an HCL fragment, and the output on stdout replaces
+it. The output may contain multiple attributes, or be empty to remove it.
+This allows you to extend hcledit without forking.
+
+Arguments:
+  ADDRESS          An address of attribute to filter.
+`,
+		RunE: runAttributeFilterCmd,
+	}
+
+	addFilterExecFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runAttributeFilterCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], true)
+	if err != nil {
+		return err
+	}
+
+	fn, err := getFilterExecFunc(cmd)
+	if err != nil {
+		return err
+	}
 
-	return editor.RemoveAttribute(cmd.InOrStdin(), cmd.OutOrStdout(), "-", address)
+	return editor.FilterAttributeFragment(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, fn)
 }
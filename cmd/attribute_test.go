@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 )
 
@@ -12,6 +14,12 @@ func TestAttributeGet(t *testing.T) {
     key    = "services/hoge/dev/terraform.tfstate"
   }
 }
+module "hoge" {
+  env = var.env # managed-by: hcledit
+}
+module "fuga" {
+  env = "fuga"
+}
 `
 
 	cases := []struct {
@@ -26,12 +34,139 @@ func TestAttributeGet(t *testing.T) {
 			ok:   true,
 			want: "\"services/hoge/dev/terraform.tfstate\"\n",
 		},
+		{
+			name: "recursive descent",
+			args: []string{"**.region"},
+			ok:   true,
+			want: "\"ap-northeast-1\"\n",
+		},
+		{
+			name: "ambiguous recursive descent",
+			args: []string{"**.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "resolve variable",
+			args: []string{"--var", "env=\"dev\"", "module.hoge.env"},
+			ok:   true,
+			want: "\"dev\"\n",
+		},
+		{
+			name: "json",
+			args: []string{"--output", "json", "module.hoge.env"},
+			ok:   true,
+			want: `[
+  {
+    "address": "module.hoge.env",
+    "value": "var.env",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 9,
+        "column": 3,
+        "byte": 168
+      },
+      "end": {
+        "line": 9,
+        "column": 16,
+        "byte": 181
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "json no match",
+			args: []string{"--output", "json", "hoge"},
+			ok:   true,
+			want: "[]\n",
+		},
+		{
+			name: "template",
+			args: []string{"--template", "{{.Address}}={{.Value}}", "module.hoge.env"},
+			ok:   true,
+			want: "module.hoge.env=var.env",
+		},
+		{
+			name: "invalid template",
+			args: []string{"--template", "{{.Address", "module.hoge.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "template with range",
+			args: []string{"--template", "{{.Address}}\t{{.Value}}\t{{.Range.Start.Line}}\n", "module.hoge.env"},
+			ok:   true,
+			want: "module.hoge.env\tvar.env\t9\n",
+		},
+		{
+			name: "raw",
+			args: []string{"--raw", "terraform.backend.s3.key"},
+			ok:   true,
+			want: "services/hoge/dev/terraform.tfstate\n",
+		},
+		{
+			name: "evaluate",
+			args: []string{"--evaluate", "--var", "env=\"dev\"", "module.hoge.env"},
+			ok:   true,
+			want: "\"dev\"\n",
+		},
+		{
+			name: "evaluate with raw",
+			args: []string{"--evaluate", "--raw", "--var", "env=\"${\"d\"}ev\"", "module.hoge.env"},
+			ok:   true,
+			want: "dev\n",
+		},
+		{
+			name: "raw with comments",
+			args: []string{"--raw", "--with-comments", "terraform.backend.s3.key"},
+			ok:   true,
+			want: "services/hoge/dev/terraform.tfstate\n",
+		},
+		{
+			name: "raw with var and comments",
+			args: []string{"--raw", "--var", "env=\"dev\"", "--with-comments", "module.hoge.env"},
+			ok:   true,
+			want: "dev # managed-by: hcledit\n",
+		},
+		{
+			name: "raw with template",
+			args: []string{"--raw", "--template", "{{.Value}}", "terraform.backend.s3.key"},
+			ok:   true,
+			want: "services/hoge/dev/terraform.tfstate",
+		},
+		{
+			name: "template with json",
+			args: []string{"--template", "{{.Value}}", "--output", "json", "module.hoge.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "with comments",
+			args: []string{"--with-comments", "module.hoge.env"},
+			ok:   true,
+			want: "var.env # managed-by: hcledit\n",
+		},
+		{
+			name: "invalid var flag",
+			args: []string{"--var", "env", "module.hoge.env"},
+			ok:   false,
+			want: "",
+		},
 		{
 			name: "no match",
 			args: []string{"hoge"},
 			ok:   true,
 			want: "",
 		},
+		{
+			name: "no match in strict mode",
+			args: []string{"--strict", "hoge"},
+			ok:   false,
+			want: "",
+		},
 		{
 			name: "no args",
 			args: []string{},
@@ -39,8 +174,47 @@ func TestAttributeGet(t *testing.T) {
 			want: "",
 		},
 		{
-			name: "too many args",
-			args: []string{"hoge", "fuga"},
+			name: "multiple addresses",
+			args: []string{"module.hoge.env", "hoge", "terraform.backend.s3.region"},
+			ok:   true,
+			want: `module.hoge.env=var.env
+terraform.backend.s3.region="ap-northeast-1"
+`,
+		},
+		{
+			name: "multiple addresses in strict mode",
+			args: []string{"--strict", "module.hoge.env", "hoge"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "multiple addresses in json",
+			args: []string{"--output", "json", "module.hoge.env", "hoge"},
+			ok:   true,
+			want: `[
+  {
+    "address": "module.hoge.env",
+    "value": "var.env",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 9,
+        "column": 3,
+        "byte": 168
+      },
+      "end": {
+        "line": 9,
+        "column": 16,
+        "byte": 181
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "multiple addresses with raw",
+			args: []string{"--raw", "module.hoge.env", "hoge"},
 			ok:   false,
 			want: "",
 		},
@@ -48,9 +222,10 @@ func TestAttributeGet(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runAttributeGetCmd, src)
+			cmd := setMockStreams(newAttributeGetCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runAttributeGetCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -122,6 +297,157 @@ module "hoge" {
 }
 `,
 		},
+		{
+			name: "stream",
+			args: []string{"--stream", "module.hoge.env", "var.env"},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source = "./hoge"
+  env    = var.env
+}
+`,
+		},
+		{
+			name: "stream is not supported with after",
+			args: []string{"--stream", "--after", "source", "module.hoge.version", `"1.0.0"`},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "create after anchor",
+			args: []string{"--after", "source", "module.hoge.version", `"1.0.0"`},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source  = "./hoge"
+  version = "1.0.0"
+  env     = "dev"
+}
+`,
+		},
+		{
+			name: "typed string",
+			args: []string{"--type", "string", "module.hoge.env", "prod"},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source = "./hoge"
+  env    = "prod"
+}
+`,
+		},
+		{
+			name: "typed list",
+			args: []string{"--type", "list", "module.hoge.env", `["dev","prod"]`},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source = "./hoge"
+  env    = ["dev", "prod"]
+}
+`,
+		},
+		{
+			name: "invalid typed value",
+			args: []string{"--type", "number", "module.hoge.env", "prod"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "null",
+			args: []string{"--null", "module.hoge.env"},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source = "./hoge"
+  env    = null
+}
+`,
+		},
+		{
+			name: "null with value",
+			args: []string{"--null", "module.hoge.env", "var.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "null with type",
+			args: []string{"--null", "--type", "string", "module.hoge.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "if value matches",
+			args: []string{"--if-value", `"dev"`, "module.hoge.env", `"prod"`},
+			ok:   true,
+			want: `terraform {
+  backend "s3" {
+    region = "ap-northeast-1"
+    bucket = "minamijoyo-hcledit"
+    key    = "services/hoge/dev/terraform.tfstate"
+  }
+}
+module "hoge" {
+  source = "./hoge"
+  env    = "prod"
+}
+`,
+		},
+		{
+			name: "if value doesn't match",
+			args: []string{"--if-value", `"stg"`, "module.hoge.env", `"prod"`},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "if value doesn't match with skip mismatch",
+			args: []string{"--if-value", `"stg"`, "--skip-mismatch", "module.hoge.env", `"prod"`},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "skip mismatch without if value",
+			args: []string{"--skip-mismatch", "module.hoge.env", `"prod"`},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "if value with after",
+			args: []string{"--if-value", `"dev"`, "--after", "source", "module.hoge.env", `"prod"`},
+			ok:   false,
+			want: "",
+		},
 		{
 			name: "no match",
 			args: []string{"hoge", "fuga"},
@@ -150,9 +476,10 @@ module "hoge" {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runAttributeGetCmd, src)
+			cmd := setMockStreams(newAttributeSetCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runAttributeSetCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -170,43 +497,969 @@ module "hoge" {
 	}
 }
 
-func TestAttributeRm(t *testing.T) {
-	src := `locals {
-  service = "hoge"
-  env     = "dev"
-  region  = "ap-northeast-1"
-}`
+func TestAttributeSetValueFile(t *testing.T) {
+	src := `resource "aws_iam_policy" "hoge" {
+  name   = "hoge"
+  policy = ""
+}
+`
+
+	heredoc := `<<-EOT
+{
+  "Version": "2012-10-17"
+}
+EOT
+`
 
 	cases := []struct {
-		name string
-		args []string
-		ok   bool
-		want string
+		name    string
+		args    []string
+		content string
+		ok      bool
+		want    string
 	}{
 		{
-			name: "remove an unused local variable",
-			args: []string{"locals.region"},
-			ok:   true,
-			want: `locals {
-  service = "hoge"
-  env     = "dev"
-}`,
+			name:    "heredoc",
+			args:    []string{"resource.aws_iam_policy.hoge.policy"},
+			content: heredoc,
+			ok:      true,
+			want: `resource "aws_iam_policy" "hoge" {
+  name   = "hoge"
+  policy = <<-EOT
+    {
+      "Version": "2012-10-17"
+    }
+  EOT
+}
+`,
 		},
 		{
-			name: "no match",
-			args: []string{"hoge"},
-			ok:   true,
-			want: src,
+			name:    "typed map",
+			args:    []string{"--type", "map", "resource.aws_iam_policy.hoge.policy"},
+			content: "{\"Version\": \"2012-10-17\"}\n",
+			ok:      true,
+			want: `resource "aws_iam_policy" "hoge" {
+  name   = "hoge"
+  policy = { Version = "2012-10-17" }
+}
+`,
 		},
 		{
-			name: "no args",
-			args: []string{},
-			ok:   false,
-			want: "",
+			name:    "wrap in heredoc",
+			args:    []string{"--heredoc", "POLICY", "resource.aws_iam_policy.hoge.policy"},
+			content: "{\n  \"Version\": \"2012-10-17\"\n}\n",
+			ok:      true,
+			want: `resource "aws_iam_policy" "hoge" {
+  name   = "hoge"
+  policy = <<POLICY
+{
+  "Version": "2012-10-17"
+}
+POLICY
+}
+`,
 		},
 		{
-			name: "too many args",
-			args: []string{"hoge", "fuga"},
+			name:    "wrap in indented heredoc",
+			args:    []string{"--heredoc", "POLICY", "--heredoc-indent", "resource.aws_iam_policy.hoge.policy"},
+			content: "{\n  \"Version\": \"2012-10-17\"\n}\n",
+			ok:      true,
+			want: `resource "aws_iam_policy" "hoge" {
+  name   = "hoge"
+  policy = <<-POLICY
+    {
+      "Version": "2012-10-17"
+    }
+  POLICY
+}
+`,
+		},
+		{
+			name:    "heredoc delimiter in content",
+			args:    []string{"--heredoc", "EOT", "resource.aws_iam_policy.hoge.policy"},
+			content: heredoc,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "with value arg",
+			args:    []string{"resource.aws_iam_policy.hoge.policy", `"hoge"`},
+			content: heredoc,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "with after",
+			args:    []string{"--after", "name", "resource.aws_iam_policy.hoge.policy"},
+			content: heredoc,
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, cleanup := newTempFile(t, tc.content)
+			defer cleanup()
+
+			cmd := setMockStreams(newAttributeSetCmd(), src)
+			cmd.SetArgs(append([]string{"--value-file", path}, tc.args...))
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeSetHeredoc(t *testing.T) {
+	src := `locals {
+  script = "" # generated
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "value arg",
+			args: []string{"--heredoc", "EOF", "locals.script", "echo foo\necho bar\n"},
+			ok:   true,
+			want: `locals {
+  # generated
+  script = <<EOF
+echo foo
+echo bar
+EOF
+}
+`,
+		},
+		{
+			name: "invalid delimiter",
+			args: []string{"--heredoc", "E OF", "locals.script", "echo foo"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "with type",
+			args: []string{"--heredoc", "EOF", "--type", "string", "locals.script", "echo foo"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "indent without heredoc",
+			args: []string{"--heredoc-indent", "locals.script", "echo foo"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "with null",
+			args: []string{"--heredoc", "EOF", "--null", "locals.script"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeSetCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeRm(t *testing.T) {
+	src := `locals {
+  service = "hoge"
+  env     = "dev"
+  region  = "ap-northeast-1"
+}`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "remove an unused local variable",
+			args: []string{"locals.region"},
+			ok:   true,
+			want: `locals {
+  service = "hoge"
+  env     = "dev"
+}`,
+		},
+		{
+			name: "no match",
+			args: []string{"hoge"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "multiple addresses",
+			args: []string{"locals.region", "hoge", "locals.service"},
+			ok:   true,
+			want: `locals {
+  env = "dev"
+}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeRmCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeAudit(t *testing.T) {
+	src := `locals {
+  service = "hoge"
+  port    = 80
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{},
+			ok:   true,
+			want: "address\tname\ttype\tvalue\n" +
+				"locals\tservice\tstring\t\"hoge\"\n" +
+				"locals\tport\tnumber\t80\n",
+		},
+		{
+			name: "too many args",
+			args: []string{"hoge"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runAttributeAuditCmd, src)
+
+			err := runAttributeAuditCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeAppend(t *testing.T) {
+	src := `terraform {
+  required_version = "0.12.18"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"terraform.experiments", "[]"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+  experiments      = []
+}
+`,
+		},
+		{
+			name: "with newline",
+			args: []string{"--newline", "terraform.experiments", "[]"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+
+  experiments = []
+}
+`,
+		},
+		{
+			name: "with newline and normalize newlines",
+			args: []string{"--newline", "--normalize-newlines", "experiments", "[]"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+}
+
+experiments = []
+`,
+		},
+		{
+			name: "existing attribute",
+			args: []string{"terraform.required_version", `"0.13.0"`},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{"hoge"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeAppendCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeMv(t *testing.T) {
+	src := `locals {
+  foo1 = "bar1"
+  foo2 = "bar2"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"locals.foo1", "locals.foo3"},
+			ok:   true,
+			want: `locals {
+  foo3 = "bar1"
+  foo2 = "bar2"
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"locals.hoge", "locals.fuga"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{"hoge"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"hoge", "fuga", "piyo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runAttributeMvCmd, src)
+
+			err := runAttributeMvCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeAddElement(t *testing.T) {
+	src := `locals {
+  foo = ["bar1"]
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"locals.foo", `"bar2"`},
+			ok:   true,
+			want: `locals {
+  foo = ["bar1", "bar2"]
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"locals.hoge", `"bar2"`},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "1 arg",
+			args: []string{"locals.foo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runAttributeAddElementCmd, src)
+
+			err := runAttributeAddElementCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeRmElement(t *testing.T) {
+	src := `locals {
+  foo = ["bar1", "bar2", "bar3"]
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "by value",
+			args: []string{"locals.foo", "--value", "bar2"},
+			ok:   true,
+			want: `locals {
+  foo = ["bar1", "bar3"]
+}
+`,
+		},
+		{
+			name: "by index",
+			args: []string{"locals.foo", "--index", "0"},
+			ok:   true,
+			want: `locals {
+  foo = ["bar2", "bar3"]
+}
+`,
+		},
+		{
+			name: "no flags",
+			args: []string{"locals.foo"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "both flags",
+			args: []string{"locals.foo", "--value", "bar2", "--index", "0"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "negative index",
+			args: []string{"locals.foo", "--index", "-1"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeRmElementCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeExists(t *testing.T) {
+	src := `module "hoge" {
+  source  = "./hoge"
+  version = null
+  subnets = ["subnet-1", null]
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+	}{
+		{
+			name: "present",
+			args: []string{"module.hoge.source"},
+			ok:   true,
+		},
+		{
+			name: "null",
+			args: []string{"module.hoge.version"},
+			ok:   true,
+		},
+		{
+			name: "null with not-null",
+			args: []string{"--not-null", "module.hoge.version"},
+			ok:   false,
+		},
+		{
+			name: "present with not-null",
+			args: []string{"--not-null", "module.hoge.source"},
+			ok:   true,
+		},
+		{
+			name: "absent",
+			args: []string{"module.hoge.env"},
+			ok:   false,
+		},
+		{
+			name: "element of list",
+			args: []string{"module.hoge.subnets[0]"},
+			ok:   true,
+		},
+		{
+			name: "null element with not-null",
+			args: []string{"--not-null", "module.hoge.subnets[1]"},
+			ok:   false,
+		},
+		{
+			name: "element out of range",
+			args: []string{"module.hoge.subnets[2]"},
+			ok:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeExistsCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if stdout := mockOut(cmd); stdout != "" {
+				t.Fatalf("expected no output, but got:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestAttributeList(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "names",
+			args: []string{"resource.aws_instance.web"},
+			ok:   true,
+			want: `ami
+instance_type
+`,
+		},
+		{
+			name: "with value",
+			args: []string{"--with-value", "resource.aws_instance.web"},
+			ok:   true,
+			want: `ami = "ami-123"
+instance_type = "t3.micro"
+`,
+		},
+		{
+			name: "too many args",
+			args: []string{"resource.aws_instance.web", "foo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeListCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeEdit(t *testing.T) {
+	src := `locals {
+  env = "dev"
+}
+`
+
+	// a fake editor which replaces dev with prod in a given file.
+	script, cleanupScript := newTempFile(t, "#!/bin/sh\nsed 's/dev/prod/' \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n")
+	defer cleanupScript()
+	if err := os.Chmod(script, 0755); err != nil {
+		t.Fatalf("failed to chmod: %s", err)
+	}
+
+	cases := []struct {
+		name   string
+		editor string
+		args   []string
+		ok     bool
+		file   string
+	}{
+		{
+			name:   "update",
+			editor: script,
+			args:   []string{"attribute", "edit", "-f", "FILE", "-u", "locals.env"},
+			ok:     true,
+			file: `locals {
+  env = "prod"
+}
+`,
+		},
+		{
+			name:   "editor fails",
+			editor: "false",
+			args:   []string{"attribute", "edit", "-f", "FILE", "-u", "locals.env"},
+			ok:     false,
+			file:   src,
+		},
+		{
+			name:   "not found",
+			editor: script,
+			args:   []string{"attribute", "edit", "-f", "FILE", "-u", "locals.foo"},
+			ok:     false,
+			file:   src,
+		},
+		{
+			name:   "stdin",
+			editor: script,
+			args:   []string{"attribute", "edit", "locals.env"},
+			ok:     false,
+			file:   src,
+		},
+	}
+
+	editor := os.Getenv("EDITOR")
+	defer os.Setenv("EDITOR", editor)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv("EDITOR", tc.editor)
+			path, cleanup := newTempFile(t, src)
+			defer cleanup()
+			args := []string{}
+			for _, arg := range tc.args {
+				if arg == "FILE" {
+					arg = path
+				}
+				args = append(args, arg)
+			}
+
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd())
+			setMockStreams(cmd, src)
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			file, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %s", err)
+			}
+			if string(file) != tc.file {
+				t.Fatalf("got file:\n%s\nwant:\n%s", string(file), tc.file)
+			}
+		})
+	}
+}
+
+func TestAttributeReplace(t *testing.T) {
+	src := `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"module.vpc.source", "s/ref=v1.2.3/ref=v1.3.0/"},
+			ok:   true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.3.0"
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"module.vpc.source", "s/hoge/fuga/"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "invalid substitution",
+			args: []string{"module.vpc.source", "ref=v1.3.0"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{"module.vpc.source"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"module.vpc.source", "s/a/b/", "s/c/d/"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newAttributeReplaceCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeFilter(t *testing.T) {
+	src := `b1 {
+  a1 = "v1"
+  a2 = "v1"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"--filter-exec", "sed s/v1/v2/", "b1.a1"},
+			ok:   true,
+			want: `b1 {
+  a1 = "v2"
+  a2 = "v1"
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"--filter-exec", "sed s/v1/v2/", "b1.a3"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "program fails",
+			args: []string{"--filter-exec", "false", "b1.a1"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no filter exec",
+			args: []string{"b1.a1"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{"--filter-exec", "cat"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"--filter-exec", "cat", "b1.a1", "b1.a2"},
 			ok:   false,
 			want: "",
 		},
@@ -214,9 +1467,10 @@ func TestAttributeRm(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runAttributeGetCmd, src)
+			cmd := setMockStreams(newAttributeFilterCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runAttributeRmCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -25,6 +25,13 @@ func newBlockCmd() *cobra.Command {
 		newBlockMvCmd(),
 		newBlockListCmd(),
 		newBlockRmCmd(),
+		newBlockRenameCmd(),
+		newBlockWrapCmd(),
+		newBlockLabelsCmd(),
+		newBlockAppendCmd(),
+		newBlockExistsCmd(),
+		newBlockCountCmd(),
+		newBlockFilterCmd(),
 	)
 
 	return cmd
@@ -42,6 +49,16 @@ Arguments:
 		RunE: runBlockGetCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.Bool("addresses-only", false, "Print addresses of matched blocks instead of their contents")
+	flags.Bool("all", false, `Print all matched blocks including nested blocks separated by blank lines.
+A wildcard, a regular expression, an index and a recursive descent can be used in the address.`)
+	addWhereFlag(cmd)
+	addOutputFlag(cmd)
+	addTemplateFlag(cmd)
+
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
@@ -50,9 +67,59 @@ func runBlockGetCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
 	}
 
-	address := args[0]
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	addressesOnly, err := cmd.Flags().GetBool("addresses-only")
+	if err != nil {
+		return err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	output, err := getOutputFlag(cmd)
+	if err != nil {
+		return err
+	}
+	tmpl, err := getTemplateFlag(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := getWhereOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	if tmpl != nil {
+		if all || addressesOnly || output == outputJSON {
+			return fmt.Errorf("--template cannot be used with --all, --addresses-only or --output json")
+		}
+		opts = append(opts, editor.WithSink(editor.NewTemplateSink(tmpl)))
+		return editor.GetBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
+	}
+
+	if all {
+		if addressesOnly || output == outputJSON {
+			return fmt.Errorf("--all cannot be used with --addresses-only or --output json")
+		}
+		return editor.GetBlockAll(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
+	}
+
+	if output == outputJSON {
+		if addressesOnly {
+			return fmt.Errorf("--output json cannot be used with --addresses-only")
+		}
+		return editor.GetBlockJSON(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
+	}
 
-	return editor.GetBlock(cmd.InOrStdin(), cmd.OutOrStdout(), "-", address)
+	if addressesOnly {
+		return editor.GetBlockAddresses(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
+	}
+
+	return editor.GetBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, opts...)
 }
 
 func newBlockMvCmd() *cobra.Command {
@@ -64,10 +131,19 @@ func newBlockMvCmd() *cobra.Command {
 Arguments:
   FROM_ADDRESS     An old address of block.
   TO_ADDRESS       A new address of block.
+                   If the --into flag is given, an address of a destination
+                   block into which matched blocks are relocated.
 `,
 		RunE: runBlockMvCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.Bool("into", false, `Relocate matched blocks including nested blocks with their comments
+into the body of the destination block instead of renaming them`)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
@@ -76,28 +152,116 @@ func runBlockMvCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
 	}
 
-	from := args[0]
+	from, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
 	to := args[1]
+	into, err := cmd.Flags().GetBool("into")
+	if err != nil {
+		return err
+	}
+
+	if into {
+		// The destination is an existing block. Otherwise it is a new address.
+		to, err = resolveAddress(cmd, to, false)
+		if err != nil {
+			return err
+		}
+		return editor.MoveBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), from, to)
+	}
 
-	return editor.RenameBlock(cmd.InOrStdin(), cmd.OutOrStdout(), "-", from, to)
+	return editor.RenameBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), from, to)
 }
 
 func newBlockListCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "list",
+		Use:   "list [<ADDRESS>]",
 		Short: "List block",
-		RunE:  runBlockListCmd,
+		Long: `List addresses of blocks
+
+Arguments:
+  ADDRESS          An optional address of blocks to list such as resource.aws_instance.*.
+                   If omitted, all blocks at the top level are listed.
+`,
+		RunE: runBlockListCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.Bool("positions", false, "Print file:line:column and byte offsets of each block before its address")
+	flags.String("type", "", "List only blocks of a given type such as resource")
+	flags.StringArray("label", nil, `List only blocks whose leading labels match given labels in order.
+A wildcard (*) or a regular expression such as /aws_.*/ can be used. It can be given multiple times`)
+	addWhereFlag(cmd)
+	addOutputFlag(cmd)
+	addTemplateFlag(cmd)
+
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
 func runBlockListCmd(cmd *cobra.Command, args []string) error {
-	if len(args) != 0 {
-		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most 1 argument, but got %d arguments", len(args))
 	}
 
-	return editor.ListBlock(cmd.InOrStdin(), cmd.OutOrStdout(), "-")
+	positions, err := cmd.Flags().GetBool("positions")
+	if err != nil {
+		return err
+	}
+	output, err := getOutputFlag(cmd)
+	if err != nil {
+		return err
+	}
+	tmpl, err := getTemplateFlag(cmd)
+	if err != nil {
+		return err
+	}
+	blockType, err := cmd.Flags().GetString("type")
+	if err != nil {
+		return err
+	}
+	labels, err := cmd.Flags().GetStringArray("label")
+	if err != nil {
+		return err
+	}
+
+	opts := []editor.Option{}
+	if len(args) == 1 {
+		address, err := resolveAddress(cmd, args[0], false)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, editor.WithFilters(editor.NewBlockAddressFilter(address)))
+	}
+	if len(blockType) != 0 || len(labels) != 0 {
+		opts = append(opts, editor.WithFilters(editor.NewBlockTypeFilter(blockType, labels)))
+	}
+	whereOpts, err := getWhereOptions(cmd)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, whereOpts...)
+
+	if tmpl != nil {
+		if positions || output == outputJSON {
+			return fmt.Errorf("--template cannot be used with --positions or --output json")
+		}
+		opts = append(opts, editor.WithSink(editor.NewTemplateSink(tmpl)))
+		return editor.ListBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), opts...)
+	}
+
+	if output == outputJSON {
+		// positions are always included in JSON.
+		return editor.ListBlockJSON(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), opts...)
+	}
+
+	if positions {
+		return editor.ListBlockWithPositions(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), opts...)
+	}
+
+	return editor.ListBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), opts...)
 }
 
 func newBlockRmCmd() *cobra.Command {
@@ -112,6 +276,14 @@ Arguments:
 		RunE: runBlockRmCmd,
 	}
 
+	flags := cmd.Flags()
+	flags.Bool("keep-body", false, `Remove only the header and braces of matched blocks, and hoist
+their attributes and nested blocks into the parent body.
+Nested blocks can be addressed as well as attribute get.`)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
 	return cmd
 }
 
@@ -120,7 +292,387 @@ func runBlockRmCmd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
 	}
 
-	address := args[0]
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	keepBody, err := cmd.Flags().GetBool("keep-body")
+	if err != nil {
+		return err
+	}
+
+	if keepBody {
+		return editor.UnwrapBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address)
+	}
+
+	return editor.RemoveBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address)
+}
+
+func newBlockRenameCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rename <ADDRESS> <NEW_LABELS>",
+		Short: "Rename labels of block",
+		Long: `Rename only labels of a matched block at a given address
+
+The block type and the body are kept as they are.
+
+Arguments:
+  ADDRESS          An address of block to rename.
+  NEW_LABELS       New labels of block joined with dots.
+                   e.g.) hcledit block rename resource.aws_instance.web aws_instance.app
+`,
+		RunE: runBlockRenameCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("moved", false, `Append a Terraform moved block from the old address to the new one.
+Only resource and module blocks are supported`)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockRenameCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+	labels := args[1]
+	moved, err := cmd.Flags().GetBool("moved")
+	if err != nil {
+		return err
+	}
+
+	if moved {
+		return editor.RenameBlockLabelsWithMoved(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, labels)
+	}
+
+	return editor.RenameBlockLabels(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, labels)
+}
+
+func newBlockWrapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wrap <ADDRESS>...",
+		Short: "Wrap attributes and blocks into a new block",
+		Long: `Move matched attributes and blocks at given addresses into a new nested block
+
+A new block is created in each parent body of matched items at the position
+of the first item. This is the inverse of block rm --keep-body.
+
+Arguments:
+  ADDRESS          An address of attribute or block to wrap.
+                   Multiple addresses can be given.
+`,
+		RunE: runBlockWrapCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.String("into", "", "An address of a new block relative to the parent such as timeouts or dynamic.ingress (required)")
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockWrapCmd(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("expected at least 1 argument, but got %d arguments", len(args))
+	}
+
+	into, err := cmd.Flags().GetString("into")
+	if err != nil {
+		return err
+	}
+	if len(into) == 0 {
+		return fmt.Errorf("--into is required")
+	}
+
+	// An address may refer to either an attribute or a block, so each address
+	// is resolved both ways. An item matched by both is wrapped only once.
+	addresses, err := resolveAddresses(cmd, args, true)
+	if err != nil {
+		return err
+	}
+	blocks, err := resolveAddresses(cmd, args, false)
+	if err != nil {
+		return err
+	}
+	addresses = append(addresses, blocks...)
+
+	return editor.WrapBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), addresses, into)
+}
+
+func newBlockLabelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "labels <TYPE>",
+		Short: "List labels of block",
+		Long: `List labels of all top level blocks of a given type
+
+Each line corresponds to a block and labels are joined with tabs.
+A block without labels is printed as an empty line.
+
+Arguments:
+  TYPE             A type of block.
+`,
+		RunE: runBlockLabelsCmd,
+	}
+
+	return cmd
+}
+
+func runBlockLabelsCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	blockType := args[0]
+
+	return editor.GetBlockLabels(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), blockType)
+}
+
+func newBlockAppendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "append <PARENT_ADDRESS> <CHILD_ADDRESS>",
+		Short: "Append block",
+		Long: `Append a new child block to matched blocks at a given parent address
+
+Arguments:
+  PARENT_ADDRESS   An address of the parent block.
+                   If empty, the new block is appended to the top level.
+  CHILD_ADDRESS    A new block address relative to the parent block.
+`,
+		RunE: runBlockAppendCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("newline", false, "Append a new line before a new child block")
+	flags.String("comment", "", "A leading comment of a new child block")
+	flags.String("body-file", "", `Read an HCL snippet of the body of a new child block from a given file.
+The - means stdin, which requires --file for the HCL input.`)
+	flags.String("before", "", "Insert a new child block before a given block address relative to the parent")
+	flags.String("after", "", "Insert a new child block after a given block address relative to the parent")
+	addNormalizeNewlinesFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockAppendCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	parent, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	child := args[1]
+	newline, err := cmd.Flags().GetBool("newline")
+	if err != nil {
+		return err
+	}
+
+	comment, err := cmd.Flags().GetString("comment")
+	if err != nil {
+		return err
+	}
+
+	bodyFile, err := cmd.Flags().GetString("body-file")
+	if err != nil {
+		return err
+	}
+
+	before, err := cmd.Flags().GetString("before")
+	if err != nil {
+		return err
+	}
+
+	after, err := cmd.Flags().GetString("after")
+	if err != nil {
+		return err
+	}
+
+	if len(before) != 0 && len(after) != 0 {
+		return fmt.Errorf("--before and --after cannot be used together")
+	}
+
+	before, err = resolveAddress(cmd, before, false)
+	if err != nil {
+		return err
+	}
+
+	after, err = resolveAddress(cmd, after, false)
+	if err != nil {
+		return err
+	}
+
+	opts, err := getNormalizeNewlinesOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	body := ""
+	if len(bodyFile) != 0 {
+		body, err = readFlagFile(cmd, "body-file", bodyFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(before) != 0 {
+		return editor.AppendBlockAt(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), parent, child, body, before, false, newline, comment, opts...)
+	}
+
+	if len(after) != 0 {
+		return editor.AppendBlockAt(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), parent, child, body, after, true, newline, comment, opts...)
+	}
+
+	if len(bodyFile) != 0 {
+		return editor.AppendBlockWithBody(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), parent, child, body, newline, comment, opts...)
+	}
+
+	return editor.AppendBlock(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), parent, child, newline, comment, opts...)
+}
+
+func newBlockExistsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "exists <ADDRESS>",
+		Short: "Check if block exists",
+		Long: `Check if at least one block matches a given address
+
+Nothing is written to the output. The result is reported by the exit status.
+It exits with status 0 if a block matches, 1 if not found, and 2 for other
+errors such as parse errors.
+
+Arguments:
+  ADDRESS          An address of block to check.
+                   It accepts the same address notation as block get --all.
+`,
+		RunE: runBlockExistsCmd,
+	}
+
+	setExitStatus(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockExistsCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	found, err := editor.HasBlock(cmd.InOrStdin(), inputFilename(cmd), address)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return &editor.NotFoundError{Address: args[0]}
+	}
+
+	return nil
+}
+
+func newBlockCountCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "count <ADDRESS>",
+		Short: "Count blocks",
+		Long: `Print the number of blocks which match a given address
+
+The result is also reported by the exit status. It exits with status 0 if a
+block matches, 1 if not found, and 2 for other errors such as parse errors.
+
+Arguments:
+  ADDRESS          An address of block to count.
+                   It accepts the same address notation as block get --all.
+`,
+		RunE: runBlockCountCmd,
+	}
+
+	setExitStatus(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockCountCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	count, err := editor.CountBlocks(cmd.InOrStdin(), inputFilename(cmd), address)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), count)
+	if count == 0 {
+		return &editor.NotFoundError{Address: args[0]}
+	}
+
+	return nil
+}
+
+func newBlockFilterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "filter <ADDRESS>",
+		Short: "Filter block with external program",
+		Long: `Transform matched blocks at a given address with an external program
+
+Each matched block is written to stdin of the program given by --filter-exec
+as an HCL fragment, and the output on stdout replaces it. The output may
+contain multiple blocks, or be empty to remove it.
+This allows you to extend hcledit without forking.
+
+Arguments:
+  ADDRESS          An address of block to filter.
+`,
+		RunE: runBlockFilterCmd,
+	}
+
+	addFilterExecFlag(cmd)
+
+	setUpdatable(cmd)
+	setIgnoreCase(cmd)
+
+	return cmd
+}
+
+func runBlockFilterCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address, err := resolveAddress(cmd, args[0], false)
+	if err != nil {
+		return err
+	}
+
+	fn, err := getFilterExecFunc(cmd)
+	if err != nil {
+		return err
+	}
 
-	return editor.RemoveBlock(cmd.InOrStdin(), cmd.OutOrStdout(), "-", address)
+	return editor.FilterBlockFragment(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, fn)
 }
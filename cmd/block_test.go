@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -30,6 +31,93 @@ provider "aws" {
 }
 `,
 		},
+		{
+			name: "where",
+			args: []string{"--where", `region == "ap-northeast-1"`, "--addresses-only", "provider.*"},
+			ok:   true,
+			want: "provider.aws\n",
+		},
+		{
+			name: "where no match",
+			args: []string{"--where", `region == "us-east-1"`, "provider.*"},
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "invalid where",
+			args: []string{"--where", `region ==`, "provider.*"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "addresses only",
+			args: []string{"--addresses-only", "provider.aws"},
+			ok:   true,
+			want: "provider.aws\n",
+		},
+		{
+			name: "template",
+			args: []string{"--template", "{{.Address}} {{index .Labels 0}} {{.Range.Start.Line}}\n", "provider.aws"},
+			ok:   true,
+			want: "provider.aws aws 5\n",
+		},
+		{
+			name: "template with addresses only",
+			args: []string{"--template", "{{.Address}}", "--addresses-only", "provider.aws"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "json",
+			args: []string{"--output", "json", "terraform"},
+			ok:   true,
+			want: `[
+  {
+    "address": "terraform",
+    "value": "terraform {\n  required_version = \"0.12.18\"\n}",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 1,
+        "column": 1,
+        "byte": 0
+      },
+      "end": {
+        "line": 3,
+        "column": 2,
+        "byte": 44
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "all",
+			args: []string{"--all", "/.*/"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+}
+
+provider "aws" {
+  version = "2.43.0"
+  region  = "ap-northeast-1"
+}
+`,
+		},
+		{
+			name: "all with addresses only",
+			args: []string{"--all", "--addresses-only", "terraform"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "json with addresses only",
+			args: []string{"--output", "json", "--addresses-only", "terraform"},
+			ok:   false,
+			want: "",
+		},
 		{
 			name: "no match",
 			args: []string{"hoge"},
@@ -52,9 +140,10 @@ provider "aws" {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runBlockGetCmd, src)
+			cmd := setMockStreams(newBlockGetCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runBlockGetCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -75,6 +164,9 @@ provider "aws" {
 func TestBlockMv(t *testing.T) {
 	src := `resource "aws_security_group" "test1" {
   name = "tfedit-test1"
+  ingress {
+    from_port = 80
+  }
 }
 
 resource "aws_security_group" "test2" {
@@ -94,13 +186,38 @@ resource "aws_security_group" "test2" {
 			ok:   true,
 			want: `resource "aws_security_group" "test3" {
   name = "tfedit-test1"
+  ingress {
+    from_port = 80
+  }
+}
+
+resource "aws_security_group" "test2" {
+  name = "tfedit-test2"
+}
+`,
+		},
+		{
+			name: "into",
+			args: []string{"--into", "resource.aws_security_group.test1.ingress", "resource.aws_security_group.test2"},
+			ok:   true,
+			want: `resource "aws_security_group" "test1" {
+  name = "tfedit-test1"
 }
 
 resource "aws_security_group" "test2" {
   name = "tfedit-test2"
+  ingress {
+    from_port = 80
+  }
 }
 `,
 		},
+		{
+			name: "into not found",
+			args: []string{"--into", "resource.aws_security_group.test1.ingress", "resource.aws_security_group.test3"},
+			ok:   false,
+			want: "",
+		},
 		{
 			name: "no match",
 			args: []string{"resource.aws_security_group.test", "resource.aws_security_group.test3"},
@@ -129,9 +246,10 @@ resource "aws_security_group" "test2" {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runBlockGetCmd, src)
+			cmd := setMockStreams(newBlockMvCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runBlockMvCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -180,11 +298,13 @@ resource "aws_security_group" "fuga" {
 
 	cases := []struct {
 		name string
+		args []string
 		ok   bool
 		want string
 	}{
 		{
 			name: "simple",
+			args: []string{},
 			ok:   true,
 			want: `terraform
 provider.aws
@@ -192,14 +312,239 @@ resource.aws_security_group.hoge
 resource.aws_security_group.fuga
 `,
 		},
+		{
+			name: "type",
+			args: []string{"--type", "resource"},
+			ok:   true,
+			want: `resource.aws_security_group.hoge
+resource.aws_security_group.fuga
+`,
+		},
+		{
+			name: "type and labels",
+			args: []string{"--type", "resource", "--label", "aws_security_group", "--label", "fuga"},
+			ok:   true,
+			want: `resource.aws_security_group.fuga
+`,
+		},
+		{
+			name: "type with where",
+			args: []string{"--type", "resource", "--where", `name == "hoge"`},
+			ok:   true,
+			want: `resource.aws_security_group.hoge
+`,
+		},
+		{
+			name: "type with template",
+			args: []string{"--type", "provider", "--template", "{{.Address}}\n"},
+			ok:   true,
+			want: `provider.aws
+`,
+		},
+		{
+			name: "json",
+			args: []string{"--output", "json"},
+			ok:   true,
+			want: `[
+  {
+    "address": "terraform",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 1,
+        "column": 1,
+        "byte": 0
+      },
+      "end": {
+        "line": 3,
+        "column": 2,
+        "byte": 44
+      }
+    }
+  },
+  {
+    "address": "provider.aws",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 5,
+        "column": 1,
+        "byte": 46
+      },
+      "end": {
+        "line": 8,
+        "column": 2,
+        "byte": 114
+      }
+    }
+  },
+  {
+    "address": "resource.aws_security_group.hoge",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 10,
+        "column": 1,
+        "byte": 116
+      },
+      "end": {
+        "line": 17,
+        "column": 2,
+        "byte": 242
+      }
+    }
+  },
+  {
+    "address": "resource.aws_security_group.fuga",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 19,
+        "column": 1,
+        "byte": 244
+      },
+      "end": {
+        "line": 26,
+        "column": 2,
+        "byte": 370
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "positions",
+			args: []string{"--positions"},
+			ok:   true,
+			want: "-:1:1\t0\t44\tterraform\n" +
+				"-:5:1\t46\t114\tprovider.aws\n" +
+				"-:10:1\t116\t242\tresource.aws_security_group.hoge\n" +
+				"-:19:1\t244\t370\tresource.aws_security_group.fuga\n",
+		},
+		{
+			name: "template",
+			args: []string{"--template", "{{.Address}}\t{{len .Labels}}\t{{.Range.Start.Line}}\n"},
+			ok:   true,
+			want: "terraform\t0\t1\n" +
+				"provider.aws\t1\t5\n" +
+				"resource.aws_security_group.hoge\t2\t10\n" +
+				"resource.aws_security_group.fuga\t2\t19\n",
+		},
+		{
+			name: "template with json",
+			args: []string{"--template", "{{.Address}}", "--output", "json"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "unknown output",
+			args: []string{"--output", "yaml"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "address",
+			args: []string{"resource.aws_security_group.*"},
+			ok:   true,
+			want: `resource.aws_security_group.hoge
+resource.aws_security_group.fuga
+`,
+		},
+		{
+			name: "address with where",
+			args: []string{"resource.aws_security_group.*", "--where", `name == "fuga"`},
+			ok:   true,
+			want: `resource.aws_security_group.fuga
+`,
+		},
+		{
+			name: "address no match",
+			args: []string{"hoge"},
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"terraform", "provider.aws"},
+			ok:   false,
+			want: "",
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runBlockGetCmd, src)
+			cmd := setMockStreams(newBlockListCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			args := []string{}
-			err := runBlockListCmd(cmd, args)
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockListWhere(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+}
+
+resource "aws_instance" "db" {
+  instance_type = "m5.large"
+}
+
+resource "aws_s3_bucket" "logs" {
+  instance_type = "t2.micro"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"block", "list", "resource.aws_instance.*", "--where", `instance_type == "t2.micro"`},
+			ok:   true,
+			want: "resource.aws_instance.web\n",
+		},
+		{
+			name: "ignore case",
+			args: []string{"block", "list", "--ignore-case", "RESOURCE.AWS_INSTANCE.*", "--where", `instance_type == "m5.large"`},
+			ok:   true,
+			want: "resource.aws_instance.db\n",
+		},
+		{
+			name: "without address",
+			args: []string{"block", "list", "--where", `instance_type == "t2.micro"`},
+			ok:   true,
+			want: `resource.aws_instance.web
+resource.aws_s3_bucket.logs
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newBlockCmd())
+			setMockStreams(cmd, src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -222,6 +567,13 @@ func TestBlockRm(t *testing.T) {
   name = "hoge"
 }
 
+resource "aws_instance" "web" {
+  ami = "ami-123"
+  timeouts {
+    create = "5m"
+  }
+}
+
 data "aws_security_group" "fuga" {
   name = "fuga"
 }
@@ -237,7 +589,32 @@ data "aws_security_group" "fuga" {
 			name: "simple",
 			args: []string{"data.aws_security_group.hoge"},
 			ok:   true,
-			want: `data "aws_security_group" "fuga" {
+			want: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  timeouts {
+    create = "5m"
+  }
+}
+
+data "aws_security_group" "fuga" {
+  name = "fuga"
+}
+`,
+		},
+		{
+			name: "keep body",
+			args: []string{"--keep-body", "resource.aws_instance.web.timeouts"},
+			ok:   true,
+			want: `data "aws_security_group" "hoge" {
+  name = "hoge"
+}
+
+resource "aws_instance" "web" {
+  ami    = "ami-123"
+  create = "5m"
+}
+
+data "aws_security_group" "fuga" {
   name = "fuga"
 }
 `,
@@ -264,9 +641,509 @@ data "aws_security_group" "fuga" {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := newMockCmd(runBlockGetCmd, src)
+			cmd := setMockStreams(newBlockRmCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockRename(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"resource.aws_instance.web", "aws_instance.app"},
+			ok:   true,
+			want: `resource "aws_instance" "app" {
+  ami = "ami-123"
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"resource.aws_instance.hoge", "aws_instance.app"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "moved",
+			args: []string{"--moved", "resource.aws_instance.web", "aws_instance.app"},
+			ok:   true,
+			want: `resource "aws_instance" "app" {
+  ami = "ami-123"
+}
+
+moved {
+  from = aws_instance.web
+  to   = aws_instance.app
+}
+`,
+		},
+		{
+			name: "1 arg",
+			args: []string{"hoge"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"hoge", "fuga", "piyo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newBlockRenameCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockWrap(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami    = "ami-123"
+  create = "5m"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"--into", "timeouts", "resource.aws_instance.web.create"},
+			ok:   true,
+			want: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  timeouts {
+    create = "5m"
+  }
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"--into", "timeouts", "resource.aws_instance.web.delete"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "no into",
+			args: []string{"resource.aws_instance.web.create"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{"--into", "timeouts"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newBlockWrapCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockLabels(t *testing.T) {
+	src := `resource "aws_security_group" "hoge" {
+  name = "hoge"
+}
+
+resource "aws_security_group" "fuga" {
+  name = "fuga"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"resource"},
+			ok:   true,
+			want: "aws_security_group\thoge\naws_security_group\tfuga\n",
+		},
+		{
+			name: "no match",
+			args: []string{"hoge"},
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runBlockLabelsCmd, src)
+
+			err := runBlockLabelsCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockAppend(t *testing.T) {
+	src := `terraform {
+  required_version = "0.12.18"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"terraform", "backend.s3"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+  backend "s3" {
+  }
+}
+`,
+		},
+		{
+			name: "with newline and comment",
+			args: []string{"--newline", "--comment", "managed by hcledit", "terraform", "backend.s3"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+
+  # managed by hcledit
+  backend "s3" {
+  }
+}
+`,
+		},
+		{
+			name: "with body file",
+			args: []string{"--body-file", "BODY_FILE", "terraform", "backend.s3"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+  backend "s3" {
+    bucket = "hoge"
+  }
+}
+`,
+		},
+		{
+			name: "anchor is not a block",
+			args: []string{"--before", "required_version", "terraform", "backend.s3"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "anchor not found",
+			args: []string{"--after", "backend.s3", "", "provider.aws"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "after at top level",
+			args: []string{"--newline", "--after", "terraform", "", "provider.aws"},
+			ok:   true,
+			want: `terraform {
+  required_version = "0.12.18"
+}
+
+provider "aws" {
+}
+`,
+		},
+		{
+			name: "before with body file",
+			args: []string{"--body-file", "BODY_FILE", "--before", "terraform", "", "backend.s3"},
+			ok:   true,
+			want: `backend "s3" {
+  bucket = "hoge"
+}
+terraform {
+  required_version = "0.12.18"
+}
+`,
+		},
+		{
+			name: "before with newline and normalize newlines",
+			args: []string{"--newline", "--normalize-newlines", "--before", "terraform", "", "provider.aws"},
+			ok:   true,
+			want: `provider "aws" {
+}
+terraform {
+  required_version = "0.12.18"
+}
+`,
+		},
+		{
+			name: "before and after",
+			args: []string{"--before", "terraform", "--after", "terraform", "", "provider.aws"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "body file not found",
+			args: []string{"--body-file", "BODY_FILE.notfound", "terraform", "backend.s3"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{"hoge"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, cleanup := newTempFile(t, `bucket = "hoge"`)
+			defer cleanup()
+			args := []string{}
+			for _, arg := range tc.args {
+				args = append(args, strings.Replace(arg, "BODY_FILE", path, 1))
+			}
+
+			cmd := setMockStreams(newBlockAppendCmd(), src)
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockCount(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+}
+
+resource "aws_instance" "db" {
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "wildcard",
+			args: []string{"resource.aws_instance.*"},
+			ok:   true,
+			want: "2\n",
+		},
+		{
+			name: "not found",
+			args: []string{"module.foo"},
+			ok:   false,
+			want: "0\n",
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runBlockCountCmd, src)
+
+			err := runBlockCountCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockFilter(t *testing.T) {
+	src := `b1 "l1" {
+  a1 = "v1"
+}
+b1 "l2" {
+  a1 = "v1"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{"--filter-exec", "sed s/v1/v2/", "b1.l1"},
+			ok:   true,
+			want: `b1 "l1" {
+  a1 = "v2"
+}
+b1 "l2" {
+  a1 = "v1"
+}
+`,
+		},
+		{
+			name: "wildcard",
+			args: []string{"--filter-exec", "sed s/a1/a2/", "b1.*"},
+			ok:   true,
+			want: `b1 "l1" {
+  a2 = "v1"
+}
+b1 "l2" {
+  a2 = "v1"
+}
+`,
+		},
+		{
+			name: "no match",
+			args: []string{"--filter-exec", "sed s/v1/v2/", "b2"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "invalid output",
+			args: []string{"--filter-exec", "echo {", "b1.l1"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no filter exec",
+			args: []string{"b1.l1"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{"--filter-exec", "cat"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newBlockFilterCmd(), src)
+			cmd.SetArgs(tc.args)
 
-			err := runBlockRmCmd(cmd, tc.args)
+			err := cmd.Execute()
 			stderr := mockErr(cmd)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newCommentCmd())
+}
+
+func newCommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Edit comment",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newCommentSetCmd(),
+		newCommentRmCmd(),
+	)
+
+	return cmd
+}
+
+func newCommentSetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <ADDRESS> <TEXT>",
+		Short: "Set leading comment",
+		Long: `Attach a leading comment to matched attributes or blocks
+
+The address is resolved as an attribute first, and then as blocks.
+Existing comments immediately above each matched item are replaced.
+
+Arguments:
+  ADDRESS          An address of attribute or block.
+  TEXT             A text of comment. Each line is written as a # comment.
+`,
+		RunE: runCommentSetCmd,
+	}
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runCommentSetCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	address := args[0]
+	text := args[1]
+
+	return editor.SetComment(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address, text)
+}
+
+func newCommentRmCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rm <ADDRESS>",
+		Short: "Remove comments",
+		Long: `Remove leading and trailing comments of matched attributes or blocks
+
+The address is resolved as an attribute first, and then as blocks.
+Comments inside a block are not removed.
+
+Arguments:
+  ADDRESS          An address of attribute or block.
+`,
+		RunE: runCommentRmCmd,
+	}
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runCommentRmCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address := args[0]
+
+	return editor.RemoveComment(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address)
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCommentSet(t *testing.T) {
+	src := `resource "foo" "bar" {
+  // old
+  attr1 = "val1"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "attribute",
+			args: []string{"resource.foo.bar.attr1", "new"},
+			ok:   true,
+			want: `resource "foo" "bar" {
+  # new
+  attr1 = "val1"
+}
+`,
+		},
+		{
+			name: "block",
+			args: []string{"resource.foo.bar", "new"},
+			ok:   true,
+			want: `# new
+resource "foo" "bar" {
+  // old
+  attr1 = "val1"
+}
+`,
+		},
+		{
+			name: "no text",
+			args: []string{"resource.foo.bar"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newCommentSetCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommentRm(t *testing.T) {
+	src := `resource "foo" "bar" {
+  // leading
+  attr1 = "val1" # trailing
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "attribute",
+			args: []string{"resource.foo.bar.attr1"},
+			ok:   true,
+			want: `resource "foo" "bar" {
+  attr1 = "val1"
+}
+`,
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newCommentRmCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
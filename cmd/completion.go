@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// bashCompletionFunc is a custom completion function for bash.
+// It completes an address argument of attribute and block commands with
+// addresses in a file given by --file, which are listed by the list command.
+// It requires bash 4 or later, because cobra records flag values in an
+// associative array.
+const bashCompletionFunc = `
+__hcledit_get_addresses()
+{
+    local file=${flaghash[--file]:-${flaghash[--file=]:-${flaghash[-f]}}}
+    if [[ -z ${file} || ${file} == "-" ]]; then
+        return
+    fi
+
+    local addresses
+    if addresses=$(${words[0]} list --file "${file}" 2>/dev/null); then
+        COMPREPLY=( $(compgen -W "${addresses}" -- "$cur") )
+    fi
+}
+
+__hcledit_custom_func()
+{
+    case ${last_command} in
+        hcledit_attribute_* | hcledit_block_*)
+            __hcledit_get_addresses
+            return
+            ;;
+        *)
+            ;;
+    esac
+}
+`
+
+func init() {
+	RootCmd.AddCommand(newCompletionCmd())
+}
+
+func newCompletionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion <SHELL>",
+		Short: "Generate shell completion",
+		Long: `Generate a shell completion script
+
+Arguments:
+  SHELL            A shell type. Valid values are bash and zsh.
+
+In bash, an address argument of attribute and block commands is completed
+with real addresses in a file given by --file, so type the --file flag before
+the address. e.g.) hcledit attribute get -f main.tf resource.<TAB>
+It requires bash 4 or later. In zsh, only commands and flags are completed.
+
+To load completions in the current shell:
+  source <(hcledit completion bash)
+`,
+		RunE: runCompletionCmd,
+	}
+
+	return cmd
+}
+
+func runCompletionCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	switch args[0] {
+	case "bash":
+		return cmd.Root().GenBashCompletion(cmd.OutOrStdout())
+	case "zsh":
+		return cmd.Root().GenZshCompletion(cmd.OutOrStdout())
+	default:
+		return fmt.Errorf("unknown shell: %s", args[0])
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "bash",
+			args: []string{"completion", "bash"},
+			ok:   true,
+			want: "__hcledit_get_addresses",
+		},
+		{
+			name: "zsh",
+			args: []string{"completion", "zsh"},
+			ok:   true,
+			want: "function _hcledit",
+		},
+		{
+			name: "unknown shell",
+			args: []string{"completion", "fish"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{"completion"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newCompletionCmd(), newAttributeCmd())
+			setMockStreams(cmd, "")
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if !strings.Contains(stdout, tc.want) {
+				t.Fatalf("expected to contain %q, but got:\n%s", tc.want, stdout)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newDiffCmd())
+}
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <FILE1> <FILE2>",
+		Short: "Compare two HCL files structurally",
+		Long: `Compare two HCL files structurally and write changed addresses
+
+Blocks are compared by address and attributes are compared by name.
+Each line of the output is an address prefixed by a kind of change:
+
+  + an address added in FILE2
+  - an address removed in FILE2
+  ~ an attribute whose value changed
+
+A difference of whitespace, comments and trailing commas is ignored.
+Nothing is written if there is no difference.
+
+Arguments:
+  FILE1            A path of file to compare. The - means stdin.
+  FILE2            A path of file to compare.
+`,
+		RunE: runDiffCmd,
+	}
+
+	return cmd
+}
+
+func runDiffCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	if !readsStdin(cmd) {
+		return fmt.Errorf("--file and --recursive cannot be used with diff. give paths as arguments")
+	}
+
+	path1 := args[0]
+	path2 := args[1]
+
+	in := cmd.InOrStdin()
+	if path1 != "-" {
+		f, err := os.Open(path1)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %s", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	other, err := os.Open(path2)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %s", err)
+	}
+	defer other.Close()
+
+	return editor.SemanticDiff(in, cmd.OutOrStdout(), path1, other, path2)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	file1, cleanup1 := newTempFile(t, `a = 1
+b = 2
+`)
+	defer cleanup1()
+
+	file2, cleanup2 := newTempFile(t, `b = 3
+`)
+	defer cleanup2()
+
+	stdin := `a = 1
+b = 3
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "files",
+			args: []string{file1, file2},
+			ok:   true,
+			want: `- a
+~ b
+`,
+		},
+		{
+			name: "stdin",
+			args: []string{"-", file2},
+			ok:   true,
+			want: `- a
+`,
+		},
+		{
+			name: "not found",
+			args: []string{file1, file2 + ".notfound"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{file1},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newDiffCmd(), stdin)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
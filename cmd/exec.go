@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// addFilterExecFlag adds a flag to transform matched items with an external
+// program.
+func addFilterExecFlag(cmd *cobra.Command) {
+	cmd.Flags().String("filter-exec", "", `An external program which transforms matched items (required).
+Each matched item is written to stdin of the program as an HCL fragment, and
+the output on stdout replaces it. Arguments are separated by spaces. e.g.) --filter-exec './myfilter --opt'`)
+}
+
+// getFilterExecFunc returns a function which runs the program given by the
+// --filter-exec flag.
+func getFilterExecFunc(cmd *cobra.Command) (editor.FragmentFunc, error) {
+	filterExec, err := cmd.Flags().GetString("filter-exec")
+	if err != nil {
+		return nil, err
+	}
+
+	command := strings.Fields(filterExec)
+	if len(command) == 0 {
+		return nil, fmt.Errorf("--filter-exec is required")
+	}
+
+	fn := func(fragment string) (string, error) {
+		var stdout bytes.Buffer
+		c := exec.Command(command[0], command[1:]...)
+		c.Stdin = strings.NewReader(fragment)
+		c.Stdout = &stdout
+		c.Stderr = cmd.ErrOrStderr()
+		if err := c.Run(); err != nil {
+			return "", fmt.Errorf("failed to run filter: %s: %s", filterExec, err)
+		}
+
+		return stdout.String(), nil
+	}
+
+	return fn, nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+)
+
+const (
+	// exitNotFound is an exit status which indicates that nothing matches a
+	// given address.
+	exitNotFound = 1
+	// exitError is an exit status for other errors such as parse errors when
+	// the exit status is enabled.
+	exitError = 2
+)
+
+// ExitError is an error with an exit status of the command.
+// If Err is nil, nothing should be printed.
+type ExitError struct {
+	// Code is an exit status.
+	Code int
+	// Err is an underlying error.
+	Err error
+}
+
+// Error returns an error message.
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+// withExitStatus converts a given error to an ExitError, so that scripts can
+// distinguish a not found result from other errors by the exit status.
+// If errors of all input files are not found, it returns exitNotFound without
+// a message. Otherwise it returns exitError.
+func withExitStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if isNotFound(err) {
+		return &ExitError{Code: exitNotFound}
+	}
+
+	return &ExitError{Code: exitError, Err: err}
+}
+
+// isNotFound returns true if a given error is a NotFoundError, or a
+// FilesError which consists of NotFoundErrors only.
+func isNotFound(err error) bool {
+	var filesErr *editor.FilesError
+	if errors.As(err, &filesErr) {
+		for _, e := range filesErr.Errors {
+			if !isNotFound(e) {
+				return false
+			}
+		}
+		return true
+	}
+
+	var notFoundErr *editor.NotFoundError
+	return errors.As(err, &notFoundErr)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// readFlagFile reads contents of a file given by a flag such as --value-file.
+// The - means stdin, but it is available only when the HCL input is read
+// from files with --file or --recursive, because stdin is used for the HCL input otherwise.
+func readFlagFile(cmd *cobra.Command, flag string, path string) (string, error) {
+	if path == "-" {
+		if readsStdin(cmd) {
+			return "", fmt.Errorf("failed to read --%s: - requires --file, because stdin is used for the HCL input", flag)
+		}
+
+		b, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read --%s from stdin: %s", flag, err)
+		}
+		return string(b), nil
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read --%s: %s", flag, err)
+	}
+
+	return string(b), nil
+}
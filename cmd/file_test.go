@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// newTempFile is a helper function which writes given contents to a new
+// temporary file and returns its path. The file is removed by cleanup.
+func newTempFile(t *testing.T, contents string) (string, func()) {
+	t.Helper()
+	f, err := ioutil.TempFile("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %s", err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(contents); err != nil {
+		os.Remove(f.Name())
+		t.Fatalf("failed to write temp file: %s", err)
+	}
+
+	return f.Name(), func() { os.Remove(f.Name()) }
+}
+
+func TestReadFlagFile(t *testing.T) {
+	path, cleanup := newTempFile(t, "foo\n")
+	defer cleanup()
+
+	cases := []struct {
+		name string
+		path string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			path: path,
+			ok:   true,
+			want: "foo\n",
+		},
+		{
+			name: "stdin",
+			path: "-",
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "not found",
+			path: path + ".notfound",
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := readFlagFile(newMockCmd(nil, ""), "test", tc.path)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newFmtCmd())
+}
+
+func newFmtCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fmt",
+		Short: "Format HCL",
+		Long: `Format HCL in the canonical style of hclwrite
+
+Files are formatted recursively with --recursive.
+e.g.) hcledit fmt -R . --write
+`,
+		RunE: runFmtCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.BoolP("write", "w", false, "Write the result back to the input file. It is an alias of --update")
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runFmtCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	return editor.Format(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd))
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestFmt(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			src: `resource   "foo"   "bar" {
+attr1 = "val1"
+  attr22 = "val2"
+}
+`,
+			args: []string{},
+			ok:   true,
+			want: `resource "foo" "bar" {
+  attr1  = "val1"
+  attr22 = "val2"
+}
+`,
+		},
+		{
+			name: "too many args",
+			src:  "",
+			args: []string{"foo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newFmtCmd(), tc.src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
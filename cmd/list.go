@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newListCmd())
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all addresses",
+		Long: `List addresses of all blocks and attributes in the file
+
+The file is walked depth first, and each address is written one per line in
+the address notation of hcledit, so that it can be given to other commands.
+Repeated blocks with the same address are addressed with an index suffix.
+`,
+		RunE: runListCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Int("max-depth", 0, "A max depth of items to be listed. Top level items are at depth 1. 0 means no limit")
+
+	return cmd
+}
+
+func runListCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	maxDepth, err := cmd.Flags().GetInt("max-depth")
+	if err != nil {
+		return err
+	}
+	if maxDepth < 0 {
+		return fmt.Errorf("--max-depth must not be negative: %d", maxDepth)
+	}
+
+	return editor.ListAddresses(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), maxDepth)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	src := `resource "foo" "bar" {
+  attr1 = "val1"
+  nested {
+    attr2 = "val2"
+  }
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "all",
+			args: []string{},
+			ok:   true,
+			want: `resource.foo.bar
+resource.foo.bar.attr1
+resource.foo.bar.nested
+resource.foo.bar.nested.attr2
+`,
+		},
+		{
+			name: "max depth",
+			args: []string{"--max-depth", "2"},
+			ok:   true,
+			want: `resource.foo.bar
+resource.foo.bar.attr1
+resource.foo.bar.nested
+`,
+		},
+		{
+			name: "negative max depth",
+			args: []string{"--max-depth", "-1"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{"foo"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newListCmd(), src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
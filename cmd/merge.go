@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newMergeCmd())
+}
+
+func newMergeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <BASE> <OVERLAY>",
+		Short: "Merge two HCL files",
+		Long: `Deep-merge an overlay file into a base file and write the result
+
+Blocks are matched by type and labels, and merged recursively.
+Attributes in the overlay replace the base ones with the same name.
+Unmatched blocks and attributes in the overlay are appended.
+
+Arguments:
+  BASE             A path of base file. The - means stdin.
+  OVERLAY          A path of overlay file.
+`,
+		RunE: runMergeCmd,
+	}
+
+	return cmd
+}
+
+func runMergeCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	if !readsStdin(cmd) {
+		return fmt.Errorf("--file and --recursive cannot be used with merge. give paths as arguments")
+	}
+
+	basePath := args[0]
+	overlayPath := args[1]
+
+	var base io.Reader = cmd.InOrStdin()
+	if basePath != "-" {
+		f, err := os.Open(basePath)
+		if err != nil {
+			return fmt.Errorf("failed to open base: %s", err)
+		}
+		defer f.Close()
+		base = f
+	}
+
+	overlay, err := os.Open(overlayPath)
+	if err != nil {
+		return fmt.Errorf("failed to open overlay: %s", err)
+	}
+	defer overlay.Close()
+
+	return editor.Merge(base, cmd.OutOrStdout(), basePath, overlay, overlayPath)
+}
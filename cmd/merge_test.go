@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base, cleanupBase := newTempFile(t, `a = 1
+b = 2
+`)
+	defer cleanupBase()
+
+	overlay, cleanupOverlay := newTempFile(t, `b = 3
+`)
+	defer cleanupOverlay()
+
+	stdin := `a = 4
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "files",
+			args: []string{base, overlay},
+			ok:   true,
+			want: `a = 1
+b = 3
+`,
+		},
+		{
+			name: "stdin",
+			args: []string{"-", overlay},
+			ok:   true,
+			want: `a = 4
+b = 3
+`,
+		},
+		{
+			name: "overlay not found",
+			args: []string{base, overlay + ".notfound"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{base},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newMergeCmd(), stdin)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
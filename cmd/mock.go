@@ -13,6 +13,13 @@ func newMockCmd(runE func(cmd *cobra.Command, args []string) error, input string
 		RunE: runE,
 	}
 
+	return setMockStreams(cmd, input)
+}
+
+// setMockStreams is a helper function which mocks in/out/err streams of a
+// given *cobra.Command for testing. This is useful for testing a command with
+// flags, because flags are defined in the command and parsed on Execute().
+func setMockStreams(cmd *cobra.Command, input string) *cobra.Command {
 	inStream := bytes.NewBufferString(input)
 	outStream := new(bytes.Buffer)
 	errStream := new(bytes.Buffer)
@@ -21,6 +28,10 @@ func newMockCmd(runE func(cmd *cobra.Command, args []string) error, input string
 	cmd.SetOut(outStream)
 	cmd.SetErr(errStream)
 
+	// Errors are returned from Execute() and checked by the caller.
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
 	return cmd
 }
 
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// addNormalizeNewlinesFlag adds a flag to remove redundant blank lines.
+func addNormalizeNewlinesFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("normalize-newlines", false, `Collapse consecutive blank lines, remove blank lines at the beginning and the end of the file and of each block,
+and end the output with exactly one newline`)
+}
+
+// getNormalizeNewlinesOptions returns options of editor for normalizing
+// blank lines.
+func getNormalizeNewlinesOptions(cmd *cobra.Command) ([]editor.Option, error) {
+	normalize, err := cmd.Flags().GetBool("normalize-newlines")
+	if err != nil {
+		return nil, err
+	}
+
+	if !normalize {
+		return []editor.Option{}, nil
+	}
+
+	return []editor.Option{editor.WithNormalizeNewlines()}, nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// outputText is an output format for humans.
+	outputText = "text"
+	// outputJSON is an output format for tools.
+	outputJSON = "json"
+)
+
+// addOutputFlag adds a flag to select an output format.
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().String("output", outputText, "An output format. Valid values are text and json")
+}
+
+// getOutputFlag returns a validated output format.
+func getOutputFlag(cmd *cobra.Command) (string, error) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return "", err
+	}
+
+	switch output {
+	case outputText, outputJSON:
+		return output, nil
+	default:
+		return "", fmt.Errorf("unknown output format: %s", output)
+	}
+}
+
+// addTemplateFlag adds a flag to write results with a Go template.
+func addTemplateFlag(cmd *cobra.Command) {
+	cmd.Flags().String("template", "", `A Go template to write each result instead of the default output.
+The template can refer to .Address, .Value, .Range such as .Range.Start.Line, and .Labels of a block.
+No newline is added between results. e.g.) --template '{{.Address}}={{.Value}}{{"\n"}}'`)
+}
+
+// getTemplateFlag returns a parsed template of the template flag.
+// If the flag is not given, it returns nil.
+func getTemplateFlag(cmd *cobra.Command) (*template.Template, error) {
+	tmpl, err := cmd.Flags().GetString("template")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tmpl) == 0 {
+		return nil, nil
+	}
+
+	t, err := template.New("output").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse --template: %s", err)
+	}
+
+	return t, nil
+}
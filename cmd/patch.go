@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newPatchCmd())
+}
+
+func newPatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Create and apply structural patches",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newPatchCreateCmd(),
+		newPatchApplyCmd(),
+	)
+
+	return cmd
+}
+
+// addPatchFormatFlag adds a flag to select a format of patch.
+func addPatchFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", editor.PatchFormatHCL, "A format of patch. Valid values are hcl and json")
+}
+
+func newPatchCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <FILE1> <FILE2>",
+		Short: "Create a patch",
+		Long: `Create a patch which edits FILE1 into FILE2 and write it
+
+A patch is a list of operations with addresses and values, so that an edit
+recorded against one file can be replayed against many with patch apply.
+Changed, added and removed attributes become set, append-attribute and rm
+operations. Added blocks become append operations with their bodies, and
+removed top level blocks become rm-block operations.
+A change in repeated blocks with the same address and a removal of nested
+block cannot be addressed, and it is an error.
+
+Arguments:
+  FILE1            A path of original file. The - means stdin.
+  FILE2            A path of edited file.
+`,
+		RunE: runPatchCreateCmd,
+	}
+
+	addPatchFormatFlag(cmd)
+
+	return cmd
+}
+
+func runPatchCreateCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	if !readsStdin(cmd) {
+		return fmt.Errorf("--file and --recursive cannot be used with patch create. give paths as arguments")
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+
+	path1 := args[0]
+	path2 := args[1]
+
+	var in io.Reader = cmd.InOrStdin()
+	if path1 != "-" {
+		f, err := os.Open(path1)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %s", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	other, err := os.Open(path2)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %s", err)
+	}
+	defer other.Close()
+
+	patch, err := editor.CreatePatch(in, path1, other, path2)
+	if err != nil {
+		return err
+	}
+
+	return editor.WritePatch(cmd.OutOrStdout(), patch, format)
+}
+
+func newPatchApplyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a patch",
+		Long: `Apply a patch created by patch create
+
+Operations in the patch are applied in order in a single pass like the apply
+command. If any of operations fails, nothing is written.
+`,
+		RunE: runPatchApplyCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.StringP("patch", "p", "", "A path of patch file. The - means stdin, which requires --file")
+	addPatchFormatFlag(cmd)
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runPatchApplyCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	path, err := cmd.Flags().GetString("patch")
+	if err != nil {
+		return err
+	}
+	if len(path) == 0 {
+		return fmt.Errorf("--patch is required")
+	}
+
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+
+	src, err := readFlagFile(cmd, "patch", path)
+	if err != nil {
+		return err
+	}
+
+	patch, err := editor.ReadPatch(strings.NewReader(src), path, format)
+	if err != nil {
+		return err
+	}
+
+	return editor.ApplyScript(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), patch)
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestPatchCreate(t *testing.T) {
+	file1, cleanup1 := newTempFile(t, `resource "foo" "bar" {
+  attr1 = "val1"
+  attr2 = "val2"
+}
+`)
+	defer cleanup1()
+
+	file2, cleanup2 := newTempFile(t, `resource "foo" "bar" {
+  attr1 = "val3"
+}
+`)
+	defer cleanup2()
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "hcl",
+			args: []string{file1, file2},
+			ok:   true,
+			want: `operation "set" {
+  address = "resource.foo.bar.attr1"
+  value   = "\"val3\""
+}
+
+operation "rm" {
+  address = "resource.foo.bar.attr2"
+}
+`,
+		},
+		{
+			name: "json",
+			args: []string{"--format", "json", file1, file2},
+			ok:   true,
+			want: `[
+  {
+    "kind": "set",
+    "address": "resource.foo.bar.attr1",
+    "value": "\"val3\""
+  },
+  {
+    "kind": "rm",
+    "address": "resource.foo.bar.attr2"
+  }
+]
+`,
+		},
+		{
+			name: "unknown format",
+			args: []string{"--format", "yaml", file1, file2},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "1 arg",
+			args: []string{file1},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newPatchCreateCmd(), "")
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestPatchApply(t *testing.T) {
+	src := `resource "foo" "baz" {
+  attr1 = "val1"
+  attr2 = "val2"
+  attr3 = "val4"
+}
+`
+
+	cases := []struct {
+		name  string
+		patch string
+		args  []string
+		ok    bool
+		want  string
+	}{
+		{
+			name: "hcl",
+			patch: `operation "set" {
+  address = "resource.foo.baz.attr1"
+  value   = "\"val3\""
+}
+
+operation "rm" {
+  address = "resource.foo.baz.attr2"
+}
+`,
+			args: []string{},
+			ok:   true,
+			want: `resource "foo" "baz" {
+  attr1 = "val3"
+  attr3 = "val4"
+}
+`,
+		},
+		{
+			name:  "json",
+			patch: `[{"kind": "rm", "address": "resource.foo.baz.attr3"}]`,
+			args:  []string{"--format", "json"},
+			ok:    true,
+			want: `resource "foo" "baz" {
+  attr1 = "val1"
+  attr2 = "val2"
+}
+`,
+		},
+		{
+			name:  "parse error",
+			patch: `operation {}`,
+			args:  []string{},
+			ok:    false,
+			want:  "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, cleanup := newTempFile(t, tc.patch)
+			defer cleanup()
+
+			cmd := setMockStreams(newPatchApplyCmd(), src)
+			cmd.SetArgs(append([]string{"--patch", path}, tc.args...))
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newProviderCmd())
+}
+
+func newProviderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "provider",
+		Short: "Edit provider requirements",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newProviderSetVersionCmd(),
+	)
+
+	return cmd
+}
+
+func newProviderSetVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-version <NAME> <CONSTRAINT>",
+		Short: "Set version of provider",
+		Long: `Set a version constraint of provider in required_providers
+
+The entry of the provider in terraform.required_providers is updated in place.
+Missing structure is created as needed, that is, the terraform block, the
+required_providers block and the entry of the provider.
+The constraint is validated before writing.
+
+Arguments:
+  NAME             A local name of provider such as aws.
+  CONSTRAINT       A version constraint such as "~> 3.0".
+                   It is quoted for you, so don't escape double quotes.
+`,
+		RunE: runProviderSetVersionCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.String("source", "", "A source address of provider such as hashicorp/aws. If empty, the source is not changed")
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runProviderSetVersionCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("expected 2 argument, but got %d arguments", len(args))
+	}
+
+	name := args[0]
+	constraint := args[1]
+	source, err := cmd.Flags().GetString("source")
+	if err != nil {
+		return err
+	}
+
+	return editor.SetProviderVersion(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), name, constraint, source)
+}
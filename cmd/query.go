@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newQueryCmd())
+}
+
+func newQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query <QUERY>",
+		Short: "Query HCL with a jq-style expression",
+		Long: `Query HCL with a jq-style expression
+
+Arguments:
+  QUERY            A pipeline of filters separated by |.
+                   A filter is either a path such as .resource.aws_instance[]
+                   or select(PATH), select(PATH == VALUE), select(PATH != VALUE).
+
+A path is resolved in the same way as an address, and [] iterates over all
+matched blocks, elements or keys at the position. Blocks are iterated even if
+they have more labels than the path such as .resource[]. Each matched block is
+written as it is, and each matched value is written in a line.
+`,
+		RunE: runQueryCmd,
+	}
+
+	return cmd
+}
+
+func runQueryCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	query := args[0]
+
+	return editor.Query(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), query)
+}
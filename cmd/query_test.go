@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+
+resource "aws_instance" "db" {
+  instance_type = "t3.large"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			args: []string{".resource.aws_instance[] | select(.ami != null) | .ami"},
+			ok:   true,
+			want: "\"ami-123\"\n",
+		},
+		{
+			name: "invalid query",
+			args: []string{"resource"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "too many args",
+			args: []string{".a", ".b"},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newMockCmd(runQueryCmd, src)
+
+			err := runQueryCmd(cmd, tc.args)
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
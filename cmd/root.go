@@ -1,25 +1,329 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 
+	"github.com/minamijoyo/hcledit/editor"
 	"github.com/spf13/cobra"
 )
 
+// annotationUpdatable is an annotation key of commands which edit HCL and
+// support writing the result back to the input file with --update.
+const annotationUpdatable = "updatable"
+
+// annotationFilename is an annotation key of a command to record a filename
+// of the input, which is set on a copy of the command for each input file.
+const annotationFilename = "filename"
+
+// annotationIgnoreCase is an annotation key of commands which match
+// addresses case-insensitively with --ignore-case.
+const annotationIgnoreCase = "ignore-case"
+
+// annotationExitStatus is an annotation key of commands which always report
+// the result by the exit status as if --exit-status is given.
+const annotationExitStatus = "exit-status"
+
 // RootCmd is a top level command instance
-var RootCmd = &cobra.Command{
-	Use:           "hcledit",
-	Short:         "A command line editor for HCL",
-	SilenceErrors: true,
-	SilenceUsage:  true,
-}
+var RootCmd = newRootCmd()
 
 func init() {
 	setDefaultStream(RootCmd)
 }
 
+func newRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "hcledit",
+		Short:             "A command line editor for HCL",
+		SilenceErrors:     true,
+		SilenceUsage:      true,
+		PersistentPreRunE: preRunRootCmd,
+		// complete addresses dynamically in bash.
+		BashCompletionFunction: bashCompletionFunc,
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringArrayP("file", "f", []string{"-"}, `A path of input file. The - means stdin.
+It accepts a glob pattern and can be given multiple times`)
+	flags.StringArrayP("recursive", "R", []string{}, `A directory to find *.tf and *.hcl files recursively. It can be given multiple times.
+Files in the JSON syntax such as *.tf.json are not found, because most commands don't support it`)
+	flags.BoolP("update", "u", false, `Write the result back to the input file instead of stdout.
+The file is replaced atomically through a temporary file. Requires --file or --recursive`)
+	flags.Bool("diff", false, "Print a unified diff of changes instead of the result. It cannot be used with --update")
+	flags.String("backup", "", "A suffix of a backup file of the original input such as .bak. Requires --update")
+	flags.IntP("parallel", "P", 1, "A number of input files processed concurrently")
+	flags.Bool("ignore-case", false, `Match block types and labels in addresses case-insensitively.
+Attribute names are still case-sensitive. Commands which don't support it return an error`)
+	flags.Bool("watch", false, `Watch input files and run the command again whenever they change.
+Errors are printed without stopping watching. Requires --file or --recursive`)
+
+	return cmd
+}
+
 func setDefaultStream(cmd *cobra.Command) {
 	cmd.SetIn(os.Stdin)
 	cmd.SetOut(os.Stdout)
 	cmd.SetErr(os.Stderr)
 }
+
+// setUpdatable marks a given command as one which supports --update.
+func setUpdatable(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[annotationUpdatable] = "true"
+}
+
+// setIgnoreCase marks a given command as one which supports --ignore-case.
+// The command must resolve all addresses in arguments with resolveAddress.
+func setIgnoreCase(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[annotationIgnoreCase] = "true"
+}
+
+// withAnnotation returns a copy of given annotations with a given key set, so
+// that the original annotations shared by concurrent runs are not modified.
+func withAnnotation(annotations map[string]string, key string, value string) map[string]string {
+	copied := make(map[string]string, len(annotations)+1)
+	for k, v := range annotations {
+		copied[k] = v
+	}
+	copied[key] = value
+	return copied
+}
+
+// inputFilename returns a filename of the input of a given command, which is
+// used for error messages, source ranges and detecting the JSON syntax.
+// It returns - if the input is stdin.
+func inputFilename(cmd *cobra.Command) string {
+	if filename, ok := cmd.Annotations[annotationFilename]; ok {
+		return filename
+	}
+	return "-"
+}
+
+// setExitStatus marks a given command as one which reports the result by the
+// exit status.
+func setExitStatus(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[annotationExitStatus] = "true"
+}
+
+// usesExitStatus returns true if the exit status of a given command should be
+// converted by withExitStatus.
+func usesExitStatus(cmd *cobra.Command) bool {
+	if cmd.Annotations[annotationExitStatus] == "true" {
+		return true
+	}
+	// The --exit-status is defined only in some commands.
+	exitStatus, err := cmd.Flags().GetBool("exit-status")
+	return err == nil && exitStatus
+}
+
+// preRunRootCmd validates flags for input files, and replaces the RunE of
+// the command with a wrapper which runs it for each input file.
+// Input files are processed concurrently by --parallel workers.
+// The input stream is replaced with each file, and the output stream is
+// replaced with a buffer if the result is written back to the file or
+// rendered as a diff. With --stream, files are processed one by one and
+// streamed without reading them into memory.
+func preRunRootCmd(cmd *cobra.Command, args []string) error {
+	update, err := cmd.Flags().GetBool("update")
+	if err != nil {
+		return err
+	}
+	// The --write is an alias of --update defined only in the fmt command.
+	if write, err := cmd.Flags().GetBool("write"); err == nil && write {
+		update = true
+	}
+	backup, err := cmd.Flags().GetString("backup")
+	if err != nil {
+		return err
+	}
+	diff, err := cmd.Flags().GetBool("diff")
+	if err != nil {
+		return err
+	}
+	parallel, err := cmd.Flags().GetInt("parallel")
+	if err != nil {
+		return err
+	}
+	watch, err := cmd.Flags().GetBool("watch")
+	if err != nil {
+		return err
+	}
+	ignoreCase, err := cmd.Flags().GetBool("ignore-case")
+	if err != nil {
+		return err
+	}
+	// The --stream is defined only in some commands.
+	stream, _ := cmd.Flags().GetBool("stream")
+
+	if parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1: %d", parallel)
+	}
+
+	if len(backup) != 0 && !update {
+		return fmt.Errorf("--backup requires --update")
+	}
+
+	if diff && update {
+		return fmt.Errorf("--diff and --update cannot be used together")
+	}
+
+	if stream && diff {
+		return fmt.Errorf("--stream and --diff cannot be used together")
+	}
+
+	if stream && parallel > 1 {
+		return fmt.Errorf("--stream and --parallel cannot be used together")
+	}
+
+	files, err := inputFiles(cmd)
+	if err != nil {
+		return err
+	}
+
+	if files == nil && update {
+		return fmt.Errorf("--update requires --file or --recursive")
+	}
+
+	if files == nil && watch {
+		return fmt.Errorf("--watch requires --file or --recursive")
+	}
+
+	updatable := cmd.Annotations[annotationUpdatable] == "true"
+	if update && !updatable {
+		return fmt.Errorf("--update is not supported by the %s command", cmd.CommandPath())
+	}
+	if diff && !updatable {
+		return fmt.Errorf("--diff is not supported by the %s command", cmd.CommandPath())
+	}
+
+	if ignoreCase && cmd.Annotations[annotationIgnoreCase] != "true" {
+		return fmt.Errorf("--ignore-case is not supported by the %s command", cmd.CommandPath())
+	}
+
+	if cmd.RunE == nil {
+		return nil
+	}
+
+	if files != nil || diff {
+		run := cmd.RunE
+		f := func(r io.Reader, w io.Writer, filename string) error {
+			// Files may be processed concurrently, so run the command with a
+			// shallow copy which has its own streams.
+			c := *cmd
+			c.SetIn(r)
+			c.SetOut(w)
+			if files != nil {
+				c.Annotations = withAnnotation(cmd.Annotations, annotationFilename, filename)
+			}
+			return run(&c, args)
+		}
+		if diff {
+			f = diffFunc(f)
+		}
+
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if files == nil {
+				return f(cmd.InOrStdin(), out, "stdin")
+			}
+			if stream {
+				// stream files instead of reading them into memory.
+				return editor.ApplyFilesStream(files, out, update, backup, f)
+			}
+			return editor.ApplyFilesParallel(files, out, update, backup, parallel, f)
+		}
+	}
+
+	if watch {
+		run := cmd.RunE
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return runWatch(cmd, args, files, run)
+		}
+	}
+
+	if usesExitStatus(cmd) {
+		run := cmd.RunE
+		cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			return withExitStatus(run(cmd, args))
+		}
+	}
+
+	return nil
+}
+
+// diffFunc wraps a given function to write a unified diff between the input
+// and the output instead of the output itself.
+func diffFunc(f func(r io.Reader, w io.Writer, filename string) error) func(r io.Reader, w io.Writer, filename string) error {
+	return func(r io.Reader, w io.Writer, filename string) error {
+		input, err := ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %s", err)
+		}
+
+		var out bytes.Buffer
+		if err := f(bytes.NewReader(input), &out, filename); err != nil {
+			return err
+		}
+
+		if _, err := w.Write(editor.Diff(filename, input, out.Bytes())); err != nil {
+			return fmt.Errorf("failed to write output: %s", err)
+		}
+
+		return nil
+	}
+}
+
+// inputFiles returns a list of input files given by --file and --recursive.
+// It returns nil if the input is stdin.
+func inputFiles(cmd *cobra.Command) ([]string, error) {
+	patterns, err := cmd.Flags().GetStringArray("file")
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := cmd.Flags().GetStringArray("recursive")
+	if err != nil {
+		return nil, err
+	}
+
+	if !cmd.Flags().Changed("file") {
+		// ignore the default value of --file.
+		patterns = []string{}
+	}
+
+	if len(dirs) == 0 && (len(patterns) == 0 || (len(patterns) == 1 && patterns[0] == "-")) {
+		return nil, nil
+	}
+
+	for _, p := range patterns {
+		if p == "-" {
+			return nil, fmt.Errorf("stdin (-) cannot be combined with other input files")
+		}
+	}
+
+	files, err := editor.FindFiles(patterns, dirs)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no input files found")
+	}
+
+	return files, nil
+}
+
+// readsStdin returns true if the HCL input is read from stdin.
+func readsStdin(cmd *cobra.Command) bool {
+	files, err := inputFiles(cmd)
+	return err != nil || files == nil
+}
@@ -0,0 +1,780 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRootFileAndUpdate(t *testing.T) {
+	src := `locals {
+  env = "dev"
+}
+`
+
+	cases := []struct {
+		name   string
+		args   []string
+		ok     bool
+		stdout string
+		file   string
+		backup string
+	}{
+		{
+			name:   "read from file",
+			args:   []string{"attribute", "get", "--file", "FILE", "locals.env"},
+			ok:     true,
+			stdout: "\"dev\"\n",
+			file:   src,
+		},
+		{
+			name:   "update",
+			args:   []string{"attribute", "set", "-f", "FILE", "-u", "locals.env", `"prod"`},
+			ok:     true,
+			stdout: "",
+			file: `locals {
+  env = "prod"
+}
+`,
+		},
+		{
+			name:   "update with backup",
+			args:   []string{"attribute", "set", "-f", "FILE", "-u", "--backup", ".bak", "locals.env", `"prod"`},
+			ok:     true,
+			stdout: "",
+			file: `locals {
+  env = "prod"
+}
+`,
+			backup: src,
+		},
+		{
+			name:   "update without file",
+			args:   []string{"attribute", "set", "-u", "locals.env", `"prod"`},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "backup without update",
+			args:   []string{"attribute", "set", "-f", "FILE", "--backup", ".bak", "locals.env", `"prod"`},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "update is not supported",
+			args:   []string{"attribute", "get", "-f", "FILE", "-u", "locals.env"},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "file is kept on error",
+			args:   []string{"attribute", "set", "-f", "FILE", "-u", "locals.env", `"prod`},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name: "read from file in streaming mode",
+			args: []string{"attribute", "set", "-f", "FILE", "--stream", "locals.env", `"prod"`},
+			ok:   true,
+			stdout: `locals {
+  env = "prod"
+}
+`,
+			file: src,
+		},
+		{
+			name:   "update in streaming mode",
+			args:   []string{"attribute", "set", "-f", "FILE", "-u", "--backup", ".bak", "--stream", "locals.env", `"prod"`},
+			ok:     true,
+			stdout: "",
+			file: `locals {
+  env = "prod"
+}
+`,
+			backup: src,
+		},
+		{
+			name:   "stream with diff",
+			args:   []string{"attribute", "set", "-f", "FILE", "--diff", "--stream", "locals.env", `"prod"`},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "stream with parallel",
+			args:   []string{"attribute", "set", "-f", "FILE", "-P", "2", "--stream", "locals.env", `"prod"`},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "fmt with write",
+			args:   []string{"fmt", "-f", "FILE", "--write"},
+			ok:     true,
+			stdout: "",
+			file: `locals {
+  env = "dev"
+}
+`,
+		},
+		{
+			name:   "fmt with write requires file",
+			args:   []string{"fmt", "-w"},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "watch without file",
+			args:   []string{"attribute", "get", "--watch", "locals.env"},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+		{
+			name:   "file not found",
+			args:   []string{"attribute", "get", "-f", "FILE.notfound", "locals.env"},
+			ok:     false,
+			stdout: "",
+			file:   src,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path, cleanup := newTempFile(t, src)
+			defer cleanup()
+			defer os.Remove(path + ".bak")
+			args := []string{}
+			for _, arg := range tc.args {
+				if arg == "FILE" {
+					arg = path
+				} else if arg == "FILE.notfound" {
+					arg = path + ".notfound"
+				}
+				args = append(args, arg)
+			}
+
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd(), newFmtCmd())
+			setMockStreams(cmd, "")
+			cmd.SetArgs(args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.stdout {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.stdout)
+			}
+
+			file, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %s", err)
+			}
+			if string(file) != tc.file {
+				t.Fatalf("got file:\n%s\nwant:\n%s", string(file), tc.file)
+			}
+
+			if len(tc.backup) != 0 {
+				backup, err := ioutil.ReadFile(path + ".bak")
+				if err != nil {
+					t.Fatalf("failed to read backup file: %s", err)
+				}
+				if string(backup) != tc.backup {
+					t.Fatalf("got backup:\n%s\nwant:\n%s", string(backup), tc.backup)
+				}
+			}
+		})
+	}
+}
+
+func TestRootMultipleFiles(t *testing.T) {
+	src := `locals {
+  env = "dev"
+}
+`
+	updated := `locals {
+  env = "prod"
+}
+`
+
+	cases := []struct {
+		name   string
+		args   []string
+		ok     bool
+		stdout string
+		files  []string
+	}{
+		{
+			name:   "multiple files",
+			args:   []string{"attribute", "get", "-f", "DIR/a.tf", "-f", "DIR/sub/b.hcl", "locals.env"},
+			ok:     true,
+			stdout: "\"dev\"\n\"dev\"\n",
+			files:  []string{src, src, src},
+		},
+		{
+			name:   "parallel",
+			args:   []string{"attribute", "get", "-P", "2", "-f", "DIR/a.tf", "-f", "DIR/sub/b.hcl", "locals.env"},
+			ok:     true,
+			stdout: "\"dev\"\n\"dev\"\n",
+			files:  []string{src, src, src},
+		},
+		{
+			name:   "recursive update in parallel",
+			args:   []string{"attribute", "set", "-R", "DIR", "-u", "-P", "4", "locals.env", `"prod"`},
+			ok:     true,
+			stdout: "",
+			files:  []string{updated, updated, src},
+		},
+		{
+			name:   "invalid parallel",
+			args:   []string{"attribute", "get", "-P", "0", "-f", "DIR/a.tf", "locals.env"},
+			ok:     false,
+			stdout: "",
+			files:  []string{src, src, src},
+		},
+		{
+			name:   "glob",
+			args:   []string{"attribute", "get", "-f", "DIR/*.tf", "locals.env"},
+			ok:     true,
+			stdout: "\"dev\"\n",
+			files:  []string{src, src, src},
+		},
+		{
+			name:   "recursive update",
+			args:   []string{"attribute", "set", "-R", "DIR", "-u", "locals.env", `"prod"`},
+			ok:     true,
+			stdout: "",
+			files:  []string{updated, updated, src},
+		},
+		{
+			name:   "stdin with files",
+			args:   []string{"attribute", "get", "-f", "-", "-f", "DIR/a.tf", "locals.env"},
+			ok:     false,
+			stdout: "",
+			files:  []string{src, src, src},
+		},
+		{
+			name:   "no match",
+			args:   []string{"attribute", "get", "-f", "DIR/*.json", "locals.env"},
+			ok:     false,
+			stdout: "",
+			files:  []string{src, src, src},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hcledit")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			filenames := []string{"a.tf", "sub/b.hcl", "sub/c.txt"}
+			for _, f := range filenames {
+				path := filepath.Join(dir, f)
+				if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+					t.Fatalf("failed to create dir: %s", err)
+				}
+				if err := ioutil.WriteFile(path, []byte(src), 0600); err != nil {
+					t.Fatalf("failed to write file: %s", err)
+				}
+			}
+
+			args := []string{}
+			for _, arg := range tc.args {
+				args = append(args, strings.Replace(arg, "DIR", dir, 1))
+			}
+
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd())
+			setMockStreams(cmd, "")
+			cmd.SetArgs(args)
+
+			err = cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.stdout {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.stdout)
+			}
+
+			for i, f := range filenames {
+				file, err := ioutil.ReadFile(filepath.Join(dir, f))
+				if err != nil {
+					t.Fatalf("failed to read file: %s", err)
+				}
+				if string(file) != tc.files[i] {
+					t.Fatalf("got file %s:\n%s\nwant:\n%s", f, string(file), tc.files[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRootDiff(t *testing.T) {
+	src := `locals {
+  env = "dev"
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "diff",
+			args: []string{"attribute", "set", "--diff", "locals.env", `"prod"`},
+			ok:   true,
+			want: `--- a/stdin
++++ b/stdin
+@@ -1,3 +1,3 @@
+ locals {
+-  env = "dev"
++  env = "prod"
+ }
+`,
+		},
+		{
+			name: "no change",
+			args: []string{"attribute", "set", "--diff", "locals.env", `"dev"`},
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "diff is not supported",
+			args: []string{"attribute", "get", "--diff", "locals.env"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "diff with update",
+			args: []string{"attribute", "set", "--diff", "-u", "locals.env", `"prod"`},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd())
+			setMockStreams(cmd, src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestRootDiffLargeInput(t *testing.T) {
+	// The output is formatted, so the unformatted attribute at the end of a
+	// large file is also changed, and the diff can not be computed only from a
+	// common prefix and suffix.
+	n := 20000
+	var b strings.Builder
+	b.WriteString("locals {\n  env = \"dev\"\n}\n")
+	b.WriteString("variable \"large\" {\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "  a%06d = %d\n", i, i)
+	}
+	b.WriteString("}\n")
+	b.WriteString("tail   =   \"unformatted\"\n")
+	src := b.String()
+
+	cmd := newRootCmd()
+	cmd.AddCommand(newAttributeCmd())
+	setMockStreams(cmd, src)
+	cmd.SetArgs([]string{"attribute", "set", "--diff", "locals.env", `"prod"`})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("unexpected err = %s, stderr: \n%s", err, mockErr(cmd))
+	}
+
+	want := fmt.Sprintf(`--- a/stdin
++++ b/stdin
+@@ -1,5 +1,5 @@
+ locals {
+-  env = "dev"
++  env = "prod"
+ }
+ variable "large" {
+   a000000 = 0
+@@ -%d,4 +%d,4 @@
+   a%06d = %d
+   a%06d = %d
+ }
+-tail   =   "unformatted"
++tail = "unformatted"
+`, n+3, n+3, n-2, n-2, n-1, n-1)
+	if got := mockOut(cmd); got != want {
+		t.Fatalf("got stdout:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRootExitStatus(t *testing.T) {
+	src := `locals {
+  env = "dev"
+}
+`
+
+	cases := []struct {
+		name   string
+		args   []string
+		src    string
+		code   int
+		msg    bool
+		stdout string
+	}{
+		{
+			name:   "found",
+			args:   []string{"attribute", "get", "--exit-status", "locals.env"},
+			src:    src,
+			code:   0,
+			stdout: "\"dev\"\n",
+		},
+		{
+			name:   "not found",
+			args:   []string{"attribute", "get", "--exit-status", "locals.foo"},
+			src:    src,
+			code:   exitNotFound,
+			msg:    false,
+			stdout: "",
+		},
+		{
+			name:   "parse error",
+			args:   []string{"attribute", "get", "--exit-status", "locals.env"},
+			src:    "locals {",
+			code:   exitError,
+			msg:    true,
+			stdout: "",
+		},
+		{
+			name:   "attribute exists",
+			args:   []string{"attribute", "exists", "locals.env"},
+			src:    src,
+			code:   0,
+			stdout: "",
+		},
+		{
+			name:   "attribute does not exist",
+			args:   []string{"attribute", "exists", "locals.foo"},
+			src:    src,
+			code:   exitNotFound,
+			msg:    false,
+			stdout: "",
+		},
+		{
+			name:   "block exists",
+			args:   []string{"block", "exists", "locals"},
+			src:    src,
+			code:   0,
+			stdout: "",
+		},
+		{
+			name:   "block does not exist",
+			args:   []string{"block", "exists", "resource.foo"},
+			src:    src,
+			code:   exitNotFound,
+			msg:    false,
+			stdout: "",
+		},
+		{
+			name:   "block exists with parse error",
+			args:   []string{"block", "exists", "locals"},
+			src:    "locals {",
+			code:   exitError,
+			msg:    true,
+			stdout: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd(), newBlockCmd())
+			setMockStreams(cmd, tc.src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stdout := mockOut(cmd)
+			if tc.code == 0 {
+				if err != nil {
+					t.Fatalf("unexpected err = %s", err)
+				}
+			} else {
+				exitErr, ok := err.(*ExitError)
+				if !ok {
+					t.Fatalf("expected to return an ExitError, but got: %#v", err)
+				}
+				if exitErr.Code != tc.code {
+					t.Errorf("got code = %d, but want = %d", exitErr.Code, tc.code)
+				}
+				if (exitErr.Err != nil) != tc.msg {
+					t.Errorf("got err = %v, but want message = %t", exitErr.Err, tc.msg)
+				}
+			}
+
+			if stdout != tc.stdout {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.stdout)
+			}
+		})
+	}
+}
+
+func TestRootFilename(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	hclPath := filepath.Join(dir, "main.tf")
+	jsonPath := filepath.Join(dir, "main.tf.json")
+	brokenPath := filepath.Join(dir, "broken.tf")
+	files := map[string]string{
+		hclPath:    "a0 = v0\n",
+		jsonPath:   "\n{\"a0\": \"v0\"}\n",
+		brokenPath: "a0 = \n",
+	}
+	for path, contents := range files {
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+	}
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "source range",
+			args: []string{"attribute", "get", "-f", hclPath, "--template", "{{.Range.Filename}}", "a0"},
+			ok:   true,
+			want: hclPath,
+		},
+		{
+			name: "json syntax",
+			args: []string{"attribute", "get", "-f", jsonPath, "a0"},
+			ok:   true,
+			want: "\"v0\"\n",
+		},
+		{
+			name: "json syntax is not supported",
+			args: []string{"attribute", "rm", "-f", jsonPath, "a0"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "parse error",
+			args: []string{"attribute", "get", "-f", brokenPath, "a0"},
+			ok:   false,
+			want: brokenPath,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd())
+			setMockStreams(cmd, "")
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+				}
+				// the error refers to the file.
+				if !strings.Contains(err.Error(), tc.want) {
+					t.Fatalf("expected the error to contain %q, but got: %s", tc.want, err)
+				}
+				return
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
+
+func TestRootIgnoreCase(t *testing.T) {
+	src := `source "amazon-ebs" "Ubuntu" {
+  ami_name = "ubuntu"
+  tags     = { Name = ["ubuntu"] }
+}
+`
+
+	cases := []struct {
+		name string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "attribute get",
+			args: []string{"attribute", "get", "--ignore-case", "Source.Amazon-EBS.ubuntu.ami_name"},
+			ok:   true,
+			want: "\"ubuntu\"\n",
+		},
+		{
+			name: "attribute name is case-sensitive",
+			args: []string{"attribute", "get", "--ignore-case", "source.amazon-ebs.ubuntu.AMI_NAME"},
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "attribute set",
+			args: []string{"attribute", "set", "--ignore-case", "source.amazon-ebs.ubuntu.ami_name", `"focal"`},
+			ok:   true,
+			want: `source "amazon-ebs" "Ubuntu" {
+  ami_name = "focal"
+  tags     = { Name = ["ubuntu"] }
+}
+`,
+		},
+		{
+			name: "attribute mv",
+			args: []string{"attribute", "mv", "--ignore-case", "Source.Amazon-EBS.ubuntu.ami_name", "source.amazon-ebs.UBUNTU.name"},
+			ok:   true,
+			want: `source "amazon-ebs" "Ubuntu" {
+  name = "ubuntu"
+  tags = { Name = ["ubuntu"] }
+}
+`,
+		},
+		{
+			name: "attribute add-element in a value of attribute",
+			args: []string{"attribute", "add-element", "--ignore-case", "SOURCE.amazon-ebs.ubuntu.tags.Name", `"focal"`},
+			ok:   true,
+			want: `source "amazon-ebs" "Ubuntu" {
+  ami_name = "ubuntu"
+  tags     = { Name = ["ubuntu", "focal"] }
+}
+`,
+		},
+		{
+			name: "attribute list",
+			args: []string{"attribute", "list", "--ignore-case", "SOURCE.amazon-ebs.ubuntu"},
+			ok:   true,
+			want: "ami_name\ntags\n",
+		},
+		{
+			name: "block get",
+			args: []string{"block", "get", "--ignore-case", "SOURCE.*.ubuntu"},
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "block rename",
+			args: []string{"block", "rename", "--ignore-case", "SOURCE.amazon-ebs.ubuntu", "amazon-ebs.focal"},
+			ok:   true,
+			want: `source "amazon-ebs" "focal" {
+  ami_name = "ubuntu"
+  tags     = { Name = ["ubuntu"] }
+}
+`,
+		},
+		{
+			name: "block wrap",
+			args: []string{"block", "wrap", "--ignore-case", "--into", "image", "SOURCE.amazon-ebs.ubuntu.ami_name"},
+			ok:   true,
+			want: `source "amazon-ebs" "Ubuntu" {
+  image {
+    ami_name = "ubuntu"
+  }
+  tags = { Name = ["ubuntu"] }
+}
+`,
+		},
+		{
+			name: "block count",
+			args: []string{"block", "count", "--ignore-case", "source.AMAZON-EBS.*"},
+			ok:   true,
+			want: "1\n",
+		},
+		{
+			name: "unsupported command",
+			args: []string{"block", "labels", "--ignore-case", "SOURCE"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "case-sensitive by default",
+			args: []string{"block", "get", "source.amazon-ebs.ubuntu"},
+			ok:   true,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.AddCommand(newAttributeCmd(), newBlockCmd())
+			setMockStreams(cmd, src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newServeCmd())
+}
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a JSON API over HTTP",
+		Long: `Serve a JSON API to edit HCL over HTTP
+
+It allows platforms to offer HCL editing without running hcledit per request.
+Each endpoint accepts a POST request with a JSON object and returns a JSON
+object. The source field is HCL to be edited. On failure, it returns an
+object with the error field and a 4xx status.
+
+  POST /parse   {"source": "..."}
+                returns {"addresses": [...]} of all blocks and attributes.
+  POST /query   {"source": "...", "query": "..."}
+                returns {"result": "..."} in the same way as the query command.
+  POST /apply   {"source": "...", "script": "..."}
+                or {"source": "...", "operations": [{"kind": "set", "address": "...", "value": "..."}]}
+                returns {"result": "..."} in the same way as the apply command.
+`,
+		RunE: runServeCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.String("listen", "127.0.0.1:8080", "An address to listen on")
+	flags.Int64("max-body-size", 1<<20, "A max size of a request body in bytes")
+
+	return cmd
+}
+
+func runServeCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected 0 argument, but got %d arguments", len(args))
+	}
+
+	if !readsStdin(cmd) {
+		return fmt.Errorf("serve cannot be used with --file or --recursive")
+	}
+
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return err
+	}
+	maxBodySize, err := cmd.Flags().GetInt64("max-body-size")
+	if err != nil {
+		return err
+	}
+	if maxBodySize < 1 {
+		return fmt.Errorf("--max-body-size must be at least 1: %d", maxBodySize)
+	}
+
+	server := &http.Server{
+		Addr:         listen,
+		Handler:      newServeHandler(maxBodySize),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "listening on %s\n", listen)
+	return server.ListenAndServe()
+}
+
+// serveRequest is a request body of the serve API.
+type serveRequest struct {
+	Source     string             `json:"source"`
+	Query      string             `json:"query,omitempty"`
+	Script     string             `json:"script,omitempty"`
+	Operations []editor.Operation `json:"operations,omitempty"`
+}
+
+// serveResponse is a response body of the serve API.
+// Result and Addresses are pointers, so that an empty result or an empty list
+// of addresses is written while unused fields are omitted.
+type serveResponse struct {
+	Result    *string   `json:"result,omitempty"`
+	Addresses *[]string `json:"addresses,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// serveFunc handles a decoded request and returns a response.
+// Each call edits its own copy of the source, so that requests never share
+// state.
+type serveFunc func(req *serveRequest) (*serveResponse, error)
+
+// newServeHandler returns a handler of the serve API which rejects request
+// bodies larger than maxBodySize.
+func newServeHandler(maxBodySize int64) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/parse", serveJSON(maxBodySize, serveParse))
+	mux.Handle("/query", serveJSON(maxBodySize, serveQuery))
+	mux.Handle("/apply", serveJSON(maxBodySize, serveApply))
+
+	return mux
+}
+
+// serveJSON returns a handler which decodes a request, calls fn, and encodes
+// the response.
+func serveJSON(maxBodySize int64, fn serveFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeServeResponse(w, http.StatusMethodNotAllowed, &serveResponse{Error: "method not allowed"})
+			return
+		}
+
+		// read one more byte to detect a body exceeding the limit.
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			writeServeResponse(w, http.StatusBadRequest, &serveResponse{Error: fmt.Sprintf("failed to read request: %s", err)})
+			return
+		}
+		if int64(len(body)) > maxBodySize {
+			writeServeResponse(w, http.StatusRequestEntityTooLarge, &serveResponse{Error: fmt.Sprintf("request body is too large. the limit is %d bytes", maxBodySize)})
+			return
+		}
+
+		var req serveRequest
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			writeServeResponse(w, http.StatusBadRequest, &serveResponse{Error: fmt.Sprintf("failed to decode request: %s", err)})
+			return
+		}
+
+		res, err := fn(&req)
+		if err != nil {
+			writeServeResponse(w, http.StatusUnprocessableEntity, &serveResponse{Error: err.Error()})
+			return
+		}
+
+		writeServeResponse(w, http.StatusOK, res)
+	})
+}
+
+// writeServeResponse writes a response as JSON with a given status code.
+func writeServeResponse(w http.ResponseWriter, status int, res *serveResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// the header has already been written, so we cannot report an error here.
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// serveParse returns addresses of all blocks and attributes in the source.
+func serveParse(req *serveRequest) (*serveResponse, error) {
+	out := new(bytes.Buffer)
+	if err := editor.ListAddresses(strings.NewReader(req.Source), out, "-", 0); err != nil {
+		return nil, err
+	}
+
+	addresses := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if out.Len() == 0 {
+		addresses = []string{}
+	}
+
+	return &serveResponse{Addresses: &addresses}, nil
+}
+
+// serveQuery returns a result of the query.
+func serveQuery(req *serveRequest) (*serveResponse, error) {
+	out := new(bytes.Buffer)
+	if err := editor.Query(strings.NewReader(req.Source), out, "-", req.Query); err != nil {
+		return nil, err
+	}
+
+	result := out.String()
+	return &serveResponse{Result: &result}, nil
+}
+
+// serveApply returns a result of applying the script or the operations.
+func serveApply(req *serveRequest) (*serveResponse, error) {
+	if len(req.Script) != 0 && len(req.Operations) != 0 {
+		return nil, fmt.Errorf("script and operations cannot be used together")
+	}
+
+	operations := req.Operations
+	if len(req.Script) != 0 {
+		var err error
+		operations, err = editor.ParseScript(strings.NewReader(req.Script))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := new(bytes.Buffer)
+	if err := editor.ApplyScript(strings.NewReader(req.Source), out, "-", operations); err != nil {
+		return nil, err
+	}
+
+	result := out.String()
+	return &serveResponse{Result: &result}, nil
+}
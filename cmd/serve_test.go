@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHandler(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "parse",
+			method: http.MethodPost,
+			path:   "/parse",
+			body:   `{"source": "a0 = v0\nb1 \"l1\" {\n  a1 = v1\n}\n"}`,
+			status: http.StatusOK,
+			want: `{"addresses":["a0","b1.l1","b1.l1.a1"]}
+`,
+		},
+		{
+			name:   "parse empty",
+			method: http.MethodPost,
+			path:   "/parse",
+			body:   `{"source": ""}`,
+			status: http.StatusOK,
+			want: `{"addresses":[]}
+`,
+		},
+		{
+			name:   "parse syntax error",
+			method: http.MethodPost,
+			path:   "/parse",
+			body:   `{"source": "a0 = "}`,
+			status: http.StatusUnprocessableEntity,
+			want:   `"error":`,
+		},
+		{
+			name:   "query",
+			method: http.MethodPost,
+			path:   "/query",
+			body:   `{"source": "b1 \"l1\" {\n  a1 = \"v1\"\n}\n", "query": ".b1.l1.a1"}`,
+			status: http.StatusOK,
+			want: `{"result":"\"v1\"\n"}
+`,
+		},
+		{
+			name:   "apply script",
+			method: http.MethodPost,
+			path:   "/apply",
+			body:   `{"source": "a0 = v0\n", "script": "set a0 v1\nappend a1 v2\n"}`,
+			status: http.StatusOK,
+			want: `{"result":"a0 = v1\na1 = v2\n"}
+`,
+		},
+		{
+			name:   "apply operations",
+			method: http.MethodPost,
+			path:   "/apply",
+			body:   `{"source": "a0 = v0\na1 = v1\n", "operations": [{"kind": "rm", "address": "a0"}]}`,
+			status: http.StatusOK,
+			want: `{"result":"a1 = v1\n"}
+`,
+		},
+		{
+			name:   "apply script and operations",
+			method: http.MethodPost,
+			path:   "/apply",
+			body:   `{"source": "a0 = v0\n", "script": "rm a0", "operations": [{"kind": "rm", "address": "a0"}]}`,
+			status: http.StatusUnprocessableEntity,
+			want: `{"error":"script and operations cannot be used together"}
+`,
+		},
+		{
+			name:   "unknown field",
+			method: http.MethodPost,
+			path:   "/parse",
+			body:   `{"source": "a0 = v0\n", "hoge": "fuga"}`,
+			status: http.StatusBadRequest,
+			want:   `"error":"failed to decode request`,
+		},
+		{
+			name:   "too large",
+			method: http.MethodPost,
+			path:   "/parse",
+			body:   `{"source": "` + strings.Repeat("a", 200) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+			want: `{"error":"request body is too large. the limit is 128 bytes"}
+`,
+		},
+		{
+			name:   "method not allowed",
+			method: http.MethodGet,
+			path:   "/parse",
+			body:   "",
+			status: http.StatusMethodNotAllowed,
+			want: `{"error":"method not allowed"}
+`,
+		},
+		{
+			name:   "not found",
+			method: http.MethodPost,
+			path:   "/hoge",
+			body:   `{"source": ""}`,
+			status: http.StatusNotFound,
+			want:   "404 page not found",
+		},
+	}
+
+	handler := newServeHandler(128)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("got status %d, want %d, body: \n%s", rec.Code, tc.status, rec.Body.String())
+			}
+
+			got := rec.Body.String()
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("got:\n%s\nwant to contain:\n%s", got, tc.want)
+			}
+		})
+	}
+}
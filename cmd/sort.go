@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newSortCmd())
+}
+
+func newSortCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sort",
+		Short: "Sort attributes or blocks",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newSortAttributesCmd(),
+		newSortBlocksCmd(),
+	)
+
+	return cmd
+}
+
+func newSortAttributesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attributes <ADDRESS>",
+		Short: "Sort attributes in block",
+		Long: `Sort attributes in matched blocks alphabetically by name
+
+Comments immediately above each attribute move with it.
+Nested blocks are not moved.
+
+Arguments:
+  ADDRESS          An address of blocks whose attributes are sorted.
+`,
+		RunE: runSortAttributesCmd,
+	}
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runSortAttributesCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	address := args[0]
+
+	return editor.SortAttributes(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address)
+}
+
+func newSortBlocksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blocks [<ADDRESS>]",
+		Short: "Sort blocks by type and labels",
+		Long: `Sort sibling blocks by type and then by labels
+
+If no address is given, top level blocks are sorted.
+Comments immediately above each block move with it.
+Attributes and blank lines between blocks are not moved.
+
+Arguments:
+  ADDRESS          An address of blocks whose nested blocks are sorted.
+                   (optional)
+`,
+		RunE: runSortBlocksCmd,
+	}
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runSortBlocksCmd(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return fmt.Errorf("expected at most 1 argument, but got %d arguments", len(args))
+	}
+
+	address := ""
+	if len(args) == 1 {
+		address = args[0]
+	}
+
+	return editor.SortBlocks(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), address)
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// addStreamFlag adds a flag to process the input in streaming mode.
+func addStreamFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("stream", false, `Process the input in streaming mode for very large files.
+Only top level items which may match the address are parsed and formatted, and others are written as they are.
+Input files are streamed one by one. It cannot be used with --diff or --parallel`)
+}
+
+// getStreamOptions returns options of editor for the streaming mode.
+func getStreamOptions(cmd *cobra.Command) ([]editor.Option, error) {
+	stream, err := cmd.Flags().GetBool("stream")
+	if err != nil {
+		return nil, err
+	}
+
+	if !stream {
+		return []editor.Option{}, nil
+	}
+
+	return []editor.Option{editor.WithStream()}, nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(newTerraformCmd())
+}
+
+func newTerraformCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "terraform",
+		Short: "Edit Terraform specific settings",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newTerraformRequiredVersionCmd(),
+	)
+
+	return cmd
+}
+
+func newTerraformRequiredVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "required-version <CONSTRAINT>",
+		Short: "Set required_version",
+		Long: `Set a version constraint to required_version of the terraform block
+
+The constraint is validated before writing. If the terraform block doesn't
+have required_version, it is added. If there is no terraform block, the
+input is not changed unless the --create flag is given.
+
+Arguments:
+  CONSTRAINT       A version constraint such as ">= 0.13".
+                   It is quoted for you, so don't escape double quotes.
+`,
+		RunE: runTerraformRequiredVersionCmd,
+	}
+
+	flags := cmd.Flags()
+	flags.Bool("create", false, "Create the terraform block if not found")
+
+	setUpdatable(cmd)
+
+	return cmd
+}
+
+func runTerraformRequiredVersionCmd(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected 1 argument, but got %d arguments", len(args))
+	}
+
+	constraint := args[0]
+	create, err := cmd.Flags().GetBool("create")
+	if err != nil {
+		return err
+	}
+
+	return editor.SetRequiredVersion(cmd.InOrStdin(), cmd.OutOrStdout(), inputFilename(cmd), constraint, create)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestTerraformRequiredVersion(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		args []string
+		ok   bool
+		want string
+	}{
+		{
+			name: "update",
+			src: `terraform {
+  required_version = ">= 0.12"
+}
+`,
+			args: []string{">= 0.13"},
+			ok:   true,
+			want: `terraform {
+  required_version = ">= 0.13"
+}
+`,
+		},
+		{
+			name: "create",
+			src: `provider "aws" {}
+`,
+			args: []string{"--create", ">= 0.13"},
+			ok:   true,
+			want: `provider "aws" {}
+
+terraform {
+  required_version = ">= 0.13"
+}
+`,
+		},
+		{
+			name: "invalid constraint",
+			src: `terraform {
+  required_version = ">= 0.12"
+}
+`,
+			args: []string{"foo"},
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "no args",
+			src:  "",
+			args: []string{},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := setMockStreams(newTerraformRequiredVersionCmd(), tc.src)
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			stderr := mockErr(cmd)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s, stderr: \n%s", err, stderr)
+			}
+
+			stdout := mockOut(cmd)
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, stdout: \n%s", stdout)
+			}
+
+			if stdout != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", stdout, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// runWatch runs a given command once, and again whenever any of given files
+// changes. An error of each run is written to stderr instead of stopping
+// watching, so that a broken intermediate state during editing is tolerated.
+// It returns only if it fails to watch files.
+func runWatch(cmd *cobra.Command, args []string, files []string, run func(cmd *cobra.Command, args []string) error) error {
+	report := func() {
+		if err := run(cmd, args); err != nil {
+			fmt.Fprintln(cmd.ErrOrStderr(), err)
+		}
+	}
+
+	report()
+	return editor.WatchFiles(nil, files, report)
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/minamijoyo/hcledit/editor"
+	"github.com/spf13/cobra"
+)
+
+// addWhereFlag adds a flag to select blocks by a predicate.
+func addWhereFlag(cmd *cobra.Command) {
+	cmd.Flags().String("where", "", `Select only blocks satisfying a given predicate on their attributes.
+e.g.) --where 'instance_type == "t2.micro"'
+Only constant values such as literals are compared, and a missing attribute is null`)
+}
+
+// getWhereOptions returns options of editor for selecting blocks by a
+// predicate.
+func getWhereOptions(cmd *cobra.Command) ([]editor.Option, error) {
+	where, err := cmd.Flags().GetString("where")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(where) == 0 {
+		return []editor.Option{}, nil
+	}
+
+	filter, err := editor.NewBlockWhereFilter(where)
+	if err != nil {
+		return nil, err
+	}
+
+	return []editor.Option{editor.WithFilters(filter)}, nil
+}
@@ -0,0 +1,324 @@
+package editor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// wildcardSegment is a segment of address which matches any label or nested
+// block type at that position.
+const wildcardSegment = "*"
+
+// recursiveSegment is a segment of address which matches zero or more labels
+// or nested blocks at any depth.
+const recursiveSegment = "**"
+
+// indexSuffixRegexp is a regular expression to match an index suffix of a
+// segment such as [1].
+var indexSuffixRegexp = regexp.MustCompile(`^\[[0-9]+\]`)
+
+// splitAddress splits a given address into segments separated by dots.
+// Leading and trailing dots are trimmed for convenience, but an empty segment
+// in the middle such as `a..b` is an error, because it would otherwise
+// silently match a block or an attribute whose name is an empty string.
+// A segment wrapped in slashes such as /prod_.*/ is a regular expression and
+// is not split even if it contains dots. A slash in the regular expression can
+// be escaped with a backslash.
+// A dot in a label can be escaped by quoting such as module."my.module" or by
+// a backslash such as module.my\.module.
+// Segments are returned in the raw form including quotes and escapes, so that
+// they can be joined again to an address. Use unquoteSegment to get a name.
+func splitAddress(address string) ([]string, error) {
+	a := []string{}
+	start := 0
+	i := 0
+	for i < len(address) {
+		switch c := address[i]; {
+		case c == '/' && i == start:
+			closing := indexRegexpEnd(address[i:])
+			if closing < 0 {
+				return nil, fmt.Errorf("failed to parse address. unterminated regular expression: %s", address)
+			}
+			i += closing + 1
+			if m := indexSuffixRegexp.FindString(address[i:]); m != "" {
+				// an index suffix can be attached to a regular expression.
+				i += len(m)
+			}
+			if i < len(address) && address[i] != '.' {
+				return nil, fmt.Errorf("failed to parse address. unexpected characters after regular expression: %s", address)
+			}
+		case c == '\\':
+			if i+1 >= len(address) {
+				return nil, fmt.Errorf("failed to parse address. trailing backslash: %s", address)
+			}
+			i += 2
+		case c == '"':
+			closing := indexQuoteEnd(address[i:])
+			if closing < 0 {
+				return nil, fmt.Errorf("failed to parse address. unterminated quote: %s", address)
+			}
+			i += closing + 1
+		case c == '.':
+			a = append(a, address[start:i])
+			start = i + 1
+			i++
+		default:
+			i++
+		}
+	}
+	a = append(a, address[start:])
+
+	// trim leading and trailing dots.
+	for len(a) != 0 && len(a[0]) == 0 {
+		a = a[1:]
+	}
+	for len(a) != 0 && len(a[len(a)-1]) == 0 {
+		a = a[:len(a)-1]
+	}
+
+	if len(a) == 0 {
+		return nil, errors.New("failed to parse address. address is empty")
+	}
+
+	for _, s := range a {
+		if len(s) == 0 {
+			return nil, fmt.Errorf("failed to parse address. address contains an empty segment: %s", address)
+		}
+	}
+
+	return a, nil
+}
+
+// indexQuoteEnd returns an index of the closing quote of a quoted string
+// which starts with a double quote, or -1 if not found.
+func indexQuoteEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// skip an escaped character.
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// quoteSegment returns a segment of address for a given name.
+// If the name contains characters which have special meanings in address, it
+// is quoted and escaped. Otherwise it is returned as it is.
+func quoteSegment(name string) string {
+	if len(name) != 0 && name != wildcardSegment && name != recursiveSegment && !strings.HasPrefix(name, "/") && !strings.ContainsAny(name, `."\[]`) {
+		return name
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(name); i++ {
+		if name[i] == '"' || name[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(name[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// unquoteSegment returns a name of a given segment of address by removing
+// quotes and backslash escapes.
+func unquoteSegment(segment string) string {
+	if !strings.ContainsAny(segment, `"\`) {
+		return segment
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		switch c := segment[i]; c {
+		case '"':
+			// quotes are just removed.
+		case '\\':
+			if i+1 < len(segment) {
+				i++
+				b.WriteByte(segment[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// indexRegexpEnd returns an index of the closing slash of a regular
+// expression segment which starts with a slash, or -1 if not found.
+func indexRegexpEnd(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// skip an escaped character.
+			i++
+		case '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// isRegexpSegment returns true if a given segment of address is a regular
+// expression wrapped in slashes.
+func isRegexpSegment(segment string) bool {
+	return len(segment) >= 2 && strings.HasPrefix(segment, "/") && strings.HasSuffix(segment, "/")
+}
+
+// containsPattern returns true if segments of address contain a wildcard, a
+// recursive descent or a regular expression, which may match multiple blocks.
+func containsPattern(segments []string) bool {
+	for _, s := range segments {
+		if s == wildcardSegment || s == recursiveSegment || isRegexpSegment(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRecursive returns true if segments of address contain a recursive
+// descent.
+func containsRecursive(segments []string) bool {
+	for _, s := range segments {
+		if s == recursiveSegment {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegment returns true if a given name such as a label or a block type
+// matches a segment of address. A wildcard (*) matches any name. If the
+// segment is wrapped in slashes, it is compiled as a regular expression which
+// must match the whole name. Otherwise it must equal to the name after
+// unquoting, so a quoted "*" matches only a literal asterisk.
+func matchSegment(segment string, name string) (bool, error) {
+	if segment == wildcardSegment {
+		return true, nil
+	}
+
+	if !isRegexpSegment(segment) {
+		return unquoteSegment(segment) == name, nil
+	}
+
+	pattern := strings.ReplaceAll(segment[1:len(segment)-1], `\/`, "/")
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return false, fmt.Errorf("failed to compile regular expression in address: %s: %s", segment, err)
+	}
+
+	return re.MatchString(name), nil
+}
+
+// IgnoreCaseAddress returns an address which matches block types and labels
+// case-insensitively. Each literal segment is rewritten to a regular
+// expression with the (?i) flag such as /(?i)aws_instance/, and an index
+// suffix is kept. Wildcards and regular expressions are left as they are.
+// If attribute is true, the last segment is an attribute name, which is
+// case-sensitive in HCL, so it is left as it is.
+func IgnoreCaseAddress(address string, attribute bool) (string, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return "", err
+	}
+
+	segments := a
+	if attribute {
+		segments = a[:len(a)-1]
+	}
+	for i, s := range segments {
+		if s == wildcardSegment || s == recursiveSegment || isRegexpSegment(s) {
+			continue
+		}
+		name, index, err := parseIndexedSegment(s)
+		if err != nil {
+			return "", err
+		}
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(unquoteSegment(name)), "/", `\/`)
+		a[i] = "/(?i)" + pattern + "/"
+		if index >= 0 {
+			a[i] += fmt.Sprintf("[%d]", index)
+		}
+	}
+
+	return strings.Join(a, "."), nil
+}
+
+// ignoreCaseSegmentRegexp is a regular expression to match a segment
+// rewritten by IgnoreCaseAddress with an optional index suffix.
+var ignoreCaseSegmentRegexp = regexp.MustCompile(`^/\(\?i\)(.*)/(\[[0-9]+\])?$`)
+
+// caseSensitiveSegment returns a literal segment for a segment rewritten by
+// IgnoreCaseAddress. IgnoreCaseAddress cannot know where an attribute name is
+// if an address refers to an element in a value of attribute such as
+// aaa.tags.key, but an attribute name and keys in a value are always
+// case-sensitive. Other segments are returned as they are.
+func caseSensitiveSegment(segment string) string {
+	m := ignoreCaseSegmentRegexp.FindStringSubmatch(segment)
+	if m == nil {
+		return segment
+	}
+
+	pattern := strings.ReplaceAll(m[1], `\/`, "/")
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] == '\\' && i+1 < len(pattern) {
+			i++
+		}
+		b.WriteByte(pattern[i])
+	}
+	name := b.String()
+	if regexp.QuoteMeta(name) != pattern {
+		// a regular expression given by the user.
+		return segment
+	}
+
+	return quoteSegment(name) + m[2]
+}
+
+// parseIndexedSegment parses a segment of address with an optional index
+// suffix such as ingress[1], and returns the name and the index.
+// If the segment has no index, the index is -1.
+func parseIndexedSegment(segment string) (string, int, error) {
+	if !strings.HasSuffix(segment, "]") || strings.HasSuffix(segment, `\]`) {
+		return segment, -1, nil
+	}
+
+	open := strings.LastIndex(segment, "[")
+	if open > 0 && segment[open-1] == '\\' {
+		// an escaped bracket is a part of the name.
+		return segment, -1, nil
+	}
+	if open <= 0 {
+		return "", -1, fmt.Errorf("failed to parse address. invalid index: %s", segment)
+	}
+
+	index, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+	if err != nil || index < 0 {
+		return "", -1, fmt.Errorf("failed to parse address. invalid index: %s", segment)
+	}
+
+	return segment[:open], index, nil
+}
+
+// selectIndex returns a block at a given index as a slice.
+// If the index is negative, it returns all blocks as they are.
+// If the index is out of range, it returns an empty slice.
+func selectIndex(blocks []*hclwrite.Block, index int) []*hclwrite.Block {
+	if index < 0 {
+		return blocks
+	}
+	if index >= len(blocks) {
+		return []*hclwrite.Block{}
+	}
+	return []*hclwrite.Block{blocks[index]}
+}
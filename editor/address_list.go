@@ -0,0 +1,69 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// ListAddresses reads HCL from io.Reader, and writes addresses of all blocks
+// and attributes in the file to io.Writer, one per line.
+// The file is walked depth first. In each body, attributes are listed first
+// in source order, and then each block is listed followed by its contents.
+// If there are multiple blocks with the same address in a body, the second
+// and subsequent ones are addressed with an index suffix such as foo[1], so
+// that every address can be given to other commands as it is.
+// If maxDepth is positive, items nested deeper than maxDepth are not listed.
+// Top level items are at depth 1.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ListAddresses(r io.Reader, w io.Writer, filename string, maxDepth int) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &addressList{maxDepth: maxDepth},
+	}
+
+	return e.Apply(r, w)
+}
+
+// addressList is a Sink implementation to list all addresses.
+type addressList struct {
+	// maxDepth is a max depth of items to be listed.
+	// If not positive, there is no limit.
+	maxDepth int
+}
+
+// Sink reads HCL and writes addresses of all blocks and attributes.
+func (s *addressList) Sink(inFile *hclwrite.File) ([]byte, error) {
+	var buf bytes.Buffer
+	s.listBody(&buf, "", inFile.Body(), 1)
+
+	return buf.Bytes(), nil
+}
+
+// listBody writes addresses of items in a given body recursively.
+// A prefix is an address of the block containing them.
+func (s *addressList) listBody(w *bytes.Buffer, prefix string, body *hclwrite.Body, depth int) {
+	if s.maxDepth > 0 && depth > s.maxDepth {
+		return
+	}
+
+	for _, name := range attributeNames(body) {
+		fmt.Fprintln(w, joinAddress(prefix, name))
+	}
+
+	// counts is the number of blocks seen so far for each address.
+	counts := make(map[string]int)
+	for _, b := range body.Blocks() {
+		addr := toAddress(b)
+		n := counts[addr]
+		counts[addr]++
+
+		indexed := joinAddress(prefix, indexedAddress(addr, n))
+		fmt.Fprintln(w, indexed)
+		s.listBody(w, indexed, b.Body(), depth+1)
+	}
+}
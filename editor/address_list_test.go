@@ -0,0 +1,100 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestListAddresses(t *testing.T) {
+	src := `a0 = v0
+
+resource "aws_security_group" "web" {
+  name = "web"
+
+  ingress {
+    from_port = 80
+  }
+
+  ingress {
+    from_port = 443
+  }
+}
+
+module "a.b" {
+}
+`
+
+	cases := []struct {
+		name     string
+		src      string
+		maxDepth int
+		ok       bool
+		want     string
+	}{
+		{
+			name:     "all",
+			src:      src,
+			maxDepth: 0,
+			ok:       true,
+			want: `a0
+resource.aws_security_group.web
+resource.aws_security_group.web.name
+resource.aws_security_group.web.ingress
+resource.aws_security_group.web.ingress.from_port
+resource.aws_security_group.web.ingress[1]
+resource.aws_security_group.web.ingress[1].from_port
+module."a.b"
+`,
+		},
+		{
+			name:     "max depth 1",
+			src:      src,
+			maxDepth: 1,
+			ok:       true,
+			want: `a0
+resource.aws_security_group.web
+module."a.b"
+`,
+		},
+		{
+			name:     "max depth 2",
+			src:      src,
+			maxDepth: 2,
+			ok:       true,
+			want: `a0
+resource.aws_security_group.web
+resource.aws_security_group.web.name
+resource.aws_security_group.web.ingress
+resource.aws_security_group.web.ingress[1]
+module."a.b"
+`,
+		},
+		{
+			name:     "empty",
+			src:      "",
+			maxDepth: 0,
+			ok:       true,
+			want:     "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ListAddresses(inStream, outStream, "test", tc.maxDepth)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,518 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitAddress(t *testing.T) {
+	cases := []struct {
+		name    string
+		address string
+		ok      bool
+		want    []string
+	}{
+		{
+			name:    "single",
+			address: "a0",
+			ok:      true,
+			want:    []string{"a0"},
+		},
+		{
+			name:    "multiple",
+			address: "b1.l1.a1",
+			ok:      true,
+			want:    []string{"b1", "l1", "a1"},
+		},
+		{
+			name:    "leading and trailing dots",
+			address: ".b1.l1.",
+			ok:      true,
+			want:    []string{"b1", "l1"},
+		},
+		{
+			name:    "regexp segment containing dots",
+			address: "resource./aws_.*/./prod\\..*/.ami",
+			ok:      true,
+			want:    []string{"resource", "/aws_.*/", "/prod\\..*/", "ami"},
+		},
+		{
+			name:    "regexp segment with an escaped slash",
+			address: "b1./a\\/b/",
+			ok:      true,
+			want:    []string{"b1", "/a\\/b/"},
+		},
+		{
+			name:    "regexp segment with an index",
+			address: "b1./a.b/[1].a1",
+			ok:      true,
+			want:    []string{"b1", "/a.b/[1]", "a1"},
+		},
+		{
+			name:    "quoted segment containing dots",
+			address: `module."my.module".source`,
+			ok:      true,
+			want:    []string{"module", `"my.module"`, "source"},
+		},
+		{
+			name:    "escaped dot",
+			address: `module.my\.module.source`,
+			ok:      true,
+			want:    []string{"module", `my\.module`, "source"},
+		},
+		{
+			name:    "escaped trailing dot is not trimmed",
+			address: `b1.l1\.`,
+			ok:      true,
+			want:    []string{"b1", `l1\.`},
+		},
+		{
+			name:    "unterminated quote",
+			address: `module."my.module`,
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "trailing backslash",
+			address: `module.my\`,
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "unterminated regexp segment",
+			address: "b1./a.b",
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "unexpected characters after regexp segment",
+			address: "b1./a/b.c",
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "empty",
+			address: "",
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "dots only",
+			address: "..",
+			ok:      false,
+			want:    nil,
+		},
+		{
+			name:    "empty interior segment",
+			address: "b1..a1",
+			ok:      false,
+			want:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := splitAddress(tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %#v", got)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchSegment(t *testing.T) {
+	cases := []struct {
+		name    string
+		segment string
+		target  string
+		ok      bool
+		want    bool
+	}{
+		{
+			name:    "literal",
+			segment: "prod_web",
+			target:  "prod_web",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name:    "literal is not a pattern",
+			segment: "prod_.*",
+			target:  "prod_web",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name:    "regexp",
+			segment: "/prod_.*/",
+			target:  "prod_web",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name:    "regexp must match the whole label",
+			segment: "/prod/",
+			target:  "prod_web",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name:    "escaped slash",
+			segment: "/a\\/b/",
+			target:  "a/b",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name:    "invalid regexp",
+			segment: "/prod_(/",
+			target:  "prod_web",
+			ok:      false,
+			want:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := matchSegment(tc.segment, tc.target)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseIndexedSegment(t *testing.T) {
+	cases := []struct {
+		name      string
+		segment   string
+		ok        bool
+		wantName  string
+		wantIndex int
+	}{
+		{
+			name:      "no index",
+			segment:   "ingress",
+			ok:        true,
+			wantName:  "ingress",
+			wantIndex: -1,
+		},
+		{
+			name:      "index",
+			segment:   "ingress[1]",
+			ok:        true,
+			wantName:  "ingress",
+			wantIndex: 1,
+		},
+		{
+			name:      "regexp with index",
+			segment:   "/prod_.*/[0]",
+			ok:        true,
+			wantName:  "/prod_.*/",
+			wantIndex: 0,
+		},
+		{
+			name:      "escaped brackets",
+			segment:   `foo\[1\]`,
+			ok:        true,
+			wantName:  `foo\[1\]`,
+			wantIndex: -1,
+		},
+		{
+			name:      "quoted with index",
+			segment:   `"my.module"[1]`,
+			ok:        true,
+			wantName:  `"my.module"`,
+			wantIndex: 1,
+		},
+		{
+			name:      "not a number",
+			segment:   "ingress[x]",
+			ok:        false,
+			wantName:  "",
+			wantIndex: -1,
+		},
+		{
+			name:      "negative",
+			segment:   "ingress[-1]",
+			ok:        false,
+			wantName:  "",
+			wantIndex: -1,
+		},
+		{
+			name:      "no name",
+			segment:   "[1]",
+			ok:        false,
+			wantName:  "",
+			wantIndex: -1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gotName, gotIndex, err := parseIndexedSegment(tc.segment)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if gotName != tc.wantName || gotIndex != tc.wantIndex {
+				t.Fatalf("got: (%s, %d), want: (%s, %d)", gotName, gotIndex, tc.wantName, tc.wantIndex)
+			}
+		})
+	}
+}
+
+func TestUnquoteSegment(t *testing.T) {
+	cases := []struct {
+		segment string
+		want    string
+	}{
+		{segment: "foo", want: "foo"},
+		{segment: `"my.module"`, want: "my.module"},
+		{segment: `my\.module`, want: "my.module"},
+		{segment: `"a\"b"`, want: `a"b`},
+		{segment: `"*"`, want: "*"},
+		{segment: `""`, want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.segment, func(t *testing.T) {
+			got := unquoteSegment(tc.segment)
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestQuoteSegment(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{name: "foo", want: "foo"},
+		{name: "my.module", want: `"my.module"`},
+		{name: `a"b`, want: `"a\"b"`},
+		{name: "*", want: `"*"`},
+		{name: "", want: `""`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := quoteSegment(tc.name)
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+
+			if unquoteSegment(got) != tc.name {
+				t.Fatalf("failed to round trip: %s", got)
+			}
+		})
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		name     string
+		segments []string
+		path     []string
+		ok       bool
+		want     bool
+	}{
+		{
+			name:     "recursive descent only",
+			segments: []string{"**"},
+			path:     []string{"b1", "l1", "b2"},
+			ok:       true,
+			want:     true,
+		},
+		{
+			name:     "recursive descent at the beginning",
+			segments: []string{"**", "b2"},
+			path:     []string{"b1", "l1", "b2"},
+			ok:       true,
+			want:     true,
+		},
+		{
+			name:     "recursive descent matches zero segments",
+			segments: []string{"b1", "**", "l1"},
+			path:     []string{"b1", "l1"},
+			ok:       true,
+			want:     true,
+		},
+		{
+			name:     "recursive descent in the middle",
+			segments: []string{"b1", "**", "b3"},
+			path:     []string{"b1", "l1", "b2", "b3"},
+			ok:       true,
+			want:     true,
+		},
+		{
+			name:     "trailing segments must match",
+			segments: []string{"**", "b2"},
+			path:     []string{"b1", "b2", "b3"},
+			ok:       true,
+			want:     false,
+		},
+		{
+			name:     "combined with a wildcard and a regexp",
+			segments: []string{"/b[0-9]/", "*", "**", "b3"},
+			path:     []string{"b1", "l1", "b3"},
+			ok:       true,
+			want:     true,
+		},
+		{
+			name:     "invalid regexp",
+			segments: []string{"**", "/b(/"},
+			path:     []string{"b1"},
+			ok:       false,
+			want:     false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := matchGlob(tc.segments, tc.path)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreCaseAddress(t *testing.T) {
+	cases := []struct {
+		name      string
+		address   string
+		attribute bool
+		ok        bool
+		want      string
+	}{
+		{
+			name:    "block",
+			address: "source.amazon-ebs.Ubuntu",
+			ok:      true,
+			want:    "/(?i)source/./(?i)amazon-ebs/./(?i)Ubuntu/",
+		},
+		{
+			name:      "attribute name is case-sensitive",
+			address:   "build.Name",
+			attribute: true,
+			ok:        true,
+			want:      "/(?i)build/.Name",
+		},
+		{
+			name:      "top level attribute",
+			address:   "Name",
+			attribute: true,
+			ok:        true,
+			want:      "Name",
+		},
+		{
+			name:    "patterns and index",
+			address: "resource.*./aws_.*/.Web[1]",
+			ok:      true,
+			want:    "/(?i)resource/.*./aws_.*/./(?i)Web/[1]",
+		},
+		{
+			name:    "quoted and special characters",
+			address: `module."my.Module/v1"`,
+			ok:      true,
+			want:    `/(?i)module/./(?i)my\.Module\/v1/`,
+		},
+		{
+			name:    "empty",
+			address: "",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := IgnoreCaseAddress(tc.address, tc.attribute)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCaseSensitiveSegment(t *testing.T) {
+	cases := []struct {
+		name    string
+		segment string
+		want    string
+	}{
+		{
+			name:    "rewritten",
+			segment: "/(?i)tags/",
+			want:    "tags",
+		},
+		{
+			name:    "rewritten with index",
+			segment: "/(?i)ingress/[1]",
+			want:    "ingress[1]",
+		},
+		{
+			name:    "rewritten with special characters",
+			segment: `/(?i)my\.Module\/v1/`,
+			want:    `"my.Module/v1"`,
+		},
+		{
+			name:    "regular expression",
+			segment: "/(?i)aws_.*/",
+			want:    "/(?i)aws_.*/",
+		},
+		{
+			name:    "literal",
+			segment: "tags",
+			want:    "tags",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := caseSensitiveSegment(tc.segment)
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
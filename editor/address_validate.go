@@ -0,0 +1,43 @@
+package editor
+
+import (
+	"io"
+)
+
+// ValidateAddresses reads HCL from io.Reader, and returns a subset of given
+// addresses which do not resolve to any attribute or block.
+// An address is considered to resolve if it matches an attribute, or it
+// matches at least one block. This is useful for confirming that every
+// target address exists before running a batch of operations.
+// It returns an error if the input cannot be parsed or any of addresses is
+// malformed.
+// Note that a filename is used only for an error message.
+func ValidateAddresses(r io.Reader, filename string, addresses []string) ([]string, error) {
+	inFile, err := parseInput(r, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved := []string{}
+	for _, address := range addresses {
+		attr, _, err := findAttribute(inFile.Body(), address)
+		if err != nil {
+			return nil, err
+		}
+		if attr != nil {
+			continue
+		}
+
+		blocks, err := findLongestMatchingBlocks(inFile.Body(), address)
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) != 0 {
+			continue
+		}
+
+		unresolved = append(unresolved, address)
+	}
+
+	return unresolved, nil
+}
@@ -0,0 +1,74 @@
+package editor
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestValidateAddresses(t *testing.T) {
+	src := `
+a0 = v0
+b1 "l1" {
+  a1 = v1
+  b2 {
+    a2 = v2
+  }
+}
+`
+
+	cases := []struct {
+		name      string
+		src       string
+		addresses []string
+		ok        bool
+		want      []string
+	}{
+		{
+			name:      "all resolved",
+			src:       src,
+			addresses: []string{"a0", "b1.l1.a1", "b1.l1", "b1.l1.b2", "b1.l1.b2.a2"},
+			ok:        true,
+			want:      []string{},
+		},
+		{
+			name:      "some unresolved",
+			src:       src,
+			addresses: []string{"a0", "a1", "b1.l2", "b1.l1.b2.a3"},
+			ok:        true,
+			want:      []string{"a1", "b1.l2", "b1.l1.b2.a3"},
+		},
+		{
+			name:      "empty address",
+			src:       src,
+			addresses: []string{"a0", ""},
+			ok:        false,
+			want:      nil,
+		},
+		{
+			name:      "parse error",
+			src:       "a0 = ",
+			addresses: []string{"a0"},
+			ok:        false,
+			want:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			got, err := ValidateAddresses(inStream, "test", tc.addresses)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %#v", got)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// AddElement reads HCL from io.Reader, and appends a value to a list of
+// matched attribute, and writes the updated HCL to io.Writer.
+// The address can also refer to a list in a value of attribute such as
+// a.b[0] or a.tags.key.
+// The value is inserted after the last element in the same style as the
+// list. If the list spans multiple lines, the value is placed on a new line
+// and a trailing comma is added only if the list already has one. Otherwise,
+// the value is appended on the same line.
+// It returns an error if the matched value is not a list.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AddElement(r io.Reader, w io.Writer, filename string, address string, value string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&elementAdd{address: address, value: value},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// elementAdd is a filter implementation for appending an element to a list.
+type elementAdd struct {
+	address string
+	value   string
+}
+
+// Filter reads HCL and appends a value to matched lists.
+func (f *elementAdd) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matches, err := findAttributeValues(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		if _, ok := m.expr.(*hclsyntax.TupleConsExpr); !ok {
+			return nil, fmt.Errorf("failed to add element. the value is not a list: %s", f.address)
+		}
+
+		if err := m.appendItem(f.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return inFile, nil
+}
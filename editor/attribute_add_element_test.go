@@ -0,0 +1,163 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestElementAdd(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "single line",
+			src: `
+a = ["x", "y"]
+`,
+			address: "a",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = ["x", "y", "z"]
+`,
+		},
+		{
+			name: "empty",
+			src: `
+a = []
+`,
+			address: "a",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = ["z"]
+`,
+		},
+		{
+			name: "multi-line with trailing comma",
+			src: `
+b {
+  a = [
+    "x", # comment x
+    "y", # comment y
+  ]
+}
+`,
+			address: "b.a",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+b {
+  a = [
+    "x", # comment x
+    "y", # comment y
+    "z",
+  ]
+}
+`,
+		},
+		{
+			name: "multi-line without trailing comma",
+			src: `
+a = [
+  "x",
+  "y" # comment y
+]
+`,
+			address: "a",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = [
+  "x",
+  "y", # comment y
+  "z"
+]
+`,
+		},
+		{
+			name: "multi-line empty",
+			src: `
+a = [
+]
+`,
+			address: "a",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = [
+  "z",
+]
+`,
+		},
+		{
+			name: "nested list",
+			src: `
+a = { k = ["x"] }
+`,
+			address: "a.k",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = { k = ["x", "z"] }
+`,
+		},
+		{
+			name: "not a list",
+			src: `
+a = "x"
+`,
+			address: "a",
+			value:   `"z"`,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "invalid value",
+			src: `
+a = ["x"]
+`,
+			address: "a",
+			value:   `"z`,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "not found",
+			src: `
+a = ["x"]
+`,
+			address: "b",
+			value:   `"z"`,
+			ok:      true,
+			want: `
+a = ["x"]
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AddElement(inStream, outStream, "test", tc.address, tc.value)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package editor
+
+import (
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// targetMarker is an inline comment to mark a target attribute.
+const targetMarker = "# <-- target"
+
+// AnnotateTarget reads HCL from io.Reader, and writes the whole file to
+// io.Writer with an inline comment `# <-- target` appended to the line of
+// the matched attribute, without changing anything else.
+// It is useful for confirming an address before editing.
+// If the attribute is not found, the file is written unchanged.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AnnotateTarget(r io.Reader, w io.Writer, filename string, address string) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &attributeAnnotate{address: address},
+	}
+
+	return e.Apply(r, w)
+}
+
+// attributeAnnotate is a Sink implementation to mark an attribute.
+type attributeAnnotate struct {
+	address string
+}
+
+// Sink reads HCL and writes formatted contents with a marker comment.
+func (s *attributeAnnotate) Sink(inFile *hclwrite.File) ([]byte, error) {
+	attr, _, err := findAttribute(inFile.Body(), s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := inFile.BuildTokens(nil)
+	if attr != nil {
+		tokens = annotateTokens(tokens, attr.BuildTokens(nil))
+	}
+
+	return formatHCL(tokens.Bytes()), nil
+}
+
+// annotateTokens returns a copy of tokens with a marker comment appended to
+// the end of the last line of a given sub tokens.
+func annotateTokens(tokens hclwrite.Tokens, sub hclwrite.Tokens) hclwrite.Tokens {
+	_, end := findTokens(tokens, sub)
+	if end < 0 {
+		return tokens
+	}
+
+	marker := &hclwrite.Token{
+		Type:         hclsyntax.TokenComment,
+		Bytes:        []byte(targetMarker),
+		SpacesBefore: 1,
+	}
+	newline := &hclwrite.Token{
+		Type:  hclsyntax.TokenNewline,
+		Bytes: []byte("\n"),
+	}
+
+	annotated := hclwrite.Tokens{}
+	annotated = append(annotated, tokens[:end-1]...)
+	last := tokens[end-1]
+	switch {
+	case last.Type == hclsyntax.TokenNewline:
+		annotated = append(annotated, marker, last)
+	case last.Type == hclsyntax.TokenComment && endsWithNewline(last):
+		// An existing inline comment contains a trailing newline.
+		// Since a comment continues to the end of line, we simply append the
+		// marker to the comment.
+		comment := &hclwrite.Token{
+			Type:         last.Type,
+			Bytes:        []byte(strings.TrimRight(string(last.Bytes), "\r\n") + " " + targetMarker),
+			SpacesBefore: last.SpacesBefore,
+		}
+		annotated = append(annotated, comment, newline)
+	default:
+		// The attribute is at the end of file without a trailing newline.
+		annotated = append(annotated, last, marker, newline)
+	}
+	annotated = append(annotated, tokens[end:]...)
+
+	return annotated
+}
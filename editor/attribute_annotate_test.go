@@ -0,0 +1,120 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeAnnotate(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `
+b1 {
+  a1 = v1
+  a2 = v2
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1 # <-- target
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "existing inline comment",
+			src: `
+b1 {
+  // lead
+  a1 = v1 // inline
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want: `
+b1 {
+  // lead
+  a1 = v1 // inline # <-- target
+}
+`,
+		},
+		{
+			name: "multi-line expression",
+			src: `
+a1 = [
+  v1,
+]
+a2 = v2
+`,
+			address: "a1",
+			ok:      true,
+			want: `
+a1 = [
+  v1,
+] # <-- target
+a2 = v2
+`,
+		},
+		{
+			name:    "without trailing newline",
+			src:     `a1 = v1`,
+			address: "a1",
+			ok:      true,
+			want: `a1 = v1 # <-- target
+`,
+		},
+		{
+			name: "not found",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.hoge",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "empty address",
+			src: `
+a1 = v1
+`,
+			address: "",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AnnotateTarget(inStream, outStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package editor
+
+import (
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// AppendAttribute reads HCL from io.Reader, and appends a new attribute to
+// matched blocks at a given address only if it doesn't already exist, and
+// writes the updated HCL to io.Writer.
+// The last element of the address is an attribute name, and the rest is the
+// address of parent blocks. If the address doesn't contain any dots, the new
+// attribute is appended to the top level body.
+// Unlike SetAttribute, an existing attribute is never overwritten.
+// If newline is true, a new line is inserted before the new attribute.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AppendAttribute(r io.Reader, w io.Writer, filename string, address string, value string, newline bool, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeAppend{address: address, value: value, newline: newline},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeAppend is a filter implementation for appending a new attribute.
+type attributeAppend struct {
+	address string
+	value   string
+	// newline is a flag to insert a new line before the new attribute.
+	newline bool
+}
+
+// Filter reads HCL and appends a new attribute to matched blocks.
+func (f *attributeAppend) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	a, err := splitAddress(f.address)
+	if err != nil {
+		return nil, err
+	}
+	attrName := unquoteSegment(a[len(a)-1])
+
+	var bodies []*hclwrite.Body
+	if len(a) == 1 {
+		bodies = append(bodies, inFile.Body())
+	} else {
+		matched, err := findLongestMatchingBlocks(inFile.Body(), strings.Join(a[:len(a)-1], "."))
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range matched {
+			bodies = append(bodies, b.Body())
+		}
+	}
+
+	expr, err := buildExpression(attrName, f.value)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, body := range bodies {
+		if body.GetAttribute(attrName) != nil {
+			// never overwrite an existing attribute.
+			continue
+		}
+		if f.newline {
+			body.AppendNewline()
+		}
+		body.SetAttributeRaw(attrName, expr.BuildTokens(nil))
+	}
+
+	return inFile, nil
+}
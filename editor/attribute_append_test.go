@@ -0,0 +1,150 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeAppend(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		newline bool
+		ok      bool
+		want    string
+	}{
+		{
+			name: "top level",
+			src: `
+a0 = v0
+`,
+			address: "a1",
+			value:   "v1",
+			ok:      true,
+			want: `
+a0 = v0
+a1 = v1
+`,
+		},
+		{
+			name: "in block",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.a2",
+			value:   `"v2"`,
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+  a2 = "v2"
+}
+`,
+		},
+		{
+			name: "with newline",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.a2",
+			value:   "v2",
+			newline: true,
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "existing attribute is not overwritten",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.a1",
+			value:   "v2",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "multiple blocks",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+}
+`,
+			address: "b1.*.a1",
+			value:   "v2",
+			ok:      true,
+			want: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  a1 = v2
+}
+`,
+		},
+		{
+			name: "block not found",
+			src: `
+b1 {
+}
+`,
+			address: "b2.a1",
+			value:   "v1",
+			ok:      true,
+			want: `
+b1 {
+}
+`,
+		},
+		{
+			name: "invalid value",
+			src: `
+b1 {
+}
+`,
+			address: "b1.a1",
+			value:   "{",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AppendAttribute(inStream, outStream, "test", tc.address, tc.value, tc.newline)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AuditAttributes reads HCL from io.Reader, and writes a TSV of all
+// attributes including nested blocks to io.Writer.
+// Each row consists of a block address, an attribute name, an inferred type
+// and a raw value of the attribute. The block address of top level attributes
+// is empty. The first row is a header.
+// Tabs, newlines and backslashes in values are escaped, so that the TSV stays
+// one row per attribute.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AuditAttributes(r io.Reader, w io.Writer, filename string, opts ...Option) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &attributeAudit{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeAudit is a Sink implementation to get a TSV of attributes.
+type attributeAudit struct {
+}
+
+// Sink reads HCL and writes a TSV of attributes.
+func (s *attributeAudit) Sink(inFile *hclwrite.File) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("address\tname\ttype\tvalue\n")
+
+	auditBody(&buf, inFile.Body(), "")
+
+	return buf.Bytes(), nil
+}
+
+// auditBody writes rows of attributes in a given body and nested blocks
+// recursively in the source order.
+func auditBody(buf *bytes.Buffer, body *hclwrite.Body, address string) {
+	for _, name := range attributeNames(body) {
+		value := getExpressionAsString(body.GetAttribute(name).Expr())
+		row := []string{address, name, inferExpressionType(value), escapeTSV(value)}
+		buf.WriteString(strings.Join(row, "\t") + "\n")
+	}
+
+	for _, b := range body.Blocks() {
+		blockAddr := toAddress(b)
+		if len(address) != 0 {
+			blockAddr = address + "." + blockAddr
+		}
+		auditBody(buf, b.Body(), blockAddr)
+	}
+}
+
+// Inferred types of expressions.
+const (
+	exprTypeString = "string"
+	exprTypeNumber = "number"
+	exprTypeBool   = "bool"
+	exprTypeNull   = "null"
+	exprTypeList   = "list"
+	exprTypeMap    = "map"
+	// exprTypeRaw is a type of expression which cannot be inferred statically,
+	// such as references and function calls.
+	exprTypeRaw = "raw"
+)
+
+// inferExpressionType returns a type name of a given expression inferred
+// from its syntax without evaluation.
+func inferExpressionType(expr string) string {
+	parsed, diags := parseValueExpression(expr)
+	if diags.HasErrors() {
+		return exprTypeRaw
+	}
+
+	switch e := parsed.(type) {
+	case *hclsyntax.TemplateExpr, *hclsyntax.TemplateWrapExpr:
+		return exprTypeString
+	case *hclsyntax.TupleConsExpr:
+		return exprTypeList
+	case *hclsyntax.ObjectConsExpr:
+		return exprTypeMap
+	case *hclsyntax.LiteralValueExpr:
+		switch {
+		case e.Val.IsNull():
+			return exprTypeNull
+		case e.Val.Type() == cty.Number:
+			return exprTypeNumber
+		case e.Val.Type() == cty.Bool:
+			return exprTypeBool
+		default:
+			return exprTypeRaw
+		}
+	default:
+		return exprTypeRaw
+	}
+}
+
+// escapeTSV escapes tabs, newlines and backslashes in a given value.
+func escapeTSV(value string) string {
+	r := strings.NewReplacer(
+		"\\", "\\\\",
+		"\t", "\\t",
+		"\n", "\\n",
+		"\r", "\\r",
+	)
+	return r.Replace(value)
+}
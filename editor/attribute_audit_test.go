@@ -0,0 +1,81 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAuditAttributes(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			src: `
+a0 = "v0"
+b1 "l1" {
+  a1 = 1
+  a2 = true
+  a3 = null
+  b2 {
+    a4 = [1, 2]
+    a5 = { k = "v" }
+  }
+  a6 = var.x
+}
+`,
+			ok: true,
+			want: "address\tname\ttype\tvalue\n" +
+				"\ta0\tstring\t\"v0\"\n" +
+				"b1.l1\ta1\tnumber\t1\n" +
+				"b1.l1\ta2\tbool\ttrue\n" +
+				"b1.l1\ta3\tnull\tnull\n" +
+				"b1.l1\ta6\traw\tvar.x\n" +
+				"b1.l1.b2\ta4\tlist\t[1, 2]\n" +
+				"b1.l1.b2\ta5\tmap\t{ k = \"v\" }\n",
+		},
+		{
+			name: "escape",
+			src: `
+a0 = "a\tb"
+a1 = [
+  1,
+  2,
+]
+`,
+			ok: true,
+			want: "address\tname\ttype\tvalue\n" +
+				"\ta0\tstring\t\"a\\\\tb\"\n" +
+				"\ta1\tlist\t[\\n  1,\\n  2,\\n]\n",
+		},
+		{
+			name: "empty",
+			src:  "",
+			ok:   true,
+			want: "address\tname\ttype\tvalue\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AuditAttributes(inStream, outStream, "test")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package editor
+
+import (
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// EditAttribute reads HCL from io.Reader, and replaces a value of matched
+// attribute at a given address with a result of a given edit function, and
+// writes the updated HCL to io.Writer.
+// The edit function receives the current expression as it is and returns a
+// new raw expression, which may span multiple lines in the same way as
+// SetAttributeRawMultiline. This is intended for editing a long value
+// interactively with an external editor.
+// It returns a NotFoundError if the attribute is not found.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func EditAttribute(r io.Reader, w io.Writer, filename string, address string, edit func(value string) (string, error), opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeEdit{address: address, edit: edit},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeEdit is a filter implementation to edit a value of attribute.
+type attributeEdit struct {
+	address string
+	edit    func(value string) (string, error)
+}
+
+// Filter reads HCL and replaces a value of matched attribute with an edited one.
+func (f *attributeEdit) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	attr, _, err := findAttribute(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if attr == nil {
+		return nil, &NotFoundError{Address: f.address}
+	}
+
+	value, err := f.edit(getExpressionAsString(attr.Expr()))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &attributeSet{address: f.address, value: value, multiline: true}
+	return set.Filter(inFile)
+}
@@ -0,0 +1,110 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAttributeEdit(t *testing.T) {
+	src := `resource "foo" "bar" {
+  attr1  = "val1"
+  policy = <<-EOT
+    {
+      "Version": "2012-10-17"
+    }
+  EOT
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		edit    func(value string) (string, error)
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "simple",
+			address: "resource.foo.bar.attr1",
+			edit: func(value string) (string, error) {
+				return strings.Replace(value, "val1", "val2", 1), nil
+			},
+			ok: true,
+			want: `resource "foo" "bar" {
+  attr1  = "val2"
+  policy = <<-EOT
+    {
+      "Version": "2012-10-17"
+    }
+  EOT
+}
+`,
+		},
+		{
+			name:    "heredoc",
+			address: "resource.foo.bar.policy",
+			edit: func(value string) (string, error) {
+				return strings.Replace(value, "2012-10-17", "2023-01-01", 1) + "\n", nil
+			},
+			ok: true,
+			want: `resource "foo" "bar" {
+  attr1  = "val1"
+  policy = <<-EOT
+    {
+      "Version": "2023-01-01"
+    }
+  EOT
+}
+`,
+		},
+		{
+			name:    "edit error",
+			address: "resource.foo.bar.attr1",
+			edit: func(value string) (string, error) {
+				return "", fmt.Errorf("aborted")
+			},
+			ok:   false,
+			want: "",
+		},
+		{
+			name:    "invalid expression",
+			address: "resource.foo.bar.attr1",
+			edit: func(value string) (string, error) {
+				return `"val2`, nil
+			},
+			ok:   false,
+			want: "",
+		},
+		{
+			name:    "not found",
+			address: "resource.foo.bar.attr2",
+			edit: func(value string) (string, error) {
+				return value, nil
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := EditAttribute(inStream, outStream, "test", tc.address, tc.edit)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,441 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// valueStep is a step to an element in a value of attribute.
+type valueStep struct {
+	// key is a key of an object. It is used if index is negative.
+	key string
+	// index is an index of an element in a list.
+	index int
+}
+
+// elementMatch is a matched element in a value of attribute.
+type elementMatch struct {
+	attributeMatch
+	// src is a source of the expression of the attribute.
+	src []byte
+	// expr is a parsed expression of the element. Ranges in it are offsets in
+	// src.
+	expr hclsyntax.Expression
+	// start and end are a range [start, end) of the element in src.
+	// For an object, it is a range of the value of the key.
+	start int
+	end   int
+	// siblings is a list of ranges of all items in the collection
+	// containing the element, and i is an index of the element in it.
+	// For an object, each range includes both the key and the value.
+	siblings []hcl.Range
+	i        int
+	// collection is a range of the collection containing the element.
+	collection hcl.Range
+}
+
+// findAttributeElement returns an element in a value of attribute at a given
+// address, which reaches into a list or an object expression.
+// An index suffix such as a.b[1] refers to an element of a list, and can be
+// repeated such as a.b[1][0] for a nested list. Segments after the attribute
+// name such as a.tags.Environment refer to keys of an object. They can be
+// combined such as a.b[0].key.
+// The longest prefix of the address which matches an attribute is used, so an
+// attribute is not shadowed by a key of an object with the same name.
+// If the address doesn't refer to an element, or the element is not found, it
+// returns nil.
+// Since the element is located by parsing the expression with hclsyntax, the
+// rest of the expression is preserved as it is when editing the element.
+func findAttributeElement(body *hclwrite.Body, address string) (*elementMatch, error) {
+	attr, steps, err := resolveAttributeSteps(body, address)
+	if err != nil || attr == nil {
+		return nil, err
+	}
+
+	return locateElement(*attr, steps)
+}
+
+// resolveAttributeSteps returns an attribute and steps to an element in its
+// value at a given address. See findAttributeElement for details.
+// If the address doesn't refer to an element, or the attribute is not found,
+// it returns nil.
+func resolveAttributeSteps(body *hclwrite.Body, address string) (*attributeMatch, []valueStep, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k := len(a) - 1; k >= 0; k-- {
+		name, steps, err := parseValueSteps(caseSensitiveSegment(a[k]))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, s := range a[k+1:] {
+			key, indexes, err := parseValueSteps(caseSensitiveSegment(s))
+			if err != nil {
+				return nil, nil, err
+			}
+			steps = append(steps, valueStep{key: unquoteSegment(key), index: -1})
+			steps = append(steps, indexes...)
+		}
+		if len(steps) == 0 {
+			// not an element
+			continue
+		}
+
+		attrAddr := strings.Join(append(append([]string{}, a[:k]...), name), ".")
+		attr, b, err := findAttribute(body, attrAddr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if attr != nil {
+			return &attributeMatch{name: unquoteSegment(name), attr: attr, body: b}, steps, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// insertObjectKey inserts a key with a given value into an object in a value
+// of attribute at a given address such as a.tags.Owner, if the attribute
+// exists but the object lacks the key. If the address reaches into keys deeper
+// than an existing object, nested objects are created such as
+// { Owner = { Name = value } }.
+// It returns true if the key is inserted. Missing elements of a list are not
+// created.
+func insertObjectKey(body *hclwrite.Body, address string, value string) (bool, error) {
+	attr, steps, err := resolveAttributeSteps(body, address)
+	if err != nil || attr == nil {
+		return false, err
+	}
+
+	m, err := locateElement(*attr, nil)
+	if err != nil {
+		return false, err
+	}
+
+	// find the deepest existing element.
+	i := 0
+	for ; i < len(steps); i++ {
+		c := m.child(steps[i])
+		if c == nil {
+			break
+		}
+		m = c
+	}
+
+	missing := steps[i:]
+	if len(missing) == 0 {
+		// already exists
+		return false, nil
+	}
+	for _, s := range missing {
+		if s.index >= 0 {
+			return false, nil
+		}
+	}
+
+	if _, ok := m.expr.(*hclsyntax.ObjectConsExpr); !ok {
+		return false, fmt.Errorf("failed to set a key. the value is not an object: %s", m.value())
+	}
+
+	for j := len(missing) - 1; j > 0; j-- {
+		value = "{ " + objectKeyString(missing[j].key) + " = " + value + " }"
+	}
+
+	if err := m.appendItem(objectKeyString(missing[0].key) + " = " + value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// objectKeyString returns a key of an object in the HCL native syntax.
+// The key is quoted only if it is not a valid identifier.
+func objectKeyString(key string) string {
+	if hclsyntax.ValidIdentifier(key) {
+		return key
+	}
+	return string(hclwrite.TokensForValue(cty.StringVal(key)).Bytes())
+}
+
+// findAttributeValues returns values of attributes at a given address as
+// elements. If no attribute matched, the address is resolved as an element
+// in a value of attribute by findAttributeElement.
+// It is useful for editing a collection at a given address.
+func findAttributeValues(body *hclwrite.Body, address string) ([]*elementMatch, error) {
+	attrs, err := findTargetAttributes(body, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(attrs) == 0 {
+		m, err := findAttributeElement(body, address)
+		if err != nil || m == nil {
+			return nil, err
+		}
+		return []*elementMatch{m}, nil
+	}
+
+	matches := []*elementMatch{}
+	for _, attr := range attrs {
+		m, err := locateElement(attr, nil)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+
+	return matches, nil
+}
+
+// locateElement returns an element at given steps in a value of attribute.
+// If steps are empty, it returns the whole value.
+// If not found, it returns nil.
+func locateElement(attr attributeMatch, steps []valueStep) (*elementMatch, error) {
+	m := &elementMatch{
+		attributeMatch: attr,
+		src:            attr.attr.Expr().BuildTokens(nil).Bytes(),
+	}
+
+	expr, diags := hclsyntax.ParseExpression(m.src, "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse expression of attribute %s: %s", m.name, diags)
+	}
+
+	m.expr = expr
+	m.start = expr.Range().Start.Byte
+	m.end = expr.Range().End.Byte
+
+	for _, s := range steps {
+		m = m.child(s)
+		if m == nil {
+			// not found
+			return nil, nil
+		}
+	}
+
+	return m, nil
+}
+
+// child returns an element at a given step in the element.
+// If not found, it returns nil.
+func (m *elementMatch) child(s valueStep) *elementMatch {
+	c := &elementMatch{
+		attributeMatch: m.attributeMatch,
+		src:            m.src,
+		siblings:       []hcl.Range{},
+	}
+
+	switch e := m.expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		for i, item := range e.Exprs {
+			c.siblings = append(c.siblings, item.Range())
+			if s.index == i {
+				c.expr = item
+				c.i = i
+			}
+		}
+		c.collection = e.SrcRange
+	case *hclsyntax.ObjectConsExpr:
+		for i, item := range e.Items {
+			c.siblings = append(c.siblings, hcl.RangeBetween(item.KeyExpr.Range(), item.ValueExpr.Range()))
+			if s.index < 0 && c.expr == nil && objectKey(item.KeyExpr) == s.key {
+				c.expr = item.ValueExpr
+				c.i = i
+			}
+		}
+		c.collection = e.SrcRange
+	}
+
+	if c.expr == nil {
+		return nil
+	}
+
+	c.start = c.expr.Range().Start.Byte
+	c.end = c.expr.Range().End.Byte
+
+	return c
+}
+
+// objectKey returns a key of an object item as a string.
+// A key which is not a constant string such as (var.key) is never matched.
+func objectKey(expr hclsyntax.Expression) string {
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return ""
+	}
+	return v.AsString()
+}
+
+// parseValueSteps parses index suffixes of a given segment such as b[1][0],
+// and returns the name and steps.
+func parseValueSteps(segment string) (string, []valueStep, error) {
+	steps := []valueStep{}
+	for {
+		name, index, err := parseIndexedSegment(segment)
+		if err != nil {
+			return "", nil, err
+		}
+		if index < 0 {
+			return segment, steps, nil
+		}
+		steps = append([]valueStep{{index: index}}, steps...)
+		segment = name
+	}
+}
+
+// value returns a source of the element.
+func (m *elementMatch) value() string {
+	return strings.TrimSpace(string(m.src[m.start:m.end]))
+}
+
+// buildTokens returns tokens of an attribute whose value is the element.
+// It is used for writing the element in the same way as an attribute.
+func (m *elementMatch) buildTokens() (hclwrite.Tokens, error) {
+	f, err := safeParseConfig([]byte(m.name+" = "+m.value()+"\n"), "generated_by_elementMatch", hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Body().GetAttribute(m.name).BuildTokens(nil), nil
+}
+
+// set replaces the element with a given value.
+func (m *elementMatch) set(value string) error {
+	return m.splice(m.start, m.end, value)
+}
+
+// remove removes the element and a separator around it.
+// For an object, the key is removed as well as the value.
+// If the element occupies its own lines, the lines are removed including a
+// trailing comment, so that the rest of the collection is kept as it is.
+func (m *elementMatch) remove() error {
+	item := m.siblings[m.i]
+	if start, end, ok := m.ownLines(item.Start.Byte, item.End.Byte); ok {
+		return m.splice(start, end, "")
+	}
+
+	switch {
+	case len(m.siblings) == 1:
+		// remove the only element with a trailing comma if any.
+		return m.splice(item.Start.Byte, m.collection.End.Byte-1, "")
+	case m.i < len(m.siblings)-1:
+		// remove up to the next element.
+		return m.splice(item.Start.Byte, m.siblings[m.i+1].Start.Byte, "")
+	default:
+		// remove from the end of the previous element, so that a trailing
+		// comma of the list if any is kept.
+		return m.splice(m.siblings[m.i-1].End.Byte, item.End.Byte, "")
+	}
+}
+
+// ownLines returns a range of lines containing a given range [start, end) of
+// src if nothing else is on the lines except for a trailing comma and a
+// trailing comment.
+func (m *elementMatch) ownLines(start int, end int) (int, int, bool) {
+	lineStart := bytes.LastIndexByte(m.src[:start], '\n') + 1
+	if lineStart == 0 || len(bytes.TrimSpace(m.src[lineStart:start])) != 0 {
+		return 0, 0, false
+	}
+
+	lineEnd := bytes.IndexByte(m.src[end:], '\n')
+	if lineEnd < 0 {
+		return 0, 0, false
+	}
+	lineEnd += end
+
+	rest := bytes.TrimSpace(m.src[end:lineEnd])
+	rest = bytes.TrimSpace(bytes.TrimPrefix(rest, []byte(",")))
+	if len(rest) != 0 && !bytes.HasPrefix(rest, []byte("#")) && !bytes.HasPrefix(rest, []byte("//")) {
+		return 0, 0, false
+	}
+
+	return lineStart, lineEnd + 1, true
+}
+
+// appendItem appends a given item to the collection of the element, which
+// must be a list or an object. The item is an element of a list or a
+// `key = value` of an object.
+// The item is inserted after the last item in the same style as the
+// collection. If the collection spans multiple lines, the item is placed on a
+// new line and a trailing comma is added only if the collection already has
+// one. Otherwise, the item is appended on the same line.
+func (m *elementMatch) appendItem(item string) error {
+	var items []hcl.Range
+	var collection hcl.Range
+	// an object in multiple lines doesn't require commas between items.
+	requireComma := true
+	switch e := m.expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		for _, i := range e.Exprs {
+			items = append(items, i.Range())
+		}
+		collection = e.SrcRange
+	case *hclsyntax.ObjectConsExpr:
+		for _, i := range e.Items {
+			items = append(items, hcl.RangeBetween(i.KeyExpr.Range(), i.ValueExpr.Range()))
+		}
+		collection = e.SrcRange
+		requireComma = false
+	default:
+		return fmt.Errorf("failed to append an item. the value is not a collection: %s", m.value())
+	}
+
+	open := collection.Start.Byte
+	closing := collection.End.Byte - 1
+	multiline := bytes.ContainsRune(m.src[open:closing], '\n')
+
+	if len(items) == 0 {
+		if !multiline {
+			return m.splice(closing, closing, item)
+		}
+		if requireComma {
+			return m.splice(closing, closing, item+",\n")
+		}
+		return m.splice(closing, closing, item+"\n")
+	}
+
+	last := items[len(items)-1].End.Byte
+	if !multiline {
+		// a trailing comma, if any, is kept after the new item.
+		return m.splice(last, last, ", "+item)
+	}
+
+	// Insert a new line after the line of the last item, so that a trailing
+	// comment of the last item is kept.
+	lineEnd := bytes.IndexByte(m.src[last:closing], '\n')
+	if lineEnd < 0 {
+		// The closing bracket is on the same line as the last item.
+		return m.splice(last, last, ",\n"+item)
+	}
+	lineEnd += last + 1
+
+	switch {
+	case bytes.HasPrefix(bytes.TrimSpace(m.src[last:lineEnd]), []byte(",")):
+		return m.splice(lineEnd, lineEnd, item+",\n")
+	case requireComma:
+		return m.splice(last, lineEnd, ","+string(m.src[last:lineEnd])+item+"\n")
+	default:
+		return m.splice(lineEnd, lineEnd, item+"\n")
+	}
+}
+
+// splice replaces a range [start, end) of the expression with a given text,
+// and sets the result back to the attribute.
+func (m *elementMatch) splice(start int, end int, text string) error {
+	src := string(m.src[:start]) + text + string(m.src[end:])
+	expr, err := buildExpression(m.name, src)
+	if err != nil {
+		return err
+	}
+
+	m.body.SetAttributeRaw(m.name, expr.BuildTokens(nil))
+	return nil
+}
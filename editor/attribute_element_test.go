@@ -0,0 +1,505 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeElement(t *testing.T) {
+	src := `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "a", # comment a
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str = "abc"
+}
+`
+
+	cases := []struct {
+		name    string
+		op      string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "get",
+			op:      "get",
+			address: "resource.x.y.subnets[1]",
+			ok:      true,
+			want:    "\"b\"\n",
+		},
+		{
+			name:    "get nested",
+			op:      "get",
+			address: "resource.x.y.nested[1][0]",
+			ok:      true,
+			want:    "3\n",
+		},
+		{
+			name:    "get out of range",
+			op:      "get",
+			address: "resource.x.y.subnets[3]",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "get not a list",
+			op:      "get",
+			address: "resource.x.y.str[0]",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "set",
+			op:      "set",
+			address: "resource.x.y.subnets[1]",
+			value:   `"z"`,
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "z", "c"]
+  multi = [
+    "a", # comment a
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "set multi-line",
+			op:      "set",
+			address: "resource.x.y.multi[1]",
+			value:   `"z"`,
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "a", # comment a
+    "z",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "set invalid",
+			op:      "set",
+			address: "resource.x.y.subnets[1]",
+			value:   `"z`,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "rm first",
+			op:      "rm",
+			address: "resource.x.y.subnets[0]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["b", "c"]
+  multi = [
+    "a", # comment a
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "rm last",
+			op:      "rm",
+			address: "resource.x.y.subnets[2]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b"]
+  multi = [
+    "a", # comment a
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "rm multi-line",
+			op:      "rm",
+			address: "resource.x.y.multi[1]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "a", # comment a
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "rm multi-line last",
+			op:      "rm",
+			address: "resource.x.y.multi[2]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "a", # comment a
+    "b",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "rm with a trailing comment",
+			op:      "rm",
+			address: "resource.x.y.multi[0]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = ["a"]
+  str    = "abc"
+}
+`,
+		},
+		{
+			name:    "rm single",
+			op:      "rm",
+			address: "resource.x.y.single[0]",
+			ok:      true,
+			want: `resource "x" "y" {
+  subnets = ["a", "b", "c"]
+  multi = [
+    "a", # comment a
+    "b",
+    "c",
+  ]
+  nested = [[1, 2], [3, 4]]
+  single = []
+  str    = "abc"
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			var err error
+			switch tc.op {
+			case "get":
+				err = GetAttribute(inStream, outStream, "test", tc.address)
+			case "set":
+				err = SetAttribute(inStream, outStream, "test", tc.address, tc.value)
+			case "rm":
+				err = RemoveAttribute(inStream, outStream, "test", tc.address)
+			}
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeObjectKey(t *testing.T) {
+	src := `resource "x" "y" {
+  tags = {
+    Name        = "foo" # comment
+    Environment = "dev"
+    "Team"      = "bar"
+  }
+  inline = { a = 1, b = 2 }
+  list = [{ key = "v0" }, { key = "v1" }]
+}
+`
+
+	cases := []struct {
+		name    string
+		op      string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "get",
+			op:      "get",
+			address: "resource.x.y.tags.Environment",
+			ok:      true,
+			want:    "\"dev\"\n",
+		},
+		{
+			name:    "get quoted key",
+			op:      "get",
+			address: "resource.x.y.tags.Team",
+			ok:      true,
+			want:    "\"bar\"\n",
+		},
+		{
+			name:    "get in list",
+			op:      "get",
+			address: "resource.x.y.list[1].key",
+			ok:      true,
+			want:    "\"v1\"\n",
+		},
+		{
+			name:    "get not found",
+			op:      "get",
+			address: "resource.x.y.tags.Owner",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "set",
+			op:      "set",
+			address: "resource.x.y.tags.Environment",
+			value:   `"prod"`,
+			ok:      true,
+			want: `resource "x" "y" {
+  tags = {
+    Name        = "foo" # comment
+    Environment = "prod"
+    "Team"      = "bar"
+  }
+  inline = { a = 1, b = 2 }
+  list   = [{ key = "v0" }, { key = "v1" }]
+}
+`,
+		},
+		{
+			name:    "rm",
+			op:      "rm",
+			address: "resource.x.y.tags.Name",
+			ok:      true,
+			want: `resource "x" "y" {
+  tags = {
+    Environment = "dev"
+    "Team"      = "bar"
+  }
+  inline = { a = 1, b = 2 }
+  list   = [{ key = "v0" }, { key = "v1" }]
+}
+`,
+		},
+		{
+			name:    "rm inline",
+			op:      "rm",
+			address: "resource.x.y.inline.a",
+			ok:      true,
+			want: `resource "x" "y" {
+  tags = {
+    Name        = "foo" # comment
+    Environment = "dev"
+    "Team"      = "bar"
+  }
+  inline = { b = 2 }
+  list   = [{ key = "v0" }, { key = "v1" }]
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			var err error
+			switch tc.op {
+			case "get":
+				err = GetAttribute(inStream, outStream, "test", tc.address)
+			case "set":
+				err = SetAttribute(inStream, outStream, "test", tc.address, tc.value)
+			case "rm":
+				err = RemoveAttribute(inStream, outStream, "test", tc.address)
+			}
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeObjectKeyCreate(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "multi-line",
+			src: `
+resource "x" "y" {
+  tags = {
+    Name = "foo" # comment
+  }
+}
+`,
+			address: "resource.x.y.tags.Owner",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+resource "x" "y" {
+  tags = {
+    Name  = "foo" # comment
+    Owner = "platform"
+  }
+}
+`,
+		},
+		{
+			name: "single line",
+			src: `
+tags = { Name = "foo" }
+`,
+			address: "tags.Owner",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+tags = { Name = "foo", Owner = "platform" }
+`,
+		},
+		{
+			name: "empty",
+			src: `
+tags = {}
+`,
+			address: "tags.Owner",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+tags = { Owner = "platform" }
+`,
+		},
+		{
+			name: "nested objects",
+			src: `
+tags = {
+  Name = "foo"
+}
+`,
+			address: "tags.Owner.Team",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+tags = {
+  Name  = "foo"
+  Owner = { Team = "platform" }
+}
+`,
+		},
+		{
+			name: "quoted key",
+			src: `
+tags = {}
+`,
+			address: `tags."kubernetes.io/role"`,
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+tags = { "kubernetes.io/role" = "platform" }
+`,
+		},
+		{
+			name: "not an object",
+			src: `
+tags = "foo"
+`,
+			address: "tags.Owner",
+			value:   `"platform"`,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "missing list element",
+			src: `
+tags = [{}]
+`,
+			address: "tags[1].Owner",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+tags = [{}]
+`,
+		},
+		{
+			name: "missing attribute",
+			src: `
+a = 1
+`,
+			address: "tags.Owner",
+			value:   `"platform"`,
+			ok:      true,
+			want: `
+a = 1
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetAttribute(inStream, outStream, "test", tc.address, tc.value)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -1,34 +1,230 @@
 package editor
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 // GetAttribute reads HCL from io.Reader, and writes a value to matched
 // attribute to io.Writer.
-// Note that a filename is used only for an error message.
+// If the attribute is not found, nothing is written, which is
+// indistinguishable from an attribute set to empty. Use WithStrict to return
+// an error instead.
+// How the value is written can be customized with WithVars, WithEvaluate,
+// WithRaw and WithComments, which can be combined. These options are only
+// for getting values, and other operations return an error for them.
+// The default sink can be replaced with WithSink such as NewJSONSink, where
+// the matched attribute is named by the address.
+// If the input is written in the JSON syntax such as *.tf.json, it writes the
+// matched value as raw JSON. A sink given by WithSink is not supported in
+// this case, and it returns an error instead of ignoring the sink silently.
+// Note that a filename is used only for an error message and detecting the
+// JSON syntax by the .json extension.
 // If an error occurs, Nothing is written to the output stream.
-func GetAttribute(r io.Reader, w io.Writer, filename string, address string) error {
+func GetAttribute(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
 	e := &Editor{
-		source: &parser{filename: filename},
-		filters: []Filter{
-			&attributeGet{address: address},
-		},
-		sink: &attributeGet{address: address},
+		source:    &parser{filename: filename},
+		getValues: true,
+	}
+	// options for getting values customize the filter and the sink, so that
+	// they are applied before building the pipeline.
+	e.setOptions(opts)
+
+	get := &attributeGet{address: address, getOptions: e.get}
+	e.filters = append([]Filter{get}, e.filters...)
+	if e.sink == nil {
+		e.sink = get
+	}
+	e.jsonEdit = func(src []byte) ([]byte, error) {
+		if e.sink != get {
+			return nil, fmt.Errorf("a custom sink such as a template or JSON output is not supported for the JSON syntax")
+		}
+		return get.getJSON(src)
 	}
 
 	return e.Apply(r, w)
 }
 
+// GetAttributeResolved reads HCL from io.Reader, and writes a value to matched
+// attribute to io.Writer with simple variable references resolved by vars.
+//
+// Deprecated: Use GetAttribute with WithVars instead.
+func GetAttributeResolved(r io.Reader, w io.Writer, filename string, address string, vars map[string]string) error {
+	return GetAttribute(r, w, filename, address, WithVars(vars))
+}
+
+// WithStrict returns an Option to return a *NotFoundError when the attribute
+// to get is not found, instead of writing nothing. It also returns an
+// *AmbiguousError when the attribute is found in multiple matched blocks,
+// instead of writing the first one silently. Note that an address containing
+// a recursive descent (**) always returns the *AmbiguousError.
+func WithStrict() Option {
+	return func(e *Editor) {
+		e.setGetOption("WithStrict", func(o *getOptions) {
+			o.strict = true
+		})
+	}
+}
+
+// WithVars returns an Option to resolve a value of the attribute to get with
+// a given map of known variables.
+// If the value is a simple variable reference such as var.NAME and NAME is
+// found in vars, the value in vars is written instead of the reference.
+// Note that the value in vars is written as it is, so a string literal should
+// be quoted as well as attribute set.
+// Only a single-level variable reference is resolved, and otherwise it falls
+// back to the raw expression.
+func WithVars(vars map[string]string) Option {
+	return func(e *Editor) {
+		e.setGetOption("WithVars", func(o *getOptions) {
+			o.vars = vars
+		})
+	}
+}
+
+// WithEvaluate returns an Option to evaluate a value of the attribute to get
+// as a constant expression such as `5 * 60` and write the resulting value.
+// A value given by WithVars can be referred as var.NAME in the expression.
+// Note that the value in vars is evaluated as an expression, so a string
+// literal should be quoted as well as attribute set.
+// It returns an error if the expression cannot be evaluated, for example, it
+// refers to an unknown variable or calls a function.
+func WithEvaluate() Option {
+	return func(e *Editor) {
+		e.setGetOption("WithEvaluate", func(o *getOptions) {
+			o.evaluate = true
+		})
+	}
+}
+
+// WithRaw returns an Option to write a string literal value of the attribute
+// to get without surrounding quotes and with escape sequences decoded, so
+// that shell scripts can use it as it is. The value is decoded only if it is
+// a string literal without any interpolation, and otherwise it falls back to
+// the raw expression.
+// If the input is written in the JSON syntax, a JSON string is decoded in the
+// same way.
+func WithRaw() Option {
+	return func(e *Editor) {
+		e.setGetOption("WithRaw", func(o *getOptions) {
+			o.raw = true
+		})
+	}
+}
+
+// WithComments returns an Option to write leading comments of the attribute
+// to get before the value and a trailing comment after the value on the same
+// line, so that annotations such as # managed-by markers are preserved.
+func WithComments() Option {
+	return func(e *Editor) {
+		e.setGetOption("WithComments", func(o *getOptions) {
+			o.withComments = true
+		})
+	}
+}
+
+// setGetOption applies an option for getting values with a given function.
+// If the operation doesn't get values, it records an error with a given name
+// of the option instead, so that the option is not ignored silently.
+func (e *Editor) setGetOption(name string, set func(o *getOptions)) {
+	if !e.getValues {
+		if e.err == nil {
+			e.err = fmt.Errorf("%s is not supported by this operation", name)
+		}
+		return
+	}
+	set(&e.get)
+}
+
+// getOptions is a set of options to customize how values are got.
+type getOptions struct {
+	// strict is a flag to return an error when the attribute is not found.
+	strict bool
+	// vars is a map of known variables used for resolving references.
+	// If nil, the value is written as it is.
+	vars map[string]string
+	// evaluate is a flag to evaluate the value as a constant expression with
+	// vars.
+	evaluate bool
+	// raw is a flag to write a string literal value without quotes.
+	raw bool
+	// withComments is a flag to write comments of the attribute.
+	withComments bool
+}
+
+// formatValue returns a value of a given attribute as string with the
+// options applied. The attribute is either an ordinary one or one built by
+// filters for getting attributes as well as attributeValue.
+func (o *getOptions) formatValue(attr *hclwrite.Attribute) (string, error) {
+	out, err := attributeValue(attr)
+	if err != nil {
+		return "", err
+	}
+
+	if o.evaluate {
+		out, err = evaluateExpression(out, o.vars)
+		if err != nil {
+			return "", err
+		}
+	} else if o.vars != nil {
+		out = resolveVariable(out, o.vars)
+	}
+
+	if o.raw {
+		if v, ok := stringLiteralValue(out); ok {
+			if isHeredocValue([]byte(out)) {
+				// a body of heredoc always ends with a newline, which is written
+				// by the caller.
+				v = strings.TrimSuffix(v, "\n")
+			}
+			out = v
+		}
+	}
+
+	// an ordinary attribute doesn't contain its comments.
+	if o.withComments && isBuiltAttribute(attr) {
+		lead, line := getAttributeComments(attr)
+		if len(line) != 0 {
+			out = out + " " + line
+		}
+		out = strings.Join(append(lead, out), "\n")
+	}
+
+	return out, nil
+}
+
+// getJSON returns a value of the attribute in a given JSON document with the
+// options applied.
+func (f *attributeGet) getJSON(src []byte) ([]byte, error) {
+	if f.vars != nil || f.evaluate || f.withComments {
+		return nil, fmt.Errorf("resolving variables, evaluating and writing comments are not supported for the JSON syntax")
+	}
+
+	out, err := getJSONAttribute(src, f.address, f.strict)
+	if err != nil || !f.raw || len(out) == 0 {
+		return out, err
+	}
+
+	var s string
+	if err := json.Unmarshal(out, &s); err != nil {
+		// not a string
+		return out, nil
+	}
+	return []byte(s + "\n"), nil
+}
+
 // attributeGet is a filter and sink implementation for attribute.
 type attributeGet struct {
 	address string
+	getOptions
+	// withAddress is a flag to write the value as address=value.
+	withAddress bool
 }
 
 // Filter reads HCL and writes only matched an attribute at a given address.
@@ -40,9 +236,40 @@ func (f *attributeGet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
 
 	outFile := hclwrite.NewEmptyFile()
 	if attr != nil {
+		a, err := splitAddress(f.address)
+		if err != nil {
+			return nil, err
+		}
+		// A recursive descent searches blocks at any depth, so silently writing
+		// the first match would hide where it came from.
+		if f.strict || containsRecursive(a) {
+			if err := checkAmbiguousAttribute(inFile, f.address); err != nil {
+				return nil, err
+			}
+		}
 		outFile.Body().SetAttributeRaw(f.address, attr.BuildTokens(nil))
+		return outFile, nil
 	}
 
+	// fallback to an element in a value of attribute such as a.b[1].
+	elem, err := findAttributeElement(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if elem == nil {
+		if f.strict {
+			return nil, &NotFoundError{Address: f.address}
+		}
+		return outFile, nil
+	}
+
+	tokens, err := elem.buildTokens()
+	if err != nil {
+		return nil, err
+	}
+	outFile.Body().SetAttributeRaw(f.address, tokens)
+
 	return outFile, nil
 }
 
@@ -53,20 +280,20 @@ func (f *attributeGet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
 // The block is fetched by findLongestMatchingBlocks.
 // If the attribute is found, the body containing it is also returned for updating.
 func findAttribute(body *hclwrite.Body, address string) (*hclwrite.Attribute, *hclwrite.Body, error) {
-	if len(address) == 0 {
-		return nil, nil, errors.New("failed to parse address. address is empty")
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	a := strings.Split(address, ".")
 	if len(a) == 1 {
 		// if the address does not cantain any dots, find attribute in the body.
-		attr := body.GetAttribute(a[0])
+		attr := body.GetAttribute(unquoteSegment(a[0]))
 		return attr, body, nil
 	}
 
 	// if address contains dots, the last element is an attribute name,
 	// and the rest is the address of the block.
-	attrName := a[len(a)-1]
+	attrName := unquoteSegment(a[len(a)-1])
 	blockAddr := strings.Join(a[:len(a)-1], ".")
 	blocks, err := findLongestMatchingBlocks(body, blockAddr)
 	if err != nil {
@@ -91,6 +318,91 @@ func findAttribute(body *hclwrite.Body, address string) (*hclwrite.Attribute, *h
 	return nil, nil, nil
 }
 
+// checkAmbiguousAttribute returns an *AmbiguousError if the attribute at a
+// given address is found in multiple matched blocks, which findAttribute
+// resolves to the first one. The error lists full addresses and positions of
+// all matches, so that the user can fix the address.
+func checkAmbiguousAttribute(inFile *hclwrite.File, address string) error {
+	a, err := splitAddress(address)
+	if err != nil {
+		return err
+	}
+	if len(a) == 1 {
+		return nil
+	}
+
+	attrName := unquoteSegment(a[len(a)-1])
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), strings.Join(a[:len(a)-1], "."))
+	if err != nil {
+		return err
+	}
+
+	var found []*hclwrite.Block
+	for _, b := range blocks {
+		if b.Body().GetAttribute(attrName) != nil {
+			found = append(found, b)
+		}
+	}
+	if len(found) <= 1 {
+		return nil
+	}
+
+	ranges := &sourceRanges{}
+	ranges.record(inFile)
+	addrs := blockAddresses(inFile.Body(), "")
+	matches := []string{}
+	for _, b := range found {
+		match := addrs[b] + "." + quoteSegment(attrName)
+		if r, ok := ranges.rangeOf(b.Body().GetAttribute(attrName).BuildTokens(nil)); ok {
+			match += fmt.Sprintf(" (%d:%d)", r.Start.Line, r.Start.Column)
+		}
+		matches = append(matches, match)
+	}
+
+	return &AmbiguousError{Address: address, Matches: matches}
+}
+
+// attributeMatch is a matched attribute and the body containing it.
+type attributeMatch struct {
+	name string
+	attr *hclwrite.Attribute
+	body *hclwrite.Body
+}
+
+// findTargetAttributes returns attributes to be edited at a given address.
+// If the address contains a wildcard or a regular expression, it returns all
+// matching attributes in all matching blocks. Otherwise it returns the first matching one found by
+// findAttribute, so that the existing behavior for duplicated blocks is kept.
+func findTargetAttributes(body *hclwrite.Body, address string) ([]attributeMatch, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	attrName := unquoteSegment(a[len(a)-1])
+
+	if !containsPattern(a) {
+		attr, b, err := findAttribute(body, address)
+		if err != nil || attr == nil {
+			return nil, err
+		}
+		return []attributeMatch{{name: attrName, attr: attr, body: b}}, nil
+	}
+
+	blocks, err := findLongestMatchingBlocks(body, strings.Join(a[:len(a)-1], "."))
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []attributeMatch{}
+	for _, b := range blocks {
+		if attr := b.Body().GetAttribute(attrName); attr != nil {
+			matches = append(matches, attributeMatch{name: attrName, attr: attr, body: b.Body()})
+		}
+	}
+
+	return matches, nil
+}
+
 // findLongestMatchingBlocks returns the longest matching blocks at a  given address.
 // if the address does not cantain any dots, return all matching blocks by type.
 // If the address contains dots, the first element is a block type,
@@ -110,36 +422,95 @@ func findAttribute(body *hclwrite.Body, address string) (*hclwrite.Attribute, *h
 // type is specified, it is assumed that the number of labels in the same block
 // type does not really change and only the label name can be changed by the
 // user, and we want to give the user room to avoid unintended conflicts.
+// A wildcard (*) segment matches any label or nested block type at that
+// position, such as A.*.C. A segment wrapped in slashes such as /aws_.*/ is a
+// regular expression which must match the whole label or block type.
+// An index suffix such as A.B[1].C selects the N-th (0-based) block in source
+// order among blocks in the same body matched by the segment, which is useful
+// for repeated blocks with the same type and labels.
+// A recursive descent (**) segment matches zero or more labels or nested
+// blocks at any depth, such as **.C. See findBlocksByGlob for details.
 func findLongestMatchingBlocks(body *hclwrite.Body, address string) ([]*hclwrite.Block, error) {
-	if len(address) == 0 {
-		return nil, errors.New("failed to parse address. address is empty")
+	return findMatchingBlocks(body, address, false)
+}
+
+// findBlocksByPrefix is the same as findLongestMatchingBlocks, but also
+// returns blocks which have more labels than the address, as long as the rest
+// of the address is consumed by their leading labels. For example,
+// resource.aws_instance matches all resource blocks whose first label is
+// aws_instance.
+func findBlocksByPrefix(body *hclwrite.Body, address string) ([]*hclwrite.Block, error) {
+	return findMatchingBlocks(body, address, true)
+}
+
+// findMatchingBlocks is an implementation of findLongestMatchingBlocks and
+// findBlocksByPrefix. If prefix is true, labels may remain after the address.
+func findMatchingBlocks(body *hclwrite.Body, address string, prefix bool) ([]*hclwrite.Block, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, err
 	}
 
-	a := strings.Split(address, ".")
-	typeName := a[0]
-	blocks := allMatchingBlocksByType(body, typeName)
+	if containsRecursive(a) {
+		return findBlocksByGlob(body, a)
+	}
+
+	// strip index suffixes for matching names.
+	names := make([]string, len(a))
+	indexes := make([]int, len(a))
+	for i, s := range a {
+		names[i], indexes[i], err = parseIndexedSegment(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	typeName := names[0]
+	blocks, err := matchingBlocksBySegment(body, typeName)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(a) == 1 {
 		// if the address does not cantain any dots,
 		// return all matching blocks by type
-		return blocks, nil
+		return selectIndex(blocks, indexes[0]), nil
 	}
 
 	matched := []*hclwrite.Block{}
+	// counts is the number of blocks seen so far which consumed the same
+	// number of segments, used for resolving an index.
+	counts := make(map[int]int)
 	// if address contains dots, the next element maybe label or nested block.
 	for _, b := range blocks {
 		labels := b.Labels()
 		// consume labels from address
-		matchedlabels := longestMatchingLabels(labels, a[1:])
-		if len(matchedlabels) < len(labels) {
+		matchedlabels, err := longestMatchingLabels(labels, names[1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(matchedlabels) < len(labels) && !(prefix && len(matchedlabels) == len(names)-1) {
 			// The labels take precedence over nested blocks.
 			// If extra labels remain, skip it.
 			continue
 		}
+		consumed := 1 + len(matchedlabels)
+		for i := 0; i < consumed-1; i++ {
+			if indexes[i] >= 0 {
+				return nil, fmt.Errorf("failed to parse address. an index must be attached to the last segment of a block: %s", address)
+			}
+		}
+		if index := indexes[consumed-1]; index >= 0 {
+			n := counts[consumed]
+			counts[consumed]++
+			if n != index {
+				continue
+			}
+		}
 		if len(matchedlabels) < (len(a)-1) || len(labels) == 0 {
 			// if the block has no labels or partially matched ones, find the nested block
-			nestedAddr := strings.Join(a[1+len(matchedlabels):], ".")
-			nested, err := findLongestMatchingBlocks(b.Body(), nestedAddr)
+			nestedAddr := strings.Join(a[consumed:], ".")
+			nested, err := findMatchingBlocks(b.Body(), nestedAddr, prefix)
 			if err != nil {
 				return nil, err
 			}
@@ -153,6 +524,91 @@ func findLongestMatchingBlocks(body *hclwrite.Body, address string) ([]*hclwrite
 	return matched, nil
 }
 
+// findBlocksByGlob returns all blocks at any depth whose path matches given
+// segments of address which contain a recursive descent (**).
+// A path of a block is a concatenation of types and labels of the block and
+// its ancestors, and ** matches any number of segments of the path.
+// Blocks are returned in source order, where a parent precedes its children.
+// Note that an index suffix cannot be used together with **.
+func findBlocksByGlob(body *hclwrite.Body, segments []string) ([]*hclwrite.Block, error) {
+	for _, s := range segments {
+		if _, index, err := parseIndexedSegment(s); err != nil || index >= 0 {
+			return nil, fmt.Errorf("failed to parse address. an index cannot be used with %s: %s", recursiveSegment, strings.Join(segments, "."))
+		}
+	}
+
+	matched := []*hclwrite.Block{}
+	var walk func(body *hclwrite.Body, path []string) error
+	walk = func(body *hclwrite.Body, path []string) error {
+		for _, b := range body.Blocks() {
+			blockPath := append(append(append([]string{}, path...), b.Type()), b.Labels()...)
+			ok, err := matchGlob(segments, blockPath)
+			if err != nil {
+				return err
+			}
+			if ok {
+				matched = append(matched, b)
+			}
+			if err := walk(b.Body(), blockPath); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(body, []string{}); err != nil {
+		return nil, err
+	}
+
+	return matched, nil
+}
+
+// matchGlob returns true if a given path matches segments of address which
+// may contain a recursive descent (**).
+func matchGlob(segments []string, path []string) (bool, error) {
+	if len(segments) == 0 {
+		return len(path) == 0, nil
+	}
+
+	if segments[0] == recursiveSegment {
+		for i := 0; i <= len(path); i++ {
+			ok, err := matchGlob(segments[1:], path[i:])
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	}
+
+	if len(path) == 0 {
+		return false, nil
+	}
+
+	ok, err := matchSegment(segments[0], path[0])
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return matchGlob(segments[1:], path[1:])
+}
+
+// matchingBlocksBySegment returns all blocks from the body whose type matches
+// a given segment of address, which may be a wildcard or a regular expression.
+func matchingBlocksBySegment(b *hclwrite.Body, segment string) ([]*hclwrite.Block, error) {
+	matched := []*hclwrite.Block{}
+	for _, block := range b.Blocks() {
+		ok, err := matchSegment(segment, block.Type())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, block)
+		}
+	}
+
+	return matched, nil
+}
+
 // allMatchingBlocksByType returns all matching blocks from the body that have the
 // given name or returns an empty list if there is currently no matching block.
 // This method is useful when you want to ignore label differences.
@@ -169,18 +625,24 @@ func allMatchingBlocksByType(b *hclwrite.Body, typeName string) []*hclwrite.Bloc
 
 // longestMatchLabels returns a partial labels from the beginning to the
 // matching part and returns an empty array if nothing matches.
-func longestMatchingLabels(labels []string, prefix []string) []string {
+// A segment of prefix wrapped in slashes such as /prod_.*/ is treated as a
+// regular expression which must match the whole label.
+func longestMatchingLabels(labels []string, prefix []string) ([]string, error) {
 	matched := []string{}
 	for i := range prefix {
 		if len(labels) <= i {
-			return matched
+			return matched, nil
+		}
+		ok, err := matchSegment(prefix[i], labels[i])
+		if err != nil {
+			return nil, err
 		}
-		if prefix[i] != labels[i] {
-			return matched
+		if !ok {
+			return matched, nil
 		}
 		matched = append(matched, labels[i])
 	}
-	return matched
+	return matched, nil
 }
 
 // Sink reads HCL and writes value of attribute.
@@ -191,16 +653,77 @@ func (f *attributeGet) Sink(inFile *hclwrite.File) ([]byte, error) {
 		return []byte{}, nil
 	}
 
-	// treat expr as a string without interpreting its meaning.
-	out, err := getAttributeValueAsString(attr)
-
+	out, err := f.formatValue(attr)
 	if err != nil {
 		return []byte{}, err
 	}
 
+	if f.withAddress {
+		out = f.address + "=" + out
+	}
+
 	return []byte(out + "\n"), nil
 }
 
+// resolveVariable returns a value in vars if a given expression is a simple
+// variable reference such as var.NAME and NAME is found in vars.
+// Otherwise it returns the expression as it is.
+func resolveVariable(expr string, vars map[string]string) string {
+	parsed, diags := hclsyntax.ParseExpression([]byte(expr), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return expr
+	}
+
+	traversal, ok := parsed.(*hclsyntax.ScopeTraversalExpr)
+	if !ok || len(traversal.Traversal) != 2 || traversal.Traversal.RootName() != "var" {
+		return expr
+	}
+
+	attr, ok := traversal.Traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return expr
+	}
+
+	if v, ok := vars[attr.Name]; ok {
+		return v
+	}
+
+	return expr
+}
+
+// getExpressionAsString returns a string representation of a given expression.
+// Unlike getAttributeValueAsString, it is intended to be used for an
+// attribute in the original tree, whose expression tokens don't include the
+// attribute name and comments.
+func getExpressionAsString(expr *hclwrite.Expression) string {
+	// TokenIdent records SpaceBefore, but we should ignore it here.
+	return strings.TrimSpace(string(expr.BuildTokens(nil).Bytes()))
+}
+
+// getAttributeComments returns leading comments and a trailing comment of
+// Attribute built by the attributeGet filter. Each comment is returned as it
+// is without a trailing newline.
+func getAttributeComments(attr *hclwrite.Attribute) ([]string, string) {
+	lead := []string{}
+	line := ""
+	afterEqual := false
+	for _, t := range attr.Expr().BuildTokens(nil) {
+		switch t.Type {
+		case hclsyntax.TokenEqual:
+			afterEqual = true
+		case hclsyntax.TokenComment:
+			comment := strings.TrimRight(string(t.Bytes), "\r\n")
+			if afterEqual {
+				line = comment
+			} else {
+				lead = append(lead, comment)
+			}
+		}
+	}
+
+	return lead, line
+}
+
 // getAttributeValueAsString returns a value of Attribute as string.
 // There is no way to get value as string directly,
 // so we parses tokens of Attribute and build string representation.
@@ -217,17 +740,48 @@ func getAttributeValueAsString(attr *hclwrite.Attribute) (string, error) {
 		return "", fmt.Errorf("failed to find TokenEqual: %#v", attr)
 	}
 
-	// append tokens until find TokenComment
-	var valueTokens hclwrite.Tokens
-	for _, t := range exprTokens[(i + 1):] {
-		if t.Type == hclsyntax.TokenComment {
-			break
-		}
-		valueTokens = append(valueTokens, t)
+	// trim a trailing comment and newlines. Comments inside the expression
+	// such as ones between elements of a multi-line list are kept.
+	valueTokens := exprTokens[(i + 1):]
+	end := len(valueTokens)
+	for end > 0 && isTrivia(valueTokens[end-1]) {
+		end--
 	}
+	valueTokens = valueTokens[:end]
 
 	// TokenIdent records SpaceBefore, but we should ignore it here.
+	// A closing marker of heredoc contains a trailing newline, which is also
+	// trimmed here. Use parseValueExpression to parse the value again.
 	value := strings.TrimSpace(string(valueTokens.Bytes()))
 
 	return value, nil
 }
+
+// parseValueExpression parses a value returned by getAttributeValueAsString
+// as an expression. If the value ends with a closing marker of heredoc, a
+// newline is appended because the marker must be followed by a newline.
+func parseValueExpression(value string) (hclsyntax.Expression, hcl.Diagnostics) {
+	src := []byte(value)
+	if isHeredocValue(src) {
+		src = append(src, '\n')
+	}
+	return hclsyntax.ParseExpression(src, "", hcl.Pos{Line: 1, Column: 1})
+}
+
+// isHeredocValue returns true if a given value ends with a closing marker of
+// heredoc such as EOF. It is detected by lexing the value with a trailing
+// newline, because a closing marker without a newline is not recognized.
+func isHeredocValue(src []byte) bool {
+	tokens, _ := hclsyntax.LexExpression(append(append([]byte{}, src...), '\n'), "", hcl.Pos{Line: 1, Column: 1})
+	for i := len(tokens) - 1; i >= 0; i-- {
+		switch tokens[i].Type {
+		case hclsyntax.TokenEOF, hclsyntax.TokenNewline:
+			continue
+		case hclsyntax.TokenCHeredoc:
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// GetAttributeFromAll reads HCL from io.Reader, and writes values of matched
+// attributes in all matching blocks to io.Writer, one per line in source
+// order. While GetAttribute returns only the first one, it is useful when
+// the address matches multiple blocks, such as a bare block type.
+// If withAddress is true, each value is prefixed with the full address of the
+// block containing it, such as `resource.aws_instance.web = "ami-123"`, so
+// that the value can be correlated with the source block.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetAttributeFromAll(r io.Reader, w io.Writer, filename string, address string, withAddress bool, opts ...Option) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &attributeGetAll{address: address, withAddress: withAddress},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeGetAll is a Sink implementation to get attributes from all
+// matching blocks.
+type attributeGetAll struct {
+	address string
+	// withAddress is a flag to prefix each value with the block address.
+	withAddress bool
+}
+
+// Sink reads HCL and writes values of matched attributes.
+func (s *attributeGetAll) Sink(inFile *hclwrite.File) ([]byte, error) {
+	a, err := splitAddress(s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	attrName := unquoteSegment(a[len(a)-1])
+	if len(a) == 1 {
+		// a top level attribute has no block address.
+		if attr := inFile.Body().GetAttribute(attrName); attr != nil {
+			buf.WriteString(getExpressionAsString(attr.Expr()) + "\n")
+		}
+		return buf.Bytes(), nil
+	}
+
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), strings.Join(a[:len(a)-1], "."))
+	if err != nil {
+		return nil, err
+	}
+
+	addrs := blockAddresses(inFile.Body(), "")
+	for _, b := range blocks {
+		attr := b.Body().GetAttribute(attrName)
+		if attr == nil {
+			continue
+		}
+
+		value := getExpressionAsString(attr.Expr())
+		if s.withAddress {
+			value = addrs[b] + " = " + value
+		}
+		buf.WriteString(value + "\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// blockAddresses returns a map of all blocks in a given body and nested
+// blocks to their full addresses.
+func blockAddresses(body *hclwrite.Body, address string) map[*hclwrite.Block]string {
+	addrs := make(map[*hclwrite.Block]string)
+	for _, b := range body.Blocks() {
+		blockAddr := toAddress(b)
+		if len(address) != 0 {
+			blockAddr = address + "." + blockAddr
+		}
+		addrs[b] = blockAddr
+
+		for nested, nestedAddr := range blockAddresses(b.Body(), blockAddr) {
+			addrs[nested] = nestedAddr
+		}
+	}
+
+	return addrs
+}
@@ -0,0 +1,171 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeGetFromAll(t *testing.T) {
+	src := `
+a0 = v0
+
+resource "aws_instance" "web" {
+  ami = "ami-1"
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-2"
+}
+
+resource "aws_s3_bucket" "log" {
+  bucket = "log"
+}
+
+b1 {
+  b2 {
+    a2 = v2
+  }
+  b2 {
+    a2 = v3
+  }
+}
+
+c1 {
+  a2 = v4
+}
+
+c22 {
+  a2 = v5
+}
+`
+
+	cases := []struct {
+		name        string
+		address     string
+		withAddress bool
+		ok          bool
+		want        string
+	}{
+		{
+			name:        "wildcard with address",
+			address:     "resource.aws_instance.*.ami",
+			withAddress: true,
+			ok:          true,
+			want: `resource.aws_instance.web = "ami-1"
+resource.aws_instance.db = "ami-2"
+`,
+		},
+		{
+			name:        "regexp block type",
+			address:     "/[bc][0-9]/.*.a2",
+			withAddress: true,
+			ok:          true,
+			want: `b1.b2 = v2
+b1.b2 = v3
+`,
+		},
+		{
+			name:        "regexp block type is anchored",
+			address:     "/c[0-9]/.a2",
+			withAddress: true,
+			ok:          true,
+			want: `c1 = v4
+`,
+		},
+		{
+			name:    "invalid regexp block type",
+			address: "/c(/.a2",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:        "recursive descent with address",
+			address:     "**.a2",
+			withAddress: true,
+			ok:          true,
+			want: `b1.b2 = v2
+b1.b2 = v3
+c1 = v4
+c22 = v5
+`,
+		},
+		{
+			name:        "recursive descent in the middle",
+			address:     "resource.**.ami",
+			withAddress: true,
+			ok:          true,
+			want: `resource.aws_instance.web = "ami-1"
+resource.aws_instance.db = "ami-2"
+`,
+		},
+		{
+			name:    "recursive descent with index",
+			address: "**.b2[0].a2",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "block type only",
+			address: "resource.ami",
+			ok:      true,
+			want:    "\"ami-1\"\n\"ami-2\"\n",
+		},
+		{
+			name:        "block type only with address",
+			address:     "resource.ami",
+			withAddress: true,
+			ok:          true,
+			want: `resource.aws_instance.web = "ami-1"
+resource.aws_instance.db = "ami-2"
+`,
+		},
+		{
+			name:        "nested blocks with address",
+			address:     "b1.b2.a2",
+			withAddress: true,
+			ok:          true,
+			want: `b1.b2 = v2
+b1.b2 = v3
+`,
+		},
+		{
+			name:        "top level attribute",
+			address:     "a0",
+			withAddress: true,
+			ok:          true,
+			want:        "v0\n",
+		},
+		{
+			name:    "no match",
+			address: "resource.hoge",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "empty",
+			address: "",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttributeFromAll(inStream, outStream, "test", tc.address, tc.withAddress)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// GetAttributes reads HCL from io.Reader, and writes values of matched
+// attributes at given addresses to io.Writer in one parse.
+// Each value is written in a line as address=value in order of addresses, so
+// that scripts can tell which value belongs to which address. An attribute
+// which is not found is skipped unless strict is true, in which case it
+// returns a *NotFoundError for the first one not found.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetAttributes(r io.Reader, w io.Writer, filename string, addresses []string, strict bool) error {
+	sinks := MultiSink{}
+	for _, address := range addresses {
+		sinks = append(sinks, &attributeGet{address: address, withAddress: true})
+	}
+
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributesGet{addresses: addresses, strict: strict},
+		},
+		sink: sinks,
+	}
+
+	return e.Apply(r, w)
+}
+
+// GetAttributesJSON is the same as GetAttributes, but writes matched
+// attributes as a JSON array of objects in the same way as GetAttribute with
+// NewJSONSink.
+// Note that a filename is used only for an error message and source ranges.
+// If an error occurs, Nothing is written to the output stream.
+func GetAttributesJSON(r io.Reader, w io.Writer, filename string, addresses []string, strict bool) error {
+	ranges := &sourceRanges{filename: filename}
+	e := &Editor{
+		source: &rangeParser{parser: parser{filename: filename}, ranges: ranges},
+		filters: []Filter{
+			&attributesGet{addresses: addresses, strict: strict},
+		},
+		sink: &attributeJSON{addresses: addresses, ranges: ranges},
+	}
+
+	return e.Apply(r, w)
+}
+
+// attributesGet is a filter implementation to get attributes at multiple
+// addresses. It writes matched attributes named by their addresses in the
+// same way as attributeGet.
+type attributesGet struct {
+	addresses []string
+	strict    bool
+}
+
+// Filter reads HCL and writes only matched attributes at given addresses.
+func (f *attributesGet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	outFile := hclwrite.NewEmptyFile()
+	for _, address := range f.addresses {
+		matched, err := (&attributeGet{address: address, getOptions: getOptions{strict: f.strict}}).Filter(inFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if attr := matched.Body().GetAttribute(address); attr != nil {
+			outFile.Body().SetAttributeRaw(address, attr.Expr().BuildTokens(nil))
+		}
+	}
+
+	return outFile, nil
+}
@@ -0,0 +1,164 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGetAttributes(t *testing.T) {
+	src := `a0 = v0
+b1 "l1" {
+  a1 = "v1" # comment
+  a2 = [
+    "v2",
+  ]
+}
+`
+
+	cases := []struct {
+		name      string
+		addresses []string
+		strict    bool
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "multiple",
+			addresses: []string{"b1.l1.a1", "a0"},
+			ok:        true,
+			want: `b1.l1.a1="v1"
+a0=v0
+`,
+		},
+		{
+			name:      "multi-line",
+			addresses: []string{"b1.l1.a2"},
+			ok:        true,
+			want: `b1.l1.a2=[
+    "v2",
+  ]
+`,
+		},
+		{
+			name:      "not found",
+			addresses: []string{"a0", "a3", "b1.l1.a1"},
+			ok:        true,
+			want: `a0=v0
+b1.l1.a1="v1"
+`,
+		},
+		{
+			name:      "not found in strict mode",
+			addresses: []string{"a0", "a3"},
+			strict:    true,
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttributes(inStream, outStream, "test", tc.addresses, tc.strict)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAttributesJSON(t *testing.T) {
+	src := `a0 = v0
+b1 {
+  a1 = "v1"
+}
+`
+
+	cases := []struct {
+		name      string
+		addresses []string
+		strict    bool
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "multiple",
+			addresses: []string{"b1.a1", "a0", "a2"},
+			ok:        true,
+			want: `[
+  {
+    "address": "b1.a1",
+    "value": "\"v1\"",
+    "range": {
+      "filename": "test",
+      "start": {
+        "line": 3,
+        "column": 3,
+        "byte": 15
+      },
+      "end": {
+        "line": 3,
+        "column": 12,
+        "byte": 24
+      }
+    }
+  },
+  {
+    "address": "a0",
+    "value": "v0",
+    "range": {
+      "filename": "test",
+      "start": {
+        "line": 1,
+        "column": 1,
+        "byte": 0
+      },
+      "end": {
+        "line": 1,
+        "column": 8,
+        "byte": 7
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name:      "not found in strict mode",
+			addresses: []string{"a0", "a2"},
+			strict:    true,
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttributesJSON(inStream, outStream, "test", tc.addresses, tc.strict)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package editor
 
 import (
 	"bytes"
+	"errors"
 	"testing"
+	"text/template"
 )
 
 func TestAttributeGet(t *testing.T) {
@@ -10,6 +12,7 @@ func TestAttributeGet(t *testing.T) {
 		name    string
 		src     string
 		address string
+		strict  bool
 		ok      bool
 		want    string
 	}{
@@ -42,6 +45,71 @@ a1 = v1
 			ok:      true,
 			want:    "",
 		},
+		{
+			name: "not found in strict mode",
+			src: `
+a0 = v0
+a1 = v1
+`,
+			address: "hoge",
+			strict:  true,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "empty string in strict mode",
+			src: `
+a0 = ""
+`,
+			address: "a0",
+			strict:  true,
+			ok:      true,
+			want:    "\"\"\n",
+		},
+		{
+			name: "ambiguous",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  a1 = v2
+}
+`,
+			address: "b1.*.a1",
+			ok:      true,
+			want:    "v1\n",
+		},
+		{
+			name: "ambiguous in strict mode",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  a1 = v2
+}
+`,
+			address: "b1.*.a1",
+			strict:  true,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "unique match by wildcard in strict mode",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  a2 = v2
+}
+`,
+			address: "b1.*.a1",
+			strict:  true,
+			ok:      true,
+			want:    "v1\n",
+		},
 		{
 			name: "attribute with comments",
 			src: `
@@ -181,6 +249,170 @@ b1 {
 			ok:      true,
 			want:    "v2\n",
 		},
+		{
+			name: "leading and trailing dots are trimmed",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: ".b1.a1.",
+			ok:      true,
+			want:    "v1\n",
+		},
+		{
+			name: "empty segment should be error",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1..a1",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "regexp label",
+			src: `
+resource "aws_instance" "dev_web" {
+  ami = "ami-1"
+}
+resource "aws_instance" "prod.web" {
+  ami = "ami-2"
+}
+`,
+			address: "resource.aws_instance./prod\\..*/.ami",
+			ok:      true,
+			want:    "\"ami-2\"\n",
+		},
+		{
+			name: "invalid regexp label should be error",
+			src: `
+resource "aws_instance" "prod_web" {
+  ami = "ami-1"
+}
+`,
+			address: "resource.aws_instance./prod_(/.ami",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "wildcard label",
+			src: `
+resource "aws_instance" "web" {
+  ami = "ami-1"
+}
+resource "aws_instance" "db" {
+  ami = "ami-2"
+}
+`,
+			address: "resource.aws_instance.*.ami",
+			ok:      true,
+			want:    "\"ami-1\"\n",
+		},
+		{
+			name: "wildcard nested block type",
+			src: `
+b1 {
+  b2 {
+    a2 = v2
+  }
+}
+`,
+			address: "b1.*.a2",
+			ok:      true,
+			want:    "v2\n",
+		},
+		{
+			name: "index of repeated nested blocks",
+			src: `
+resource "aws_security_group" "x" {
+  ingress {
+    from_port = 80
+  }
+  ingress {
+    from_port = 443
+  }
+}
+`,
+			address: "resource.aws_security_group.x.ingress[1].from_port",
+			ok:      true,
+			want:    "443\n",
+		},
+		{
+			name: "index of duplicated blocks with labels",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+b1 "l1" {
+  a1 = v2
+}
+`,
+			address: "b1.l1[1].a1",
+			ok:      true,
+			want:    "v2\n",
+		},
+		{
+			name: "index out of range",
+			src: `
+b1 {
+  b2 {
+    a2 = v2
+  }
+}
+`,
+			address: "b1.b2[1].a2",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name: "index in the middle of block segments should be error",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+`,
+			address: "b1[0].l1.a1",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "invalid index should be error",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1[x].a1",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "quoted label containing dots",
+			src: `
+module "my.module" {
+  source = "./my"
+}
+module "my" {
+  source = "./other"
+}
+`,
+			address: `module."my.module".source`,
+			ok:      true,
+			want:    "\"./my\"\n",
+		},
+		{
+			name: "escaped dot in label",
+			src: `
+module "my.module" {
+  source = "./my"
+}
+`,
+			address: `module.my\.module.source`,
+			ok:      true,
+			want:    "\"./my\"\n",
+		},
 		{
 			name: "attribute in duplicated blocks",
 			src: `
@@ -195,13 +427,735 @@ b1 "l1" "l2" {
 			ok:      true,
 			want:    "v1\n",
 		},
+		{
+			name: "heredoc",
+			src: `
+a0 = <<EOF
+foo # not a comment
+  bar
+EOF
+a1 = v1
+`,
+			address: "a0",
+			ok:      true,
+			want: `<<EOF
+foo # not a comment
+  bar
+EOF
+`,
+		},
+		{
+			name: "indented heredoc in block",
+			src: `
+b1 {
+  a1 = <<-EOT
+    foo
+      bar
+    EOT
+  a2 = v2
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want: `<<-EOT
+    foo
+      bar
+    EOT
+`,
+		},
+		{
+			name: "multi-line list with comments",
+			src: `
+a0 = [
+  "foo", # comment
+  "bar",
+] # trailing
+`,
+			address: "a0",
+			ok:      true,
+			want: `[
+  "foo", # comment
+  "bar",
+]
+`,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			inStream := bytes.NewBufferString(tc.src)
 			outStream := new(bytes.Buffer)
-			err := GetAttribute(inStream, outStream, "test", tc.address)
+			opts := []Option{}
+			if tc.strict {
+				opts = append(opts, WithStrict())
+			}
+			err := GetAttribute(inStream, outStream, "test", tc.address, opts...)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetAmbiguousError(t *testing.T) {
+	src := `b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  b2 {
+    a1 = v2
+  }
+  a1 = v3
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		opts    []Option
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "strict",
+			address: "b1.**.a1",
+			opts:    []Option{WithStrict()},
+			wantErr: "ambiguous: b1.**.a1 matches 3 items: b1.l1.a1 (2:3), b1.l2.a1 (8:3), b1.l2.b2.a1 (6:5)",
+		},
+		{
+			name:    "recursive descent without strict",
+			address: "**.a1",
+			wantErr: "ambiguous: **.a1 matches 3 items: b1.l1.a1 (2:3), b1.l2.a1 (8:3), b1.l2.b2.a1 (6:5)",
+		},
+		{
+			name:    "recursive descent with a unique match",
+			address: "**.b2.a1",
+			want:    "v2\n",
+		},
+		{
+			name:    "wildcard without strict writes the first match",
+			address: "b1.*.a1",
+			want:    "v1\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, "test", tc.address, tc.opts...)
+
+			if len(tc.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected err = %s", err)
+				}
+				if got := outStream.String(); got != tc.want {
+					t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+				}
+				return
+			}
+
+			var ambiguousErr *AmbiguousError
+			if !errors.As(err, &ambiguousErr) {
+				t.Fatalf("expected to return an AmbiguousError, but got: %v", err)
+			}
+
+			if got := err.Error(); got != tc.wantErr {
+				t.Fatalf("got: %s, want: %s", got, tc.wantErr)
+			}
+
+			if got := outStream.String(); got != "" {
+				t.Fatalf("expected to write nothing, but got: %s", got)
+			}
+		})
+	}
+}
+
+func TestAttributeGetResolved(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		vars    map[string]string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "variable reference found",
+			src: `
+b1 {
+  ami = var.ami
+}
+`,
+			address: "b1.ami",
+			vars:    map[string]string{"ami": `"ami-123"`},
+			ok:      true,
+			want:    "\"ami-123\"\n",
+		},
+		{
+			name: "variable reference not found",
+			src: `
+b1 {
+  ami = var.ami
+}
+`,
+			address: "b1.ami",
+			vars:    map[string]string{"foo": `"bar"`},
+			ok:      true,
+			want:    "var.ami\n",
+		},
+		{
+			name: "not a variable reference",
+			src: `
+b1 {
+  ami = local.ami
+}
+`,
+			address: "b1.ami",
+			vars:    map[string]string{"ami": `"ami-123"`},
+			ok:      true,
+			want:    "local.ami\n",
+		},
+		{
+			name: "nested traversal is not resolved",
+			src: `
+b1 {
+  ami = var.amis.default
+}
+`,
+			address: "b1.ami",
+			vars:    map[string]string{"amis": `"ami-123"`},
+			ok:      true,
+			want:    "var.amis.default\n",
+		},
+		{
+			name: "literal",
+			src: `
+a0 = "v0"
+`,
+			address: "a0",
+			vars:    map[string]string{"a0": `"v1"`},
+			ok:      true,
+			want:    "\"v0\"\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, "test", tc.address, WithVars(tc.vars))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+
+			// The deprecated function should behave the same for compatibility.
+			outStream.Reset()
+			err = GetAttributeResolved(bytes.NewBufferString(tc.src), outStream, "test", tc.address, tc.vars)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err in GetAttributeResolved = %s", err)
+			}
+
+			if got := outStream.String(); got != tc.want {
+				t.Fatalf("got in GetAttributeResolved:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetEvaluated(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		vars    map[string]string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "arithmetic",
+			src: `
+a0 = 5 * 60
+`,
+			address: "a0",
+			vars:    nil,
+			ok:      true,
+			want:    "300\n",
+		},
+		{
+			name: "string template",
+			src: `
+a0 = "foo-${"bar"}"
+`,
+			address: "a0",
+			vars:    nil,
+			ok:      true,
+			want:    "\"foo-bar\"\n",
+		},
+		{
+			name: "heredoc",
+			src: `
+a0 = <<-EOF
+  foo-${var.env}
+  EOF
+`,
+			address: "a0",
+			vars:    map[string]string{"env": `"dev"`},
+			ok:      true,
+			want:    "\"foo-dev\\n\"\n",
+		},
+		{
+			name: "collection",
+			src: `
+a0 = [1 + 1, true && false]
+`,
+			address: "a0",
+			vars:    nil,
+			ok:      true,
+			want:    "[2, false]\n",
+		},
+		{
+			name: "variables",
+			src: `
+a0 = "${var.env}-${var.count * 2}"
+`,
+			address: "a0",
+			vars:    map[string]string{"env": `"dev"`, "count": "3"},
+			ok:      true,
+			want:    "\"dev-6\"\n",
+		},
+		{
+			name: "unknown variable",
+			src: `
+a0 = var.env
+`,
+			address: "a0",
+			vars:    nil,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "function",
+			src: `
+a0 = upper("foo")
+`,
+			address: "a0",
+			vars:    nil,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "not found",
+			src: `
+a0 = 1
+`,
+			address: "hoge",
+			vars:    nil,
+			ok:      true,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, "test", tc.address, WithVars(tc.vars), WithEvaluate())
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetWithComments(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "leading and trailing comments",
+			src: `
+b1 {
+  # managed-by: hcledit
+  # do not edit
+  a1 = v1 # trailing
+  a2 = v2
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want: `# managed-by: hcledit
+# do not edit
+v1 # trailing
+`,
+		},
+		{
+			name: "trailing comment only",
+			src: `
+a0 = v0 // trailing
+`,
+			address: "a0",
+			ok:      true,
+			want:    "v0 // trailing\n",
+		},
+		{
+			name: "no comments",
+			src: `
+a0 = v0
+`,
+			address: "a0",
+			ok:      true,
+			want:    "v0\n",
+		},
+		{
+			name: "not found",
+			src: `
+# comment
+a0 = v0
+`,
+			address: "hoge",
+			ok:      true,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, "test", tc.address, WithComments())
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetRaw(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		filename string
+		address  string
+		ok       bool
+		want     string
+	}{
+		{
+			name: "string literal",
+			src: `
+a0 = "v0"
+`,
+			filename: "test",
+			address:  "a0",
+			ok:       true,
+			want:     "v0\n",
+		},
+		{
+			name: "escape sequences",
+			src: `
+a0 = "foo\tbar \"baz\""
+`,
+			filename: "test",
+			address:  "a0",
+			ok:       true,
+			want:     "foo\tbar \"baz\"\n",
+		},
+		{
+			name: "heredoc",
+			src: `
+a0 = <<EOF
+foo
+  "bar"
+EOF
+`,
+			filename: "test",
+			address:  "a0",
+			ok:       true,
+			want:     "foo\n  \"bar\"\n",
+		},
+		{
+			name: "indented heredoc",
+			src: `
+b1 {
+  a1 = <<-EOT
+    foo
+      bar
+    EOT
+}
+`,
+			filename: "test",
+			address:  "b1.a1",
+			ok:       true,
+			want:     "foo\n  bar\n",
+		},
+		{
+			name: "interpolation",
+			src: `
+a0 = "${var.foo}-bar"
+`,
+			filename: "test",
+			address:  "a0",
+			ok:       true,
+			want:     "\"${var.foo}-bar\"\n",
+		},
+		{
+			name: "not a string",
+			src: `
+a0 = ["v0"]
+`,
+			filename: "test",
+			address:  "a0",
+			ok:       true,
+			want:     "[\"v0\"]\n",
+		},
+		{
+			name: "json syntax",
+			src: `{"a0": "foo\"bar", "a1": 1}
+`,
+			filename: "test.tf.json",
+			address:  "a0",
+			ok:       true,
+			want:     "foo\"bar\n",
+		},
+		{
+			name: "not found",
+			src: `
+a0 = "v0"
+`,
+			filename: "test",
+			address:  "hoge",
+			ok:       true,
+			want:     "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, tc.filename, tc.address, WithRaw())
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetWithOptions(t *testing.T) {
+	src := `
+b1 {
+  # managed-by: hcledit
+  a1 = var.foo # comment
+  a2 = "v2"
+}
+`
+
+	cases := []struct {
+		name     string
+		src      string
+		filename string
+		address  string
+		opts     []Option
+		ok       bool
+		want     string
+	}{
+		{
+			name:     "raw with vars",
+			src:      src,
+			filename: "test",
+			address:  "b1.a1",
+			opts:     []Option{WithRaw(), WithVars(map[string]string{"foo": `"bar"`})},
+			ok:       true,
+			want:     "bar\n",
+		},
+		{
+			name:     "raw with vars and comments",
+			src:      src,
+			filename: "test",
+			address:  "b1.a1",
+			opts:     []Option{WithRaw(), WithVars(map[string]string{"foo": `"bar"`}), WithComments()},
+			ok:       true,
+			want:     "# managed-by: hcledit\nbar # comment\n",
+		},
+		{
+			name:     "evaluate with raw",
+			src:      src,
+			filename: "test",
+			address:  "b1.a1",
+			opts:     []Option{WithEvaluate(), WithRaw(), WithVars(map[string]string{"foo": `"${"b"}ar"`})},
+			ok:       true,
+			want:     "bar\n",
+		},
+		{
+			name:     "raw with template sink",
+			src:      src,
+			filename: "test",
+			address:  "b1.a2",
+			opts:     []Option{WithRaw(), WithSink(NewTemplateSink(template.Must(template.New("test").Parse("{{.Address}}={{.Value}}\n"))))},
+			ok:       true,
+			want:     "b1.a2=v2\n",
+		},
+		{
+			name: "template sink in json syntax",
+			src: `{"a0": "v0"}
+`,
+			filename: "test.tf.json",
+			address:  "a0",
+			opts:     []Option{WithSink(NewTemplateSink(template.Must(template.New("test").Parse("{{.Value}}"))))},
+			ok:       false,
+			want:     "",
+		},
+		{
+			name: "comments in json syntax",
+			src: `{"a0": "v0"}
+`,
+			filename: "test.tf.json",
+			address:  "a0",
+			opts:     []Option{WithComments()},
+			ok:       false,
+			want:     "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, tc.filename, tc.address, tc.opts...)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeGetJSON(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		strict  bool
+		ok      bool
+		want    string
+	}{
+		{
+			name: "found",
+			src: `
+b1 {
+  a1 = "v1"
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want: `[
+  {
+    "address": "b1.a1",
+    "value": "\"v1\"",
+    "range": {
+      "filename": "test",
+      "start": {
+        "line": 3,
+        "column": 3,
+        "byte": 8
+      },
+      "end": {
+        "line": 3,
+        "column": 12,
+        "byte": 17
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "not found",
+			src: `
+a0 = v0
+`,
+			address: "hoge",
+			ok:      true,
+			want:    "[]\n",
+		},
+		{
+			name: "not found in strict mode",
+			src: `
+a0 = v0
+`,
+			address: "hoge",
+			strict:  true,
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			opts := []Option{WithSink(NewJSONSink())}
+			if tc.strict {
+				opts = append(opts, WithStrict())
+			}
+			err := GetAttribute(inStream, outStream, "test", tc.address, opts...)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s", err)
 			}
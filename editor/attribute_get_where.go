@@ -0,0 +1,108 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// GetAttributeWhere reads HCL from io.Reader, finds blocks of a given type
+// whose keyAttr value equals to keyValue, and writes a value of getAttr in
+// the matched blocks to io.Writer.
+// The blockType is resolved in the same way as the address of block get, so
+// it can contain labels as well as a bare block type.
+// The keyValue is compared with the raw expression, or with the unquoted
+// value if the expression is a string literal, so that both "web" and web
+// match `name = "web"`.
+// If multiple blocks match, all values are written in source order, one per
+// line. Blocks without getAttr are skipped.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetAttributeWhere(r io.Reader, w io.Writer, filename string, blockType string, keyAttr string, keyValue string, getAttr string, opts ...Option) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink: &attributeWhere{
+			blockType: blockType,
+			keyAttr:   keyAttr,
+			keyValue:  keyValue,
+			getAttr:   getAttr,
+		},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeWhere is a Sink implementation to get attributes of blocks
+// matched by a key attribute.
+type attributeWhere struct {
+	blockType string
+	keyAttr   string
+	keyValue  string
+	getAttr   string
+}
+
+// Sink reads HCL and writes values of attributes in matched blocks.
+func (s *attributeWhere) Sink(inFile *hclwrite.File) ([]byte, error) {
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), s.blockType)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, b := range blocks {
+		key := b.Body().GetAttribute(s.keyAttr)
+		if key == nil || !matchExpression(key.Expr(), s.keyValue) {
+			continue
+		}
+
+		attr := b.Body().GetAttribute(s.getAttr)
+		if attr == nil {
+			continue
+		}
+
+		buf.WriteString(getExpressionAsString(attr.Expr()) + "\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// matchExpression returns true if a given expression equals to value.
+// If the expression is a string literal, its unquoted value is also compared.
+func matchExpression(expr *hclwrite.Expression, value string) bool {
+	raw := getExpressionAsString(expr)
+	if raw == value {
+		return true
+	}
+
+	s, ok := stringLiteralValue(raw)
+	return ok && s == value
+}
+
+// stringLiteralValue returns an unquoted value of a given expression if it is
+// a string literal without any interpolation.
+func stringLiteralValue(expr string) (string, bool) {
+	parsed, diags := parseValueExpression(expr)
+	if diags.HasErrors() {
+		return "", false
+	}
+
+	if _, ok := parsed.(*hclsyntax.TemplateExpr); !ok {
+		return "", false
+	}
+
+	if len(parsed.Variables()) != 0 {
+		return "", false
+	}
+
+	v, diags := parsed.Value(nil)
+	if diags.HasErrors() || v.IsNull() || !v.IsKnown() || v.Type() != cty.String {
+		return "", false
+	}
+
+	return v.AsString(), true
+}
@@ -0,0 +1,146 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeGetWhere(t *testing.T) {
+	src := `
+resource "aws_instance" "web" {
+  name = "web"
+  ami  = "ami-1"
+}
+
+resource "aws_instance" "db" {
+  name = "db"
+  ami  = "ami-2"
+}
+
+resource "aws_instance" "web2" {
+  name = "web"
+  ami  = "ami-3"
+}
+
+resource "aws_instance" "interpolated" {
+  name = "${var.name}"
+  ami  = "ami-4"
+}
+
+resource "aws_instance" "ref" {
+  name = var.name
+}
+`
+
+	cases := []struct {
+		name      string
+		blockType string
+		keyAttr   string
+		keyValue  string
+		getAttr   string
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "unquoted key value",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "db",
+			getAttr:   "ami",
+			ok:        true,
+			want:      "\"ami-2\"\n",
+		},
+		{
+			name:      "quoted key value",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  `"db"`,
+			getAttr:   "ami",
+			ok:        true,
+			want:      "\"ami-2\"\n",
+		},
+		{
+			name:      "multiple matches in source order",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "web",
+			getAttr:   "ami",
+			ok:        true,
+			want:      "\"ami-1\"\n\"ami-3\"\n",
+		},
+		{
+			name:      "raw expression",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "var.name",
+			getAttr:   "name",
+			ok:        true,
+			want:      "var.name\n",
+		},
+		{
+			name:      "interpolation is not unquoted",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "${var.name}",
+			getAttr:   "ami",
+			ok:        true,
+			want:      "",
+		},
+		{
+			name:      "block type with labels",
+			blockType: "resource.aws_instance.web2",
+			keyAttr:   "name",
+			keyValue:  "web",
+			getAttr:   "ami",
+			ok:        true,
+			want:      "\"ami-3\"\n",
+		},
+		{
+			name:      "missing attribute to get",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "db",
+			getAttr:   "hoge",
+			ok:        true,
+			want:      "",
+		},
+		{
+			name:      "no match",
+			blockType: "resource",
+			keyAttr:   "name",
+			keyValue:  "hoge",
+			getAttr:   "ami",
+			ok:        true,
+			want:      "",
+		},
+		{
+			name:      "empty block type",
+			blockType: "",
+			keyAttr:   "name",
+			keyValue:  "web",
+			getAttr:   "ami",
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttributeWhere(inStream, outStream, "test", tc.blockType, tc.keyAttr, tc.keyValue, tc.getAttr)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
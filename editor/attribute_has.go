@@ -0,0 +1,95 @@
+package editor
+
+import (
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// AttributeState is a state of an attribute at an address.
+type AttributeState int
+
+const (
+	// AttributeAbsent means that the attribute doesn't exist.
+	AttributeAbsent AttributeState = iota
+	// AttributeNull means that the attribute exists and its value is a literal
+	// null. Terraform treats it as if the attribute were omitted in most
+	// cases, but it still overrides a value in some contexts such as a module
+	// argument with a default, so it is distinguished from absent.
+	AttributeNull
+	// AttributePresent means that the attribute exists with a non-null value.
+	AttributePresent
+)
+
+// String returns a name of the state.
+func (s AttributeState) String() string {
+	switch s {
+	case AttributeAbsent:
+		return "absent"
+	case AttributeNull:
+		return "null"
+	default:
+		return "present"
+	}
+}
+
+// HasAttribute reads HCL from io.Reader, and returns true if an attribute at
+// a given address exists, false otherwise.
+// Unlike GetAttribute, it distinguishes an absent attribute from a present
+// attribute with an empty value, and writes nothing.
+// An attribute set to null exists. Use GetAttributeState to distinguish it.
+// The address can refer to an element in a value of attribute such as a.b[1]
+// or a.tags.Name in the same way as GetAttribute.
+// Note that a filename is used only for an error message.
+func HasAttribute(r io.Reader, filename string, address string) (bool, error) {
+	state, err := GetAttributeState(r, filename, address)
+	if err != nil {
+		return false, err
+	}
+
+	return state != AttributeAbsent, nil
+}
+
+// GetAttributeState reads HCL from io.Reader, and returns a state of an
+// attribute at a given address, that is, absent, null or present.
+// Only a literal null is detected as null, and an expression which may be
+// evaluated to null such as a reference is present.
+// The address can refer to an element in a value of attribute such as a.b[1]
+// or a.tags.Name in the same way as GetAttribute.
+// Note that a filename is used only for an error message.
+func GetAttributeState(r io.Reader, filename string, address string) (AttributeState, error) {
+	inFile, err := parseInput(r, filename)
+	if err != nil {
+		return AttributeAbsent, err
+	}
+
+	attr, _, err := findAttribute(inFile.Body(), address)
+	if err != nil {
+		return AttributeAbsent, err
+	}
+
+	if attr == nil {
+		// fallback to an element in a value of attribute such as a.b[1].
+		elem, err := findAttributeElement(inFile.Body(), address)
+		if err != nil || elem == nil {
+			return AttributeAbsent, err
+		}
+		if elem.value() == "null" {
+			return AttributeNull, nil
+		}
+		return AttributePresent, nil
+	}
+
+	if isNullExpression(attr.Expr()) {
+		return AttributeNull, nil
+	}
+
+	return AttributePresent, nil
+}
+
+// isNullExpression returns true if a given expression is a literal null.
+func isNullExpression(expr *hclwrite.Expression) bool {
+	tokens := significantTokens(expr.BuildTokens(nil))
+	return len(tokens) == 1 && tokens[0].Type == hclsyntax.TokenIdent && string(tokens[0].Bytes) == "null"
+}
@@ -0,0 +1,239 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHasAttribute(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    bool
+	}{
+		{
+			name: "top level attribute",
+			src: `
+a0 = v0
+`,
+			address: "a0",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "attribute in block",
+			src: `
+b1 "l1" {
+  a1 = ""
+}
+`,
+			address: "b1.l1.a1",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "not found",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+`,
+			address: "b1.l1.a2",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name: "element of list",
+			src: `
+b1 {
+  a1 = ["v1", "v2"]
+}
+`,
+			address: "b1.a1[1]",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "element of list out of range",
+			src: `
+b1 {
+  a1 = ["v1", "v2"]
+}
+`,
+			address: "b1.a1[2]",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name: "key of object",
+			src: `
+b1 {
+  tags = { Name = "v1" }
+}
+`,
+			address: "b1.tags.Name",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "missing key of object",
+			src: `
+b1 {
+  tags = { Name = "v1" }
+}
+`,
+			address: "b1.tags.Owner",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name: "empty address",
+			src: `
+a0 = v0
+`,
+			address: "",
+			ok:      false,
+			want:    false,
+		},
+		{
+			name:    "parse error",
+			src:     `a0 = `,
+			address: "a0",
+			ok:      false,
+			want:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			got, err := HasAttribute(inStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %t", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAttributeState(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    AttributeState
+	}{
+		{
+			name: "present",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want:    AttributePresent,
+		},
+		{
+			name: "null",
+			src: `
+b1 {
+  a1 = null # comment
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want:    AttributeNull,
+		},
+		{
+			name: "null in expression",
+			src: `
+b1 {
+  a1 = var.a == null ? "" : var.a
+}
+`,
+			address: "b1.a1",
+			ok:      true,
+			want:    AttributePresent,
+		},
+		{
+			name: "absent",
+			src: `
+b1 {
+  a1 = null
+}
+`,
+			address: "b1.a2",
+			ok:      true,
+			want:    AttributeAbsent,
+		},
+		{
+			name: "null key of object",
+			src: `
+b1 {
+  tags = { Name = null, Owner = "v1" }
+}
+`,
+			address: "b1.tags.Name",
+			ok:      true,
+			want:    AttributeNull,
+		},
+		{
+			name: "present element of list",
+			src: `
+b1 {
+  a1 = [null, "v1"]
+}
+`,
+			address: "b1.a1[1]",
+			ok:      true,
+			want:    AttributePresent,
+		},
+		{
+			name: "absent key of object",
+			src: `
+b1 {
+  tags = { Name = null }
+}
+`,
+			address: "b1.tags.Owner",
+			ok:      true,
+			want:    AttributeAbsent,
+		},
+		{
+			name:    "parse error",
+			src:     `a0 = `,
+			address: "a0",
+			ok:      false,
+			want:    AttributeAbsent,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			got, err := GetAttributeState(inStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
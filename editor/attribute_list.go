@@ -0,0 +1,62 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// ListAttributes reads HCL from io.Reader, and writes names of all attributes
+// in matched blocks at a given address to io.Writer, one per line in source
+// order. If the address is empty, attributes in the top level body are
+// listed. Nested blocks are not listed.
+// If withValue is true, each name is followed by its value such as
+// `ami = "ami-123"`.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ListAttributes(r io.Reader, w io.Writer, filename string, address string, withValue bool) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &attributeList{address: address, withValue: withValue},
+	}
+
+	return e.Apply(r, w)
+}
+
+// attributeList is a Sink implementation to list attributes in blocks.
+type attributeList struct {
+	address string
+	// withValue is a flag to write a value after each name.
+	withValue bool
+}
+
+// Sink reads HCL and writes names of attributes in matched blocks.
+func (s *attributeList) Sink(inFile *hclwrite.File) ([]byte, error) {
+	var bodies []*hclwrite.Body
+	if len(s.address) == 0 {
+		bodies = append(bodies, inFile.Body())
+	} else {
+		blocks, err := findLongestMatchingBlocks(inFile.Body(), s.address)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range blocks {
+			bodies = append(bodies, b.Body())
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, body := range bodies {
+		for _, name := range attributeNames(body) {
+			line := name
+			if s.withValue {
+				line += " = " + getExpressionAsString(body.GetAttribute(name).Expr())
+			}
+			buf.WriteString(line + "\n")
+		}
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,111 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestListAttributes(t *testing.T) {
+	src := `a0 = v0
+
+resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro" # comment
+
+  root_block_device {
+    volume_size = 8
+  }
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-456"
+}
+`
+
+	cases := []struct {
+		name      string
+		src       string
+		address   string
+		withValue bool
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "names",
+			src:       src,
+			address:   "resource.aws_instance.web",
+			withValue: false,
+			ok:        true,
+			want: `ami
+instance_type
+`,
+		},
+		{
+			name:      "with value",
+			src:       src,
+			address:   "resource.aws_instance.web",
+			withValue: true,
+			ok:        true,
+			want: `ami = "ami-123"
+instance_type = "t3.micro"
+`,
+		},
+		{
+			name:      "nested block",
+			src:       src,
+			address:   "resource.aws_instance.web.root_block_device",
+			withValue: false,
+			ok:        true,
+			want: `volume_size
+`,
+		},
+		{
+			name:      "multiple blocks",
+			src:       src,
+			address:   "resource.aws_instance.*",
+			withValue: true,
+			ok:        true,
+			want: `ami = "ami-123"
+instance_type = "t3.micro"
+ami = "ami-456"
+`,
+		},
+		{
+			name:      "top level",
+			src:       src,
+			address:   "",
+			withValue: false,
+			ok:        true,
+			want: `a0
+`,
+		},
+		{
+			name:      "not found",
+			src:       src,
+			address:   "resource.aws_instance.app",
+			withValue: false,
+			ok:        true,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ListAttributes(inStream, outStream, "test", tc.address, tc.withValue)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
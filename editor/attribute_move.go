@@ -0,0 +1,125 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// MoveAttribute reads HCL from io.Reader, and moves a matched attribute at a
+// given address to a new address, and writes the updated HCL to io.Writer.
+// The attribute is moved as it is, including its raw expression tokens, a
+// trailing comment and leading comments above it, so it can be used for renaming an attribute and relocating it to
+// another existing block. If the destination is in the same body, the
+// attribute is renamed in place. Otherwise it is appended at the end of the
+// destination block.
+// It returns an error if the destination block doesn't exist or the
+// destination attribute already exists. If the source attribute is not found,
+// nothing is changed.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func MoveAttribute(r io.Reader, w io.Writer, filename string, from string, to string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeMove{from: from, to: to},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeMove is a filter implementation for moving an attribute.
+type attributeMove struct {
+	from string
+	to   string
+}
+
+// Filter reads HCL and moves a matched attribute.
+func (f *attributeMove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	attr, srcBody, err := findAttribute(inFile.Body(), f.from)
+	if err != nil {
+		return nil, err
+	}
+	if attr == nil {
+		return inFile, nil
+	}
+
+	from, err := splitAddress(f.from)
+	if err != nil {
+		return nil, err
+	}
+	srcName := unquoteSegment(from[len(from)-1])
+
+	to, err := splitAddress(f.to)
+	if err != nil {
+		return nil, err
+	}
+	dstName := unquoteSegment(to[len(to)-1])
+
+	dstBody := inFile.Body()
+	if len(to) > 1 {
+		dstAddr := strings.Join(to[:len(to)-1], ".")
+		blocks, err := findLongestMatchingBlocks(inFile.Body(), dstAddr)
+		if err != nil {
+			return nil, err
+		}
+		switch len(blocks) {
+		case 0:
+			return nil, fmt.Errorf("failed to move attribute. destination block not found: %s", dstAddr)
+		case 1:
+			dstBody = blocks[0].Body()
+		default:
+			return nil, fmt.Errorf("failed to move attribute. destination block is ambiguous: %s", dstAddr)
+		}
+	}
+
+	if dstBody.GetAttribute(dstName) != nil && !(dstBody == srcBody && srcName == dstName) {
+		return nil, fmt.Errorf("failed to move attribute. destination attribute already exists: %s", f.to)
+	}
+
+	// The hclwrite parser doesn't attach a multi-line comment (/* */) above the
+	// attribute to it, so find leading comments in tokens of the file to move
+	// them together.
+	tokens := inFile.BuildTokens(nil)
+	start, end := attributeWithLeadingComments(tokens, attr)
+	if start < 0 {
+		return nil, fmt.Errorf("failed to move attribute. tokens not found: %s", f.from)
+	}
+	attrTokens := tokens[start:end]
+	renamed := withTrailingNewline(renameAttributeTokens(attrTokens, dstName))
+
+	if dstBody == srcBody {
+		// rename in place to keep the position.
+		return replaceTokens(inFile, attrTokens, renamed)
+	}
+
+	// The renamed tokens are copies, so the original ones can be removed after
+	// appending them to the destination.
+	dstBody.AppendUnstructuredTokens(renamed)
+
+	// parse the result again to make appended tokens a structured attribute.
+	return replaceTokens(inFile, attrTokens, nil)
+}
+
+// renameAttributeTokens returns a copy of tokens of an attribute with a new
+// name. The name is the first identifier which is not a part of comments.
+func renameAttributeTokens(tokens hclwrite.Tokens, name string) hclwrite.Tokens {
+	renamed := make(hclwrite.Tokens, 0, len(tokens))
+	done := false
+	for _, t := range tokens {
+		c := *t
+		if !done && c.Type == hclsyntax.TokenIdent {
+			c.Bytes = []byte(name)
+			done = true
+		}
+		renamed = append(renamed, &c)
+	}
+
+	return renamed
+}
@@ -0,0 +1,261 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeMove(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		from string
+		to   string
+		ok   bool
+		want string
+	}{
+		{
+			name: "rename in place",
+			src: `
+b1 {
+  a1 = v1
+  // comment
+  a2 = [for x in var.xs : x] // inline
+  a3 = v3
+}
+`,
+			from: "b1.a2",
+			to:   "b1.new",
+			ok:   true,
+			want: `
+b1 {
+  a1 = v1
+  // comment
+  new = [for x in var.xs : x] // inline
+  a3  = v3
+}
+`,
+		},
+		{
+			name: "rename in place with a multi-line comment above",
+			src: `
+b1 {
+  a1 = v1
+
+  /* comment
+     for a2 */
+  a2 = v2
+  a3 = v3
+}
+`,
+			from: "b1.a2",
+			to:   "b1.new",
+			ok:   true,
+			want: `
+b1 {
+  a1 = v1
+
+  /* comment
+     for a2 */
+  new = v2
+  a3  = v3
+}
+`,
+		},
+		{
+			name: "rename in place at top level with comments above",
+			src: `
+/* comment */
+// lead
+a1 = v1
+a2 = v2
+`,
+			from: "a1",
+			to:   "new",
+			ok:   true,
+			want: `
+/* comment */
+// lead
+new = v1
+a2  = v2
+`,
+		},
+		{
+			name: "move to another block",
+			src: `
+b1 {
+  // comment
+  a1 = "${var.x}-y" // inline
+  a2 = v2
+}
+
+b2 "l1" {
+  a3 = v3
+}
+`,
+			from: "b1.a1",
+			to:   "b2.l1.a1",
+			ok:   true,
+			want: `
+b1 {
+  a2 = v2
+}
+
+b2 "l1" {
+  a3 = v3
+  // comment
+  a1 = "${var.x}-y" // inline
+}
+`,
+		},
+		{
+			name: "move with a multi-line comment above",
+			src: `
+b1 {
+  a1 = v1
+  /* comment
+     for a2 */
+  # lead
+  a2 = v2 # inline
+  a3 = v3
+}
+
+b2 {
+  a4 = v4
+}
+`,
+			from: "b1.a2",
+			to:   "b2.a2",
+			ok:   true,
+			want: `
+b1 {
+  a1 = v1
+  a3 = v3
+}
+
+b2 {
+  a4 = v4
+  /* comment
+     for a2 */
+  # lead
+  a2 = v2 # inline
+}
+`,
+		},
+		{
+			name: "move with a comment above to top level",
+			src: `
+b1 {
+  a1 = v1
+  /* comment */
+  a2 = v2
+}
+`,
+			from: "b1.a2",
+			to:   "a0",
+			ok:   true,
+			want: `
+b1 {
+  a1 = v1
+}
+/* comment */
+a0 = v2
+`,
+		},
+		{
+			name: "move to top level",
+			src: `
+a0 = v0
+b1 {
+  a1 = v1
+}
+`,
+			from: "b1.a1",
+			to:   "a2",
+			ok:   true,
+			want: `
+a0 = v0
+b1 {
+}
+a2 = v1
+`,
+		},
+		{
+			name: "source not found",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			from: "b1.hoge",
+			to:   "b1.fuga",
+			ok:   true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "destination block not found",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			from: "b1.a1",
+			to:   "b2.a1",
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "destination block is ambiguous",
+			src: `
+b1 {
+  a1 = v1
+}
+b2 {
+}
+b2 {
+}
+`,
+			from: "b1.a1",
+			to:   "b2.a1",
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "destination attribute already exists",
+			src: `
+b1 {
+  a1 = v1
+  a2 = v2
+}
+`,
+			from: "b1.a1",
+			to:   "b1.a2",
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := MoveAttribute(inStream, outStream, "test", tc.from, tc.to)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
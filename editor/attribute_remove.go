@@ -2,7 +2,6 @@ package editor
 
 import (
 	"io"
-	"strings"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
@@ -11,14 +10,37 @@ import (
 // and writes the updated HCL to io.Writer.
 // Note that a filename is used only for an error message.
 // If an error occurs, Nothing is written to the output stream.
-func RemoveAttribute(r io.Reader, w io.Writer, filename string, address string) error {
+func RemoveAttribute(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
 	e := &Editor{
 		source: &parser{filename: filename},
 		filters: []Filter{
 			&attributeRemove{address: address},
 		},
-		sink: &formater{},
+		sink:          &formater{},
+		streamAddress: address,
 	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// RemoveAttributes is the same as RemoveAttribute, but removes attributes at
+// multiple addresses in a single pass by chaining filters. The addresses are
+// applied in order and an address which doesn't match anything is ignored.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func RemoveAttributes(r io.Reader, w io.Writer, filename string, addresses []string, opts ...Option) error {
+	filters := []Filter{}
+	for _, address := range addresses {
+		filters = append(filters, &attributeRemove{address: address})
+	}
+
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: filters,
+		sink:    &formater{},
+	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
@@ -29,16 +51,29 @@ type attributeRemove struct {
 }
 
 // Filter reads HCL and remove a matched attribute at a given address.
+// If the address contains a pattern, all matched attributes are removed.
 func (f *attributeRemove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
-	attr, body, err := findAttribute(inFile.Body(), f.address)
+	matches, err := findTargetAttributes(inFile.Body(), f.address)
 	if err != nil {
 		return nil, err
 	}
 
-	if attr != nil {
-		a := strings.Split(f.address, ".")
-		attrName := a[len(a)-1]
-		body.RemoveAttribute(attrName)
+	if len(matches) == 0 {
+		// fallback to an element in a value of attribute such as a.b[1].
+		elem, err := findAttributeElement(inFile.Body(), f.address)
+		if err != nil {
+			return nil, err
+		}
+		if elem != nil {
+			if err := elem.remove(); err != nil {
+				return nil, err
+			}
+		}
+		return inFile, nil
+	}
+
+	for _, m := range matches {
+		m.body.RemoveAttribute(m.name)
 	}
 
 	return inFile, nil
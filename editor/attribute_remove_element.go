@@ -0,0 +1,110 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// RemoveElement reads HCL from io.Reader, and removes elements from a list of
+// matched attribute, and writes the updated HCL to io.Writer.
+// If index is not negative, the element at the index is removed. Otherwise,
+// all elements equal to value are removed. An element is equal to the value
+// if its source is the same as the value, or it is a string literal whose
+// unquoted value is the same as the value.
+// Separators around the removed element are cleaned up, and an element on its
+// own line is removed with the line including a trailing comment.
+// It returns an error if the matched value is not a list.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func RemoveElement(r io.Reader, w io.Writer, filename string, address string, value string, index int, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&elementRemove{address: address, value: value, index: index},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// elementRemove is a filter implementation for removing elements from a list.
+type elementRemove struct {
+	address string
+	value   string
+	// index is an index of an element to be removed.
+	// If negative, elements equal to value are removed.
+	index int
+}
+
+// Filter reads HCL and removes elements from matched lists.
+func (f *elementRemove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	if f.index >= 0 {
+		matches, err := findAttributeValues(inFile.Body(), f.address)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, m := range matches {
+			if _, ok := m.expr.(*hclsyntax.TupleConsExpr); !ok {
+				return nil, fmt.Errorf("failed to remove element. the value is not a list: %s", f.address)
+			}
+			if c := m.child(valueStep{index: f.index}); c != nil {
+				if err := c.remove(); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return inFile, nil
+	}
+
+	// Since removing an element updates the attribute, we find the lists again
+	// after each removal.
+	for {
+		target, err := f.findValue(inFile.Body())
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			return inFile, nil
+		}
+
+		if err := target.remove(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// findValue returns the first element equal to the value in matched lists.
+// If not found, it returns nil.
+func (f *elementRemove) findValue(body *hclwrite.Body) (*elementMatch, error) {
+	matches, err := findAttributeValues(body, f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		tuple, ok := m.expr.(*hclsyntax.TupleConsExpr)
+		if !ok {
+			return nil, fmt.Errorf("failed to remove element. the value is not a list: %s", f.address)
+		}
+
+		for i := range tuple.Exprs {
+			c := m.child(valueStep{index: i})
+			raw := c.value()
+			if raw == f.value {
+				return c, nil
+			}
+			if s, ok := stringLiteralValue(raw); ok && s == f.value {
+				return c, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
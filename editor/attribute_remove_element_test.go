@@ -0,0 +1,151 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestElementRemove(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		index   int
+		ok      bool
+		want    string
+	}{
+		{
+			name: "by value",
+			src: `
+a = ["x", "y", "z"]
+`,
+			address: "a",
+			value:   "y",
+			index:   -1,
+			ok:      true,
+			want: `
+a = ["x", "z"]
+`,
+		},
+		{
+			name: "by quoted value",
+			src: `
+a = ["x", "y", "z"]
+`,
+			address: "a",
+			value:   `"z"`,
+			index:   -1,
+			ok:      true,
+			want: `
+a = ["x", "y"]
+`,
+		},
+		{
+			name: "by reference",
+			src: `
+a = [aws_security_group.x.id, aws_security_group.y.id]
+`,
+			address: "a",
+			value:   "aws_security_group.x.id",
+			index:   -1,
+			ok:      true,
+			want: `
+a = [aws_security_group.y.id]
+`,
+		},
+		{
+			name: "all duplicated values in multi-line",
+			src: `
+b {
+  a = [
+    "x",
+    "y", # comment y
+    "z",
+    "y",
+  ]
+}
+`,
+			address: "b.a",
+			value:   "y",
+			index:   -1,
+			ok:      true,
+			want: `
+b {
+  a = [
+    "x",
+    "z",
+  ]
+}
+`,
+		},
+		{
+			name: "by index",
+			src: `
+a = ["x", "y", "z"]
+`,
+			address: "a",
+			index:   0,
+			ok:      true,
+			want: `
+a = ["y", "z"]
+`,
+		},
+		{
+			name: "index out of range",
+			src: `
+a = ["x", "y", "z"]
+`,
+			address: "a",
+			index:   3,
+			ok:      true,
+			want: `
+a = ["x", "y", "z"]
+`,
+		},
+		{
+			name: "value not found",
+			src: `
+a = ["x", "y", "z"]
+`,
+			address: "a",
+			value:   "w",
+			index:   -1,
+			ok:      true,
+			want: `
+a = ["x", "y", "z"]
+`,
+		},
+		{
+			name: "not a list",
+			src: `
+a = "x"
+`,
+			address: "a",
+			value:   "x",
+			index:   -1,
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := RemoveElement(inStream, outStream, "test", tc.address, tc.value, tc.index)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
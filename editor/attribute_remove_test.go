@@ -82,6 +82,31 @@ a0 = v0
 b1 "l1" {
   a1 = v1
 }
+`,
+		},
+		{
+			name: "wildcard removes all matched attributes",
+			src: `
+b1 {
+  b2 {
+    a1 = v1
+    a2 = v2
+  }
+  b3 {
+    a1 = v1
+  }
+}
+`,
+			address: "b1.*.a1",
+			ok:      true,
+			want: `
+b1 {
+  b2 {
+    a2 = v2
+  }
+  b3 {
+  }
+}
 `,
 		},
 		{
@@ -123,3 +148,71 @@ b1 "l1" {
 		})
 	}
 }
+
+func TestAttributeRemoveMultiple(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		addresses []string
+		ok        bool
+		want      string
+	}{
+		{
+			name: "multiple addresses",
+			src: `
+a0 = v0
+b1 {
+  a1 = v1
+  a2 = v2
+}
+`,
+			addresses: []string{"a0", "b1.a2", "hoge"},
+			ok:        true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "no addresses",
+			src: `
+a0 = v0
+`,
+			addresses: []string{},
+			ok:        true,
+			want: `
+a0 = v0
+`,
+		},
+		{
+			name: "invalid address",
+			src: `
+a0 = v0
+`,
+			addresses: []string{"a0", ""},
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := RemoveAttributes(inStream, outStream, "test", tc.addresses)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
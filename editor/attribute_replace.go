@@ -0,0 +1,153 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// ReplaceAttribute reads HCL from io.Reader, and applies a regular expression
+// substitution to an expression of matched attributes at a given address, and
+// writes the updated HCL to io.Writer.
+// The substitution is given in the form of s/pattern/replacement/ like sed,
+// where any character can be used as a delimiter instead of / and a trailing
+// g flag replaces all occurrences instead of the first one. The pattern is a
+// regular expression in the Go syntax, and the replacement can refer to
+// submatches such as $1. This is useful for rewriting a part of a value such
+// as a version in a module source URL.
+// The expression is edited as text, and the name and comments of the
+// attribute are kept as they are.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ReplaceAttribute(r io.Reader, w io.Writer, filename string, address string, substitution string, opts ...Option) error {
+	s, err := parseSubstitution(substitution)
+	if err != nil {
+		return err
+	}
+
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeReplace{address: address, substitution: s},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// substitution is a parsed form of s/pattern/replacement/flags.
+type substitution struct {
+	pattern     *regexp.Regexp
+	replacement string
+	// global is a flag to replace all occurrences.
+	global bool
+}
+
+// parseSubstitution parses a substitution in the form of
+// s/pattern/replacement/flags. The delimiter can be escaped with a backslash
+// in the pattern and the replacement.
+func parseSubstitution(src string) (*substitution, error) {
+	if len(src) < 2 || src[0] != 's' {
+		return nil, fmt.Errorf("failed to parse substitution. expected s/pattern/replacement/: %s", src)
+	}
+
+	delim := src[1:2]
+	parts := []string{}
+	var part strings.Builder
+	rest := src[2:]
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case rest[i] == '\\' && i+1 < len(rest) && rest[i+1:i+2] == delim:
+			part.WriteByte(rest[i+1])
+			i++
+		case rest[i:i+1] == delim && len(parts) < 2:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(rest[i])
+		}
+	}
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("failed to parse substitution. expected s/pattern/replacement/: %s", src)
+	}
+
+	flags := part.String()
+	if flags != "" && flags != "g" {
+		return nil, fmt.Errorf("failed to parse substitution. unknown flags %q: %s", flags, src)
+	}
+
+	pattern, err := regexp.Compile(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse substitution. invalid pattern: %s", err)
+	}
+
+	return &substitution{pattern: pattern, replacement: parts[1], global: flags == "g"}, nil
+}
+
+// apply returns a given string with the substitution applied.
+func (s *substitution) apply(src string) string {
+	if s.global {
+		return s.pattern.ReplaceAllString(src, s.replacement)
+	}
+
+	loc := s.pattern.FindStringSubmatchIndex(src)
+	if loc == nil {
+		return src
+	}
+	dst := s.pattern.ExpandString(nil, s.replacement, src, loc)
+
+	return src[:loc[0]] + string(dst) + src[loc[1]:]
+}
+
+// attributeReplace is a filter implementation to apply a substitution to
+// expressions of attributes.
+type attributeReplace struct {
+	address      string
+	substitution *substitution
+}
+
+// Filter reads HCL and applies the substitution to matched attributes.
+func (f *attributeReplace) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matches, err := findTargetAttributes(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := splitAddress(f.address)
+	if err != nil {
+		return nil, err
+	}
+	attrName := unquoteSegment(a[len(a)-1])
+
+	changed := false
+	for _, m := range matches {
+		current := getExpressionAsString(m.attr.Expr())
+		value := f.substitution.apply(current)
+		if value == current {
+			continue
+		}
+
+		value = normalizeMultilineExpression(value, attributeIndent(m.attr))
+		expr, err := buildExpression(attrName, value)
+		if err != nil {
+			return nil, err
+		}
+		m.body.SetAttributeRaw(attrName, expr.BuildTokens(nil))
+		changed = true
+	}
+
+	if changed {
+		// make sure that the result is still valid.
+		if _, err := safeParseConfig(inFile.Bytes(), "generated_by_attributeReplace", hcl.Pos{Line: 1, Column: 1}); err != nil {
+			return nil, fmt.Errorf("failed to replace attribute: %s", err)
+		}
+	}
+
+	return inFile, nil
+}
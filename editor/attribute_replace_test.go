@@ -0,0 +1,186 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeReplace(t *testing.T) {
+	src := `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3" # pinned
+  tags = {
+    Version = "v1.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.com/db.git?ref=v1.2.3"
+}
+`
+
+	cases := []struct {
+		name         string
+		address      string
+		substitution string
+		ok           bool
+		want         string
+	}{
+		{
+			name:         "simple",
+			address:      "module.vpc.source",
+			substitution: "s/ref=v1.2.3/ref=v1.3.0/",
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.3.0" # pinned
+  tags = {
+    Version = "v1.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.com/db.git?ref=v1.2.3"
+}
+`,
+		},
+		{
+			name:         "submatch and wildcard",
+			address:      "module.*.source",
+			substitution: `s/ref=v(\d+)\.\d+\.\d+/ref=v${1}.9.0/`,
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.9.0" # pinned
+  tags = {
+    Version = "v1.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.com/db.git?ref=v1.9.0"
+}
+`,
+		},
+		{
+			name:         "escaped delimiter",
+			address:      "module.db.source",
+			substitution: `s/example.com\/db/example.org\/db/`,
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3" # pinned
+  tags = {
+    Version = "v1.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.org/db.git?ref=v1.2.3"
+}
+`,
+		},
+		{
+			name:         "custom delimiter",
+			address:      "module.db.source",
+			substitution: "s|https://example.com|https://example.org|",
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3" # pinned
+  tags = {
+    Version = "v1.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.org/db.git?ref=v1.2.3"
+}
+`,
+		},
+		{
+			name:         "first only",
+			address:      "module.vpc.tags",
+			substitution: "s/[0-9]/x/",
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3" # pinned
+  tags = {
+    Version = "vx.2.3"
+  }
+}
+module "db" {
+  source = "git::https://example.com/db.git?ref=v1.2.3"
+}
+`,
+		},
+		{
+			name:         "global",
+			address:      "module.vpc.tags",
+			substitution: "s/[0-9]/x/g",
+			ok:           true,
+			want: `module "vpc" {
+  source = "git::https://example.com/vpc.git?ref=v1.2.3" # pinned
+  tags = {
+    Version = "vx.x.x"
+  }
+}
+module "db" {
+  source = "git::https://example.com/db.git?ref=v1.2.3"
+}
+`,
+		},
+		{
+			name:         "no match",
+			address:      "module.vpc.source",
+			substitution: "s/hoge/fuga/",
+			ok:           true,
+			want:         src,
+		},
+		{
+			name:         "not found",
+			address:      "module.vpc.hoge",
+			substitution: "s/v1/v2/",
+			ok:           true,
+			want:         src,
+		},
+		{
+			name:         "invalid result",
+			address:      "module.db.source",
+			substitution: `s/"$//`,
+			ok:           false,
+			want:         "",
+		},
+		{
+			name:         "invalid pattern",
+			address:      "module.db.source",
+			substitution: "s/(/x/",
+			ok:           false,
+			want:         "",
+		},
+		{
+			name:         "invalid form",
+			address:      "module.db.source",
+			substitution: "s/v1/v2",
+			ok:           false,
+			want:         "",
+		},
+		{
+			name:         "unknown flags",
+			address:      "module.db.source",
+			substitution: "s/v1/v2/i",
+			ok:           false,
+			want:         "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := ReplaceAttribute(inStream, outStream, "test", tc.address, tc.substitution)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -6,21 +6,100 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 // SetAttribute reads HCL from io.Reader, and updates a value of matched
 // attribute, and writes the updated HCL to io.Writer.
-// Note that a filename is used only for an error message.
+// If the address refers to a key of an object in a value of attribute such as
+// a.tags.Owner and the object lacks the key, the key is inserted.
+// If the input is written in the JSON syntax such as *.tf.json, the value
+// must be a JSON value, and only the matched value is replaced.
+// Note that a filename is used only for an error message and detecting the
+// JSON syntax by the .json extension.
 // If an error occurs, Nothing is written to the output stream.
-func SetAttribute(r io.Reader, w io.Writer, filename string, address string, value string) error {
+func SetAttribute(r io.Reader, w io.Writer, filename string, address string, value string, opts ...Option) error {
 	e := &Editor{
 		source: &parser{filename: filename},
 		filters: []Filter{
 			&attributeSet{address: address, value: value},
 		},
+		sink:          &formater{},
+		streamAddress: address,
+		jsonEdit: func(src []byte) ([]byte, error) {
+			return setJSONAttribute(src, address, value)
+		},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// SetAttributeRawMultiline is the same as SetAttribute, but accepts a raw
+// expression which spans multiple lines such as an object or a heredoc.
+// Newlines in the expression are preserved and a flush heredoc (<<-) is
+// re-indented to the nesting level of the attribute. A trailing comment of
+// the attribute is moved above it if the expression spans multiple lines,
+// because nothing can follow the closing marker of heredoc. It returns an
+// error if the result cannot be parsed.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetAttributeRawMultiline(r io.Reader, w io.Writer, filename string, address string, expr string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeSet{address: address, value: expr, multiline: true},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// SetAttributeAfter is the same as SetAttribute, but creates the attribute
+// if it doesn't exist in the first matching block, and inserts it immediately
+// after the attribute named after for readability. If after doesn't exist,
+// the new attribute is appended at the end of the body.
+// An existing attribute is updated in place and the anchor is ignored.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetAttributeAfter(r io.Reader, w io.Writer, filename string, address string, value string, after string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeSet{address: address, value: value, create: true, after: after},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// SetAttributeIfValue is the same as SetAttribute, but updates matched
+// attributes only if all of their current expressions equal to old, which
+// makes concurrent automation safe against racing edits like compare-and-swap.
+// The expressions are compared as text in the same form as GetAttribute
+// writes, ignoring leading and trailing whitespace.
+// If they don't match or the attribute is not found, it returns an error
+// unless skipMismatch is true, in which case the input is written as it is.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetAttributeIfValue(r io.Reader, w io.Writer, filename string, address string, value string, old string, skipMismatch bool, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeCompareAndSet{
+				attributeSet: attributeSet{address: address, value: value},
+				old:          old,
+				skipMismatch: skipMismatch,
+			},
+		},
 		sink: &formater{},
 	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
@@ -29,31 +108,304 @@ func SetAttribute(r io.Reader, w io.Writer, filename string, address string, val
 type attributeSet struct {
 	address string
 	value   string
+	// multiline is a flag to treat the value as a multi-line expression.
+	multiline bool
+	// create is a flag to create the attribute if it doesn't exist.
+	create bool
+	// after is a name of attribute after which a new attribute is inserted.
+	// If empty or not found, it is appended at the end of the body.
+	after string
 }
 
 // Filter reads HCL and updates a value of matched an attribute at a given address.
+// If the address contains a pattern, all matched attributes are updated.
 func (f *attributeSet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
-	attr, body, err := findAttribute(inFile.Body(), f.address)
+	matches, err := findTargetAttributes(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(matches) == 0 {
+		// fallback to an element in a value of attribute such as a.b[1].
+		elem, err := findAttributeElement(inFile.Body(), f.address)
+		if err != nil {
+			return nil, err
+		}
+		if elem != nil {
+			if err := elem.set(f.value); err != nil {
+				return nil, err
+			}
+			return inFile, nil
+		}
+
+		// create a missing key in an object such as a.tags.Owner.
+		inserted, err := insertObjectKey(inFile.Body(), f.address, f.value)
+		if err != nil {
+			return nil, err
+		}
+		if inserted {
+			return inFile, nil
+		}
+	}
+
+	if len(matches) == 0 && f.create {
+		return f.createAttribute(inFile)
+	}
+
+	a, err := splitAddress(f.address)
 	if err != nil {
 		return nil, err
 	}
+	attrName := unquoteSegment(a[len(a)-1])
 
-	if attr != nil {
-		a := strings.Split(f.address, ".")
-		attrName := a[len(a)-1]
+	// A comment after the closing marker of heredoc is not valid, so trailing
+	// comments of attributes set to a multi-line value are moved above them.
+	moved := make(map[*hclwrite.Token]*hclwrite.Token)
+	for _, m := range matches {
+		value := f.value
+		if f.multiline {
+			value = normalizeMultilineExpression(value, attributeIndent(m.attr))
+			if strings.Contains(strings.TrimSuffix(value, "\n"), "\n") {
+				tokens := m.attr.BuildTokens(nil)
+				if i := trailingCommentIndex(tokens, tokens); i >= 0 {
+					moved[attributeNameToken(tokens)] = tokens[i]
+				}
+			}
+		}
 
 		// To delegate expression parsing to the hclwrite parser,
 		// We build a new expression and set back to the attribute by tokens.
-		expr, err := buildExpression(attrName, f.value)
+		expr, err := buildExpression(attrName, value)
 		if err != nil {
 			return nil, err
 		}
-		body.SetAttributeRaw(attrName, expr.BuildTokens(nil))
+		m.body.SetAttributeRaw(attrName, expr.BuildTokens(nil))
+	}
+
+	if len(moved) != 0 {
+		inFile, err = moveCommentsAbove(inFile, moved)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(matches) != 0 && f.multiline {
+		// make sure that the result is still valid.
+		if _, err := safeParseConfig(inFile.Bytes(), "generated_by_attributeSet", hcl.Pos{Line: 1, Column: 1}); err != nil {
+			return nil, fmt.Errorf("failed to set a multi-line expression: %s", err)
+		}
 	}
 
 	return inFile, nil
 }
 
+// attributeCompareAndSet is a filter implementation to update attributes only
+// if their current values equal to an expected one.
+type attributeCompareAndSet struct {
+	attributeSet
+	// old is an expected expression of the current value.
+	old string
+	// skipMismatch is a flag to leave the input as it is instead of returning
+	// an error when the current value doesn't match.
+	skipMismatch bool
+}
+
+// Filter reads HCL and updates matched attributes if their values equal to
+// the expected one.
+func (f *attributeCompareAndSet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matches, err := findTargetAttributes(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch := ""
+	if len(matches) == 0 {
+		mismatch = "the attribute is not found"
+	}
+	want := strings.TrimSpace(f.old)
+	for _, m := range matches {
+		if got := getExpressionAsString(m.attr.Expr()); got != want {
+			mismatch = fmt.Sprintf("got %s, want %s", got, want)
+			break
+		}
+	}
+
+	if len(mismatch) != 0 {
+		if f.skipMismatch {
+			return inFile, nil
+		}
+		return nil, fmt.Errorf("failed to set attribute. the current value doesn't match: %s: %s", f.address, mismatch)
+	}
+
+	return f.attributeSet.Filter(inFile)
+}
+
+// createAttribute creates a new attribute in the first matching block.
+// If the block is not found, the file is returned as it is.
+func (f *attributeSet) createAttribute(inFile *hclwrite.File) (*hclwrite.File, error) {
+	a, err := splitAddress(f.address)
+	if err != nil {
+		return nil, err
+	}
+	attrName := unquoteSegment(a[len(a)-1])
+
+	body := inFile.Body()
+	if len(a) > 1 {
+		blocks, err := findLongestMatchingBlocks(body, strings.Join(a[:len(a)-1], "."))
+		if err != nil {
+			return nil, err
+		}
+		if len(blocks) == 0 {
+			return inFile, nil
+		}
+		body = blocks[0].Body()
+	}
+
+	expr, err := buildExpression(attrName, f.value)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := body.GetAttribute(f.after)
+	if len(f.after) == 0 || anchor == nil {
+		body.SetAttributeRaw(attrName, expr.BuildTokens(nil))
+		return inFile, nil
+	}
+
+	// The hclwrite doesn't provide a way to insert an attribute at an arbitrary
+	// position, so we insert tokens of the new attribute after the anchor.
+	newAttr := hclwrite.NewEmptyFile()
+	newAttr.Body().SetAttributeRaw(attrName, expr.BuildTokens(nil))
+
+	anchorTokens := anchor.BuildTokens(nil)
+	var tokens hclwrite.Tokens
+	tokens = append(tokens, withTrailingNewline(anchorTokens)...)
+	tokens = append(tokens, trimEOF(newAttr.BuildTokens(nil))...)
+
+	return replaceTokens(inFile, anchorTokens, tokens)
+}
+
+// normalizeMultilineExpression returns a multi-line expression which can be
+// parsed by buildExpression. It normalizes line endings and appends a
+// trailing newline, because the closing marker of heredoc must be followed by
+// a newline. A flush heredoc (<<-) is re-indented for a given indent of the
+// attribute.
+func normalizeMultilineExpression(value string, indent int) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.TrimRight(value, "\n")
+
+	if strings.HasPrefix(strings.TrimSpace(value), "<<-") {
+		value = reindentFlushHeredoc(strings.TrimSpace(value), indent)
+	}
+
+	return value + "\n"
+}
+
+// reindentFlushHeredoc re-indents lines of a flush heredoc.
+// The content is indented one level deeper than the attribute and the
+// closing marker is aligned with the attribute.
+// If the value doesn't end with the closing marker, it returns the value as it is.
+func reindentFlushHeredoc(value string, indent int) string {
+	lines := strings.Split(value, "\n")
+	if len(lines) < 2 {
+		return value
+	}
+
+	marker := strings.TrimSpace(strings.TrimPrefix(lines[0], "<<-"))
+	last := len(lines) - 1
+	if strings.TrimSpace(lines[last]) != marker {
+		return value
+	}
+
+	content := lines[1:last]
+	minIndent := -1
+	for _, l := range content {
+		if len(strings.TrimSpace(l)) == 0 {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if minIndent < 0 || n < minIndent {
+			minIndent = n
+		}
+	}
+
+	prefix := strings.Repeat(" ", indent+2)
+	reindented := []string{lines[0]}
+	for _, l := range content {
+		if len(strings.TrimSpace(l)) == 0 {
+			reindented = append(reindented, "")
+			continue
+		}
+		reindented = append(reindented, prefix+l[minIndent:])
+	}
+	reindented = append(reindented, strings.Repeat(" ", indent)+marker)
+
+	return strings.Join(reindented, "\n")
+}
+
+// attributeNameToken returns a token of the name in given tokens of an
+// attribute, that is, the first token which is not a part of lead comments.
+func attributeNameToken(tokens hclwrite.Tokens) *hclwrite.Token {
+	for _, t := range tokens {
+		if t.Type != hclsyntax.TokenComment && t.Type != hclsyntax.TokenNewline {
+			return t
+		}
+	}
+
+	return nil
+}
+
+// moveCommentsAbove moves comments to the line above given tokens, and
+// returns a new file by parsing the result. The map is keyed by a token
+// before which the comment is placed.
+// Tokens are compared by identity, so they must be built from the same tree.
+func moveCommentsAbove(inFile *hclwrite.File, comments map[*hclwrite.Token]*hclwrite.Token) (*hclwrite.File, error) {
+	skip := make(map[*hclwrite.Token]bool)
+	for _, c := range comments {
+		skip[c] = true
+	}
+
+	var tokens hclwrite.Tokens
+	for _, t := range inFile.BuildTokens(nil) {
+		if skip[t] {
+			if endsWithNewline(t) {
+				// a single-line comment consumes the newline of the line.
+				tokens = append(tokens, &hclwrite.Token{
+					Type:  hclsyntax.TokenNewline,
+					Bytes: []byte("\n"),
+				})
+			}
+			continue
+		}
+		if c, ok := comments[t]; ok {
+			moved := *c
+			moved.SpacesBefore = 0
+			if !endsWithNewline(c) {
+				// a multi-line comment (/* */) is not terminated by a newline.
+				moved.Bytes = append(append([]byte{}, c.Bytes...), '\n')
+			}
+			tokens = append(tokens, &moved)
+		}
+		tokens = append(tokens, t)
+	}
+
+	return safeParseConfig(tokens.Bytes(), "generated_by_moveCommentsAbove", hcl.Pos{Line: 1, Column: 1})
+}
+
+// attributeIndent returns the number of spaces before the name of a given
+// attribute, which means the indent of the attribute.
+func attributeIndent(attr *hclwrite.Attribute) int {
+	for _, t := range attr.BuildTokens(nil) {
+		if t.Type == hclsyntax.TokenComment || t.Type == hclsyntax.TokenNewline {
+			// skip lead comments
+			continue
+		}
+		return t.SpacesBefore
+	}
+
+	return 0
+}
+
 // buildExpression returns a new expressions for a given name and value of attribute.
 // At the time of wrting this, there is no way to parse expression from string.
 // So we generate a temporarily config on memory and parse it, and extract a generated expression.
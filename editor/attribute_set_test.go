@@ -132,6 +132,112 @@ a0 = v0
 b1 "l1" {
   a1 = v1
 }
+`,
+		},
+		{
+			name: "wildcard updates all matched attributes",
+			src: `
+resource "aws_instance" "web" {
+  ami = "ami-1"
+}
+resource "aws_instance" "db" {
+  ami = "ami-2"
+}
+resource "aws_s3_bucket" "log" {
+  ami = "ami-3"
+}
+`,
+			address: "resource.aws_instance.*.ami",
+			value:   `"ami-4"`,
+			ok:      true,
+			want: `
+resource "aws_instance" "web" {
+  ami = "ami-4"
+}
+resource "aws_instance" "db" {
+  ami = "ami-4"
+}
+resource "aws_s3_bucket" "log" {
+  ami = "ami-3"
+}
+`,
+		},
+		{
+			name: "regexp updates all matched attributes",
+			src: `
+resource "aws_instance" "prod_web" {
+  ami = "ami-1"
+}
+resource "aws_instance" "prod_db" {
+  ami = "ami-2"
+}
+resource "aws_instance" "dev_web" {
+  ami = "ami-3"
+}
+`,
+			address: "resource.aws_instance./prod_.*/.ami",
+			value:   `"ami-4"`,
+			ok:      true,
+			want: `
+resource "aws_instance" "prod_web" {
+  ami = "ami-4"
+}
+resource "aws_instance" "prod_db" {
+  ami = "ami-4"
+}
+resource "aws_instance" "dev_web" {
+  ami = "ami-3"
+}
+`,
+		},
+		{
+			name: "index of repeated nested blocks",
+			src: `
+b1 {
+  b2 {
+    a2 = v1
+  }
+  b2 {
+    a2 = v2
+  }
+}
+`,
+			address: "b1.b2[1].a2",
+			value:   "v3",
+			ok:      true,
+			want: `
+b1 {
+  b2 {
+    a2 = v1
+  }
+  b2 {
+    a2 = v3
+  }
+}
+`,
+		},
+		{
+			name: "recursive descent updates attributes at any depth",
+			src: `
+tags = {}
+b1 {
+  tags = {}
+  b2 "l1" {
+    tags = {}
+  }
+}
+`,
+			address: "**.tags",
+			value:   `{ "Owner" = "me" }`,
+			ok:      true,
+			want: `
+tags = {}
+b1 {
+  tags = { "Owner" = "me" }
+  b2 "l1" {
+    tags = { "Owner" = "me" }
+  }
+}
 `,
 		},
 		{
@@ -174,3 +280,460 @@ b1 "l1" {
 		})
 	}
 }
+
+func TestAttributeSetRawMultiline(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		expr    string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "multi-line object",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.a1",
+			expr: `{
+  k1 = "v1"
+  k2 = [
+    1,
+  ]
+}`,
+			ok: true,
+			want: `
+b1 {
+  a1 = {
+    k1 = "v1"
+    k2 = [
+      1,
+    ]
+  }
+}
+`,
+		},
+		{
+			name: "heredoc without a trailing newline",
+			src: `
+b1 {
+  a1 = v1
+  a2 = v2
+}
+`,
+			address: "b1.a1",
+			expr: `<<EOF
+{
+  "k": "v"
+}
+EOF`,
+			ok: true,
+			want: `
+b1 {
+  a1 = <<EOF
+{
+  "k": "v"
+}
+EOF
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "flush heredoc is re-indented",
+			src: `
+b1 {
+  b2 {
+    a1 = v1
+  }
+}
+`,
+			address: "b1.b2.a1",
+			expr:    "<<-EOF\r\n{\r\n  \"k\": \"v\"\r\n}\r\nEOF\r\n",
+			ok:      true,
+			want: `
+b1 {
+  b2 {
+    a1 = <<-EOF
+      {
+        "k": "v"
+      }
+    EOF
+  }
+}
+`,
+		},
+		{
+			name: "heredoc on attribute with trailing comment",
+			src: `
+b1 {
+  # lead
+  a1 = v1 # trailing
+  a2 = v2 /* block */
+}
+`,
+			address: "b1.a1",
+			expr: `<<EOF
+foo
+EOF`,
+			ok: true,
+			want: `
+b1 {
+  # lead
+  # trailing
+  a1 = <<EOF
+foo
+EOF
+  a2 = v2 /* block */
+}
+`,
+		},
+		{
+			name: "heredoc on attribute with trailing multi-line comment",
+			src: `
+b1 {
+  a1 = v1 /* block */
+}
+`,
+			address: "b1.a1",
+			expr: `<<EOF
+foo
+EOF`,
+			ok: true,
+			want: `
+b1 {
+  /* block */
+  a1 = <<EOF
+foo
+EOF
+}
+`,
+		},
+		{
+			name: "single-line expression keeps trailing comment",
+			src: `
+b1 {
+  a1 = v1 # trailing
+}
+`,
+			address: "b1.a1",
+			expr:    "\"foo\"\n",
+			ok:      true,
+			want: `
+b1 {
+  a1 = "foo" # trailing
+}
+`,
+		},
+		{
+			name: "invalid expression",
+			src: `
+a1 = v1
+`,
+			address: "a1",
+			expr: `{
+  k1 =
+}`,
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetAttributeRawMultiline(inStream, outStream, "test", tc.address, tc.expr)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeSetAfter(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		after   string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "insert after anchor",
+			src: `
+b1 {
+  a1 = v1
+  // comment
+  a2 = v2 // inline
+  a3 = v3
+}
+`,
+			address: "b1.new",
+			value:   "v4",
+			after:   "a2",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+  // comment
+  a2  = v2 // inline
+  new = v4
+  a3  = v3
+}
+`,
+		},
+		{
+			name: "anchor is the last attribute at top level without newline",
+			src: `a1 = v1
+a2 = v2`,
+			address: "new",
+			value:   "v3",
+			after:   "a2",
+			ok:      true,
+			want: `a1  = v1
+a2  = v2
+new = v3
+`,
+		},
+		{
+			name: "anchor not found",
+			src: `
+b1 {
+  a1 = v1
+  a2 = v2
+}
+`,
+			address: "b1.new",
+			value:   "v3",
+			after:   "hoge",
+			ok:      true,
+			want: `
+b1 {
+  a1  = v1
+  a2  = v2
+  new = v3
+}
+`,
+		},
+		{
+			name: "update in place ignores anchor",
+			src: `
+b1 {
+  a1 = v1
+  a2 = v2
+  a3 = v3
+}
+`,
+			address: "b1.a3",
+			value:   "v4",
+			after:   "a1",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+  a2 = v2
+  a3 = v4
+}
+`,
+		},
+		{
+			name: "block not found",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b2.new",
+			value:   "v2",
+			after:   "a1",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "invalid value",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1.new",
+			value:   "{",
+			after:   "a1",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetAttributeAfter(inStream, outStream, "test", tc.address, tc.value, tc.after)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAttributeSetIfValue(t *testing.T) {
+	src := `a0 = v0
+b1 {
+  a1 = "v1" # comment
+  a2 = [
+    "v2",
+  ]
+}
+b2 "l1" {
+  a1 = "v1"
+}
+`
+
+	cases := []struct {
+		name         string
+		address      string
+		value        string
+		old          string
+		skipMismatch bool
+		ok           bool
+		want         string
+	}{
+		{
+			name:    "match",
+			address: "b1.a1",
+			value:   `"v3"`,
+			old:     `"v1"`,
+			ok:      true,
+			want: `a0 = v0
+b1 {
+  a1 = "v3" # comment
+  a2 = [
+    "v2",
+  ]
+}
+b2 "l1" {
+  a1 = "v1"
+}
+`,
+		},
+		{
+			name:    "multi-line",
+			address: "b1.a2",
+			value:   `["v3"]`,
+			old: `[
+    "v2",
+  ]
+`,
+			ok: true,
+			want: `a0 = v0
+b1 {
+  a1 = "v1" # comment
+  a2 = ["v3"]
+}
+b2 "l1" {
+  a1 = "v1"
+}
+`,
+		},
+		{
+			name:    "all matches",
+			address: "*.a1",
+			value:   `"v3"`,
+			old:     `"v1"`,
+			ok:      true,
+			want: `a0 = v0
+b1 {
+  a1 = "v3" # comment
+  a2 = [
+    "v2",
+  ]
+}
+b2 "l1" {
+  a1 = "v3"
+}
+`,
+		},
+		{
+			name:    "mismatch",
+			address: "a0",
+			value:   "v3",
+			old:     "v1",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:         "mismatch with skip",
+			address:      "a0",
+			value:        "v3",
+			old:          "v1",
+			skipMismatch: true,
+			ok:           true,
+			want:         src,
+		},
+		{
+			name:    "not found",
+			address: "a3",
+			value:   "v3",
+			old:     "v0",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:         "not found with skip",
+			address:      "a3",
+			value:        "v3",
+			old:          "v0",
+			skipMismatch: true,
+			ok:           true,
+			want:         src,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := SetAttributeIfValue(inStream, outStream, "test", tc.address, tc.value, tc.old, tc.skipMismatch)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
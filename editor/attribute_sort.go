@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SortAttributes reads HCL from io.Reader, and sorts attributes in matched
+// blocks at a given address alphabetically by name, and writes the updated
+// HCL to io.Writer.
+// The sorted attributes are placed in the positions originally occupied by
+// attributes in each block, so nested blocks and blank lines are not moved.
+// Comments immediately above each attribute and a trailing comment on the
+// same line move with it.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SortAttributes(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&attributeSort{address: address},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// attributeSort is a filter implementation for sorting attributes.
+type attributeSort struct {
+	address string
+}
+
+// attributeSpan is a range [start, end) of tokens of an attribute in the file
+// tokens.
+type attributeSpan struct {
+	start int
+	end   int
+	name  string
+}
+
+// Filter reads HCL and sorts attributes in matched blocks.
+func (f *attributeSort) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := inFile.BuildTokens(nil)
+
+	// spans is a list of original spans, and sorted is a list of spans to be
+	// placed there in the same order.
+	spans := []attributeSpan{}
+	sorted := []attributeSpan{}
+	seen := make(map[*hclwrite.Block]bool)
+	for _, b := range blocks {
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+
+		body := b.Body()
+		blockSpans := []attributeSpan{}
+		for _, name := range attributeNames(body) {
+			start, end := attributeWithLeadingComments(tokens, body.GetAttribute(name))
+			if start < 0 {
+				continue
+			}
+			blockSpans = append(blockSpans, attributeSpan{start: start, end: end, name: name})
+		}
+
+		blockSorted := make([]attributeSpan, len(blockSpans))
+		copy(blockSorted, blockSpans)
+		sort.SliceStable(blockSorted, func(i, j int) bool {
+			return blockSorted[i].name < blockSorted[j].name
+		})
+
+		spans = append(spans, blockSpans...)
+		sorted = append(sorted, blockSorted...)
+	}
+
+	// Rebuild tokens by filling original positions with sorted attributes.
+	// Spans of different blocks never overlap, but they may be out of order
+	// when matched blocks are nested, so sort them by position.
+	order := make([]int, len(spans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return spans[order[i]].start < spans[order[j]].start
+	})
+
+	var out hclwrite.Tokens
+	pos := 0
+	for _, i := range order {
+		out = append(out, tokens[pos:spans[i].start]...)
+		out = append(out, withTrailingNewline(copyTokens(tokens[sorted[i].start:sorted[i].end]))...)
+		pos = spans[i].end
+	}
+	out = append(out, tokens[pos:]...)
+
+	return safeParseConfig(out.Bytes(), "generated_by_attributeSort", hcl.Pos{Line: 1, Column: 1})
+}
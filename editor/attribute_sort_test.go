@@ -0,0 +1,185 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAttributeSort(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `
+b1 "l1" {
+  c = 3
+  a = 1
+  b = 2
+}
+`,
+			address: "b1.l1",
+			ok:      true,
+			want: `
+b1 "l1" {
+  a = 1
+  b = 2
+  c = 3
+}
+`,
+		},
+		{
+			name: "comments move with attributes",
+			src: `
+b1 "l1" {
+  # comment for c
+  c = 3
+  /* comment for b */
+  b = 2 # trailing comment for b
+  a = 1
+}
+`,
+			address: "b1.l1",
+			ok:      true,
+			want: `
+b1 "l1" {
+  a = 1
+  /* comment for b */
+  b = 2 # trailing comment for b
+  # comment for c
+  c = 3
+}
+`,
+		},
+		{
+			name: "nested blocks and blank lines are not moved",
+			src: `
+b1 "l1" {
+  b = 2
+
+  b2 {
+    z = 1
+    y = 2
+  }
+  a = 1
+}
+`,
+			address: "b1.l1",
+			ok:      true,
+			want: `
+b1 "l1" {
+  a = 1
+
+  b2 {
+    z = 1
+    y = 2
+  }
+  b = 2
+}
+`,
+		},
+		{
+			name: "multiple blocks",
+			src: `
+b1 "l1" {
+  b = 2
+  a = 1
+}
+
+b1 "l2" {
+  d = 4
+  c = 3
+}
+
+b2 {
+  f = 6
+  e = 5
+}
+`,
+			address: "b1",
+			ok:      true,
+			want: `
+b1 "l1" {
+  a = 1
+  b = 2
+}
+
+b1 "l2" {
+  c = 3
+  d = 4
+}
+
+b2 {
+  f = 6
+  e = 5
+}
+`,
+		},
+		{
+			name: "recursive",
+			src: `
+b1 {
+  b = 2
+  a = 1
+  b2 {
+    d = 4
+    c = 3
+  }
+}
+`,
+			address: "**",
+			ok:      true,
+			want: `
+b1 {
+  a = 1
+  b = 2
+  b2 {
+    c = 3
+    d = 4
+  }
+}
+`,
+		},
+		{
+			name: "not found",
+			src: `
+b1 {
+  b = 2
+  a = 1
+}
+`,
+			address: "b2",
+			ok:      true,
+			want: `
+b1 {
+  b = 2
+  a = 1
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SortAttributes(inStream, outStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// AppendBlock reads HCL from io.Reader, and appends a new block to matched
+// blocks at a given parent address, and writes the updated HCL to io.Writer.
+// If parent is empty, the new block is appended to the top level body.
+// If newline is true, a new line is inserted before the new block.
+// If comment is not empty, it is attached to the new block as a leading
+// comment. Each line of the comment becomes a line starting with "#".
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AppendBlock(r io.Reader, w io.Writer, filename string, parent string, child string, newline bool, comment string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockAppend{parent: parent, child: child, newline: newline, comment: comment},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// AppendBlockWithBody is the same as AppendBlock, but fills the body of the
+// new block with a given HCL snippet such as attributes and nested blocks.
+// The snippet is parsed and grafted as it is, so that comments and formatting
+// are kept. It returns an error if the snippet cannot be parsed.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AppendBlockWithBody(r io.Reader, w io.Writer, filename string, parent string, child string, body string, newline bool, comment string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockAppend{parent: parent, child: child, newline: newline, comment: comment, body: body},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// AppendBlockAt is the same as AppendBlockWithBody, but inserts the new block
+// next to an anchor block instead of at the end of the parent body.
+// The anchor is an address of a block relative to the parent, such as
+// resource.aws_vpc.main. If after is true, the new block is inserted after the
+// last matched anchor, otherwise before the first matched anchor including its
+// leading comments. If body is empty, the new block has an empty body.
+// It returns an error if the anchor is not found in a matched parent.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func AppendBlockAt(r io.Reader, w io.Writer, filename string, parent string, child string, body string, anchor string, after bool, newline bool, comment string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockAppend{parent: parent, child: child, newline: newline, comment: comment, body: body, anchor: anchor, after: after},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockAppend is a filter implementation for appending a new block.
+type blockAppend struct {
+	// parent is an address of blocks to which a new block is appended.
+	// If empty, a new block is appended to the top level body.
+	parent string
+	// child is an address of a new block relative to the parent.
+	child string
+	// newline is a flag to insert a new line before the new block.
+	newline bool
+	// comment is a leading comment of the new block.
+	comment string
+	// body is an HCL snippet of the body of the new block.
+	// If empty, the new block has an empty body.
+	body string
+	// anchor is an address of a block relative to the parent next to which a
+	// new block is inserted. If empty, a new block is appended at the end.
+	anchor string
+	// after is a flag to insert a new block after the anchor instead of before.
+	after bool
+}
+
+// Filter reads HCL and appends a new block to matched blocks at a given address.
+func (f *blockAppend) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	typeName, labels, err := parseAddress(f.child)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyTokens hclwrite.Tokens
+	if len(strings.TrimSpace(f.body)) != 0 {
+		snippet, err := safeParseConfig([]byte(f.body), "generated_by_blockAppend", hcl.Pos{Line: 1, Column: 1})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse body of new block: %s", err)
+		}
+		bodyTokens = withTrailingNewline(trimEOF(snippet.BuildTokens(nil)))
+	}
+
+	var bodies []*hclwrite.Body
+	if len(f.parent) == 0 {
+		bodies = append(bodies, inFile.Body())
+	} else {
+		matched, err := findLongestMatchingBlocks(inFile.Body(), f.parent)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range matched {
+			bodies = append(bodies, b.Body())
+		}
+	}
+
+	if len(f.anchor) != 0 {
+		return f.insert(inFile, bodies, typeName, labels, bodyTokens)
+	}
+
+	for _, body := range bodies {
+		if f.newline {
+			body.AppendNewline()
+		}
+		if len(f.comment) != 0 {
+			body.AppendUnstructuredTokens(commentTokens(f.comment))
+		}
+		block := body.AppendNewBlock(typeName, labels)
+		if len(bodyTokens) != 0 {
+			block.Body().AppendUnstructuredTokens(copyTokens(bodyTokens))
+		}
+	}
+
+	if len(bodyTokens) != 0 {
+		// parse the result again to make appended tokens structured items.
+		return safeParseConfig(inFile.BuildTokens(nil).Bytes(), "generated_by_blockAppend", hcl.Pos{Line: 1, Column: 1})
+	}
+
+	return inFile, nil
+}
+
+// insert inserts a new block next to the anchor in each of given bodies.
+// The hclwrite doesn't provide a way to insert a block at an arbitrary
+// position, so we splice tokens of the new block next to the anchor, and
+// parse the result again.
+func (f *blockAppend) insert(inFile *hclwrite.File, bodies []*hclwrite.Body, typeName string, labels []string, bodyTokens hclwrite.Tokens) (*hclwrite.File, error) {
+	tokens := inFile.BuildTokens(nil)
+	for _, body := range bodies {
+		anchors, err := findBlocksByAddress(body, f.anchor)
+		if err != nil {
+			return nil, err
+		}
+		if len(anchors) == 0 {
+			return nil, fmt.Errorf("failed to append block. anchor block not found: %s", f.anchor)
+		}
+
+		newBlock := hclwrite.NewEmptyFile()
+		if f.newline {
+			newBlock.Body().AppendNewline()
+		}
+		if len(f.comment) != 0 {
+			newBlock.Body().AppendUnstructuredTokens(commentTokens(f.comment))
+		}
+		block := newBlock.Body().AppendNewBlock(typeName, labels)
+		if len(bodyTokens) != 0 {
+			block.Body().AppendUnstructuredTokens(copyTokens(bodyTokens))
+		}
+		newTokens := trimEOF(newBlock.BuildTokens(nil))
+
+		var anchorTokens hclwrite.Tokens
+		if f.after {
+			anchorTokens = anchors[len(anchors)-1].BuildTokens(nil)
+		} else {
+			anchorTokens = anchors[0].BuildTokens(nil)
+		}
+		start, end := findTokens(tokens, anchorTokens)
+		if start < 0 {
+			return nil, fmt.Errorf("failed to find tokens of anchor block: %s", f.anchor)
+		}
+
+		var inserted hclwrite.Tokens
+		inserted = append(inserted, tokens[:start]...)
+		if f.after {
+			inserted = append(inserted, withTrailingNewline(append(hclwrite.Tokens{}, tokens[start:end]...))...)
+			inserted = append(inserted, newTokens...)
+		} else {
+			inserted = append(inserted, newTokens...)
+			inserted = append(inserted, tokens[start:end]...)
+		}
+		inserted = append(inserted, tokens[end:]...)
+		tokens = inserted
+	}
+
+	return safeParseConfig(tokens.Bytes(), "generated_by_blockAppend", hcl.Pos{Line: 1, Column: 1})
+}
+
+// commentTokens returns tokens of single-line comments for a given text.
+// Each line of the text becomes a comment line starting with "#".
+func commentTokens(text string) hclwrite.Tokens {
+	var tokens hclwrite.Tokens
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		comment := "#"
+		if len(line) != 0 {
+			comment += " " + line
+		}
+		tokens = append(tokens, &hclwrite.Token{
+			Type:  hclsyntax.TokenComment,
+			Bytes: []byte(comment + "\n"),
+		})
+	}
+
+	return tokens
+}
@@ -0,0 +1,410 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockAppend(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		parent  string
+		child   string
+		newline bool
+		comment string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+`,
+			parent:  "b1.l1",
+			child:   "b2.l2",
+			newline: false,
+			ok:      true,
+			want: `
+b1 "l1" {
+  a1 = v1
+  b2 "l2" {
+  }
+}
+`,
+		},
+		{
+			name: "with newline",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+`,
+			parent:  "b1.l1",
+			child:   "b2",
+			newline: true,
+			ok:      true,
+			want: `
+b1 "l1" {
+  a1 = v1
+
+  b2 {
+  }
+}
+`,
+		},
+		{
+			name: "top level with comment",
+			src: `a0 = v0
+`,
+			parent:  "",
+			child:   "b1.l1",
+			newline: true,
+			comment: "generated by hcledit",
+			ok:      true,
+			want: `a0 = v0
+
+# generated by hcledit
+b1 "l1" {
+}
+`,
+		},
+		{
+			name: "multi-line comment",
+			src: `
+b1 {
+}
+`,
+			parent:  "b1",
+			child:   "b2",
+			comment: "line1\n\nline2\n",
+			ok:      true,
+			want: `
+b1 {
+  # line1
+  #
+  # line2
+  b2 {
+  }
+}
+`,
+		},
+		{
+			name: "parent not found",
+			src: `
+b1 {
+}
+`,
+			parent: "b2",
+			child:  "b3",
+			ok:     true,
+			want: `
+b1 {
+}
+`,
+		},
+		{
+			name: "empty child",
+			src: `
+b1 {
+}
+`,
+			parent: "b1",
+			child:  "",
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AppendBlock(inStream, outStream, "test", tc.parent, tc.child, tc.newline, tc.comment)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockAppendWithBody(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		parent string
+		child  string
+		body   string
+		ok     bool
+		want   string
+	}{
+		{
+			name: "attributes and nested blocks with comments",
+			src: `
+resource "aws_security_group" "hoge" {
+  name = "hoge"
+}
+`,
+			parent: "resource.aws_security_group.hoge",
+			child:  "ingress",
+			body: `# allow https
+from_port = 443
+to_port   = 443
+cidr_blocks = ["0.0.0.0/0"]
+timeouts {
+  create = "5m"
+}`,
+			ok: true,
+			want: `
+resource "aws_security_group" "hoge" {
+  name = "hoge"
+  ingress {
+    # allow https
+    from_port   = 443
+    to_port     = 443
+    cidr_blocks = ["0.0.0.0/0"]
+    timeouts {
+      create = "5m"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "multiple parents",
+			src: `
+b1 "l1" {
+}
+b1 "l2" {
+}
+`,
+			parent: "b1.*",
+			child:  "b2",
+			body:   "a1 = v1\n",
+			ok:     true,
+			want: `
+b1 "l1" {
+  b2 {
+    a1 = v1
+  }
+}
+b1 "l2" {
+  b2 {
+    a1 = v1
+  }
+}
+`,
+		},
+		{
+			name: "empty body",
+			src: `
+b1 {
+}
+`,
+			parent: "b1",
+			child:  "b2",
+			body:   "",
+			ok:     true,
+			want: `
+b1 {
+  b2 {
+  }
+}
+`,
+		},
+		{
+			name: "invalid body",
+			src: `
+b1 {
+}
+`,
+			parent: "b1",
+			child:  "b2",
+			body:   "a1 = ",
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AppendBlockWithBody(inStream, outStream, "test", tc.parent, tc.child, tc.body, false, "")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockAppendAt(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		parent  string
+		child   string
+		body    string
+		anchor  string
+		after   bool
+		newline bool
+		comment string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "after",
+			src: `resource "aws_vpc" "main" {
+}
+
+resource "aws_instance" "web" {
+}
+`,
+			parent:  "",
+			child:   "resource.aws_subnet.main",
+			body:    "vpc_id = aws_vpc.main.id\n",
+			anchor:  "resource.aws_vpc.main",
+			after:   true,
+			newline: true,
+			ok:      true,
+			want: `resource "aws_vpc" "main" {
+}
+
+resource "aws_subnet" "main" {
+  vpc_id = aws_vpc.main.id
+}
+
+resource "aws_instance" "web" {
+}
+`,
+		},
+		{
+			name: "before with leading comments",
+			src: `a0 = v0
+
+# comment
+b1 "l1" {
+}
+`,
+			parent:  "",
+			child:   "b2",
+			anchor:  "b1.l1",
+			after:   false,
+			comment: "generated",
+			ok:      true,
+			want: `a0 = v0
+
+# generated
+b2 {
+}
+# comment
+b1 "l1" {
+}
+`,
+		},
+		{
+			name: "after the last block without a trailing newline",
+			src: `b1 {
+}`,
+			parent: "",
+			child:  "b2",
+			anchor: "b1",
+			after:  true,
+			ok:     true,
+			want: `b1 {
+}
+b2 {
+}
+`,
+		},
+		{
+			name: "multiple parents and wildcard anchor",
+			src: `
+b1 "l1" {
+  b2 "x" {
+  }
+  b2 "y" {
+  }
+}
+b1 "l2" {
+  b2 "z" {
+  }
+}
+`,
+			parent: "b1.*",
+			child:  "b3",
+			anchor: "b2.*",
+			after:  true,
+			ok:     true,
+			want: `
+b1 "l1" {
+  b2 "x" {
+  }
+  b2 "y" {
+  }
+  b3 {
+  }
+}
+b1 "l2" {
+  b2 "z" {
+  }
+  b3 {
+  }
+}
+`,
+		},
+		{
+			name: "anchor not found",
+			src: `
+b1 {
+}
+`,
+			parent: "",
+			child:  "b2",
+			anchor: "b3",
+			after:  true,
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := AppendBlockAt(inStream, outStream, "test", tc.parent, tc.child, tc.body, tc.anchor, tc.after, tc.newline, tc.comment)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package editor
+
+import (
+	"io"
+)
+
+// CountBlocks reads HCL from io.Reader, and returns the number of blocks
+// which match a given address.
+// The address is resolved in the same way as HasBlock, so that a wildcard, a
+// regular expression, an index suffix and a recursive descent can be used.
+// It writes nothing.
+// Note that a filename is used only for an error message.
+func CountBlocks(r io.Reader, filename string, address string) (int, error) {
+	inFile, err := parseInput(r, filename)
+	if err != nil {
+		return 0, err
+	}
+
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), address)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(blocks), nil
+}
@@ -0,0 +1,86 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCountBlocks(t *testing.T) {
+	src := `
+terraform {
+  backend "s3" {
+  }
+}
+
+resource "aws_instance" "web" {
+}
+
+resource "aws_instance" "db" {
+}
+
+resource "aws_s3_bucket" "logs" {
+}
+`
+
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    int
+	}{
+		{
+			name:    "single",
+			src:     src,
+			address: "terraform.backend",
+			ok:      true,
+			want:    1,
+		},
+		{
+			name:    "wildcard",
+			src:     src,
+			address: "resource.aws_instance.*",
+			ok:      true,
+			want:    2,
+		},
+		{
+			name:    "type only",
+			src:     src,
+			address: "resource",
+			ok:      true,
+			want:    3,
+		},
+		{
+			name:    "not found",
+			src:     src,
+			address: "module.foo",
+			ok:      true,
+			want:    0,
+		},
+		{
+			name:    "parse error",
+			src:     `b1 {`,
+			address: "b1",
+			ok:      false,
+			want:    0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			got, err := CountBlocks(inStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %d", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %d, want: %d", got, tc.want)
+			}
+		})
+	}
+}
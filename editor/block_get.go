@@ -1,17 +1,18 @@
 package editor
 
 import (
-	"fmt"
 	"io"
-	"strings"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 // GetBlock reads HCL from io.Reader, and writes matched blocks to io.Writer.
+// The default sink can be replaced with WithSink such as NewTemplateSink,
+// which is given matched blocks at the top level.
 // Note that a filename is used only for an error message.
 // If an error occurs, Nothing is written to the output stream.
-func GetBlock(r io.Reader, w io.Writer, filename string, address string) error {
+func GetBlock(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
 	e := &Editor{
 		source: &parser{filename: filename},
 		filters: []Filter{
@@ -19,10 +20,114 @@ func GetBlock(r io.Reader, w io.Writer, filename string, address string) error {
 		},
 		sink: &formater{},
 	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
 
+// GetBlockAddresses is the same as GetBlock, but writes addresses of matched
+// blocks instead of their contents. This is useful for checking what a given
+// address resolves to before running destructive operations.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetBlockAddresses(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockFilter{address: address},
+		},
+		// The filter leaves only matched blocks at the top level,
+		// so we can reuse the blockList sink to print their addresses.
+		sink: &blockList{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// GetBlockJSON is the same as GetBlock, but writes matched blocks as a JSON
+// array of objects with their addresses, formatted contents and source ranges.
+// Note that a filename is used only for an error message and source ranges.
+// If an error occurs, Nothing is written to the output stream.
+func GetBlockJSON(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	ranges := &sourceRanges{filename: filename}
+	e := &Editor{
+		source: &rangeParser{parser: parser{filename: filename}, ranges: ranges},
+		filters: []Filter{
+			&blockFilter{address: address},
+		},
+		sink: &blockJSON{ranges: ranges, withValue: true},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// GetBlockAll is the same as GetBlock, but writes all blocks matched by
+// findLongestMatchingBlocks, including nested blocks, instead of top level
+// blocks only. A wildcard, a regular expression, an index suffix and a
+// recursive descent can be used in the address as well as attribute get.
+// Matched blocks are written in the order they appear in the source and
+// separated by blank lines. Each block is written only once even if it is
+// matched multiple times.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetBlockAll(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockGetAll{address: address},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockGetAll is a filter implementation to get all matching blocks.
+type blockGetAll struct {
+	address string
+}
+
+// Filter reads HCL and writes all matched blocks at a given address.
+func (f *blockGetAll) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matched, err := findLongestMatchingBlocks(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	// sort blocks in the source order and remove duplicates.
+	order := make(map[*hclwrite.Token]int)
+	for i, t := range inFile.BuildTokens(nil) {
+		order[t] = i
+	}
+	position := func(b *hclwrite.Block) int {
+		return order[b.BuildTokens(nil)[0]]
+	}
+	seen := make(map[*hclwrite.Block]bool)
+	blocks := []*hclwrite.Block{}
+	for _, b := range matched {
+		if !seen[b] {
+			seen[b] = true
+			blocks = append(blocks, b)
+		}
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return position(blocks[i]) < position(blocks[j])
+	})
+
+	outFile := hclwrite.NewEmptyFile()
+	for i, b := range blocks {
+		if i != 0 {
+			outFile.Body().AppendNewline()
+		}
+		outFile.Body().AppendBlock(b)
+	}
+
+	return outFile, nil
+}
+
 // blockFilter is a filter implementation for block.
 type blockFilter struct {
 	address string
@@ -30,13 +135,11 @@ type blockFilter struct {
 
 // Filter reads HCL and writes only matched blocks at a given address.
 func (f *blockFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
-	typeName, labels, err := parseAddress(f.address)
+	matched, err := findBlocksByAddress(inFile.Body(), f.address)
 	if err != nil {
 		return nil, err
 	}
 
-	matched := findBlocks(inFile.Body(), typeName, labels)
-
 	outFile := hclwrite.NewEmptyFile()
 	for i, b := range matched {
 		if i != 0 {
@@ -51,19 +154,56 @@ func (f *blockFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
 }
 
 func parseAddress(address string) (string, []string, error) {
-	if len(address) == 0 {
-		return "", []string{}, fmt.Errorf("failed to parse address: %s", address)
+	a, err := splitAddress(address)
+	if err != nil {
+		return "", []string{}, err
 	}
 
-	a := strings.Split(address, ".")
-	typeName := a[0]
+	typeName := unquoteSegment(a[0])
 	labels := []string{}
-	if len(a) > 1 {
-		labels = a[1:]
+	for _, s := range a[1:] {
+		labels = append(labels, unquoteSegment(s))
 	}
 	return typeName, labels, nil
 }
 
+// findBlocksByAddress returns matching blocks from the body at a given
+// address, whose first segment is a block type and the rest is labels.
+// Unlike findBlocks, each segment is matched by matchSegment, so that a
+// regular expression can be used as well as a wildcard (*), but numbers of
+// label must be equal.
+func findBlocksByAddress(b *hclwrite.Body, address string) ([]*hclwrite.Block, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*hclwrite.Block
+	for _, block := range b.Blocks() {
+		names := append([]string{block.Type()}, block.Labels()...)
+		if len(a) != len(names) {
+			continue
+		}
+
+		ok := true
+		for i := range a {
+			match, err := matchSegment(a[i], names[i])
+			if err != nil {
+				return nil, err
+			}
+			if !match && !(i != 0 && names[i] == wildcardSegment) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matched = append(matched, block)
+		}
+	}
+
+	return matched, nil
+}
+
 // findBlocks returns matching blocks from the body that have the given name
 // and labels or returns an empty list if there is currently no matching block.
 // The labels can be wildcard (*), but numbers of label must be equal.
@@ -7,11 +7,12 @@ import (
 
 func TestBlockGet(t *testing.T) {
 	cases := []struct {
-		name    string
-		src     string
-		address string
-		ok      bool
-		want    string
+		name          string
+		src           string
+		address       string
+		addressesOnly bool
+		ok            bool
+		want          string
 	}{
 		{
 			name: "simple",
@@ -169,13 +170,232 @@ b1 {
 }
 `,
 		},
+		{
+			name: "quoted label containing dots",
+			src: `
+b1 "l1.l2" {
+}
+
+b1 "l1" "l2" {
+}
+`,
+			address: `b1."l1.l2"`,
+			ok:      true,
+			want: `b1 "l1.l2" {
+}
+`,
+		},
+		{
+			name: "addresses only",
+			src: `
+b1 {
+}
+
+b1 l1 {
+}
+
+b1 l2 {
+  a1 = v1
+}
+`,
+			address:       "b1.*",
+			addressesOnly: true,
+			ok:            true,
+			want: `b1.l1
+b1.l2
+`,
+		},
+		{
+			name: "regexp",
+			src: `
+b1 "Web" {
+}
+b1 "db" {
+}
+`,
+			address: "b1./(?i)web|DB/",
+			ok:      true,
+			want: `b1 "Web" {
+}
+
+b1 "db" {
+}
+`,
+		},
+		{
+			name: "quoted label is literal",
+			src: `
+b1 "/l1/" {
+}
+`,
+			address: `b1."/l1/"`,
+			ok:      true,
+			want: `b1 "/l1/" {
+}
+`,
+		},
+		{
+			name: "invalid regexp",
+			src: `
+b1 "l1" {
+}
+`,
+			address: "b1./(/",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "addresses only no match",
+			src: `
+b1 {
+}
+`,
+			address:       "b2",
+			addressesOnly: true,
+			ok:            true,
+			want:          "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			var err error
+			if tc.addressesOnly {
+				err = GetBlockAddresses(inStream, outStream, "test", tc.address)
+			} else {
+				err = GetBlock(inStream, outStream, "test", tc.address)
+			}
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockGetAll(t *testing.T) {
+	src := `
+resource "aws_security_group" "hoge" {
+  ingress {
+    from_port = 80
+  }
+  ingress {
+    from_port = 443
+  }
+}
+
+resource "aws_security_group" "fuga" {
+  ingress {
+    from_port = 22
+  }
+}
+`
+
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "nested blocks with wildcard",
+			src:     src,
+			address: "resource.aws_security_group.*.ingress",
+			ok:      true,
+			want: `ingress {
+  from_port = 80
+}
+
+ingress {
+  from_port = 443
+}
+
+ingress {
+  from_port = 22
+}
+`,
+		},
+		{
+			name:    "index",
+			src:     src,
+			address: "resource.aws_security_group.hoge.ingress[1]",
+			ok:      true,
+			want: `ingress {
+  from_port = 443
+}
+`,
+		},
+		{
+			name:    "recursive descent in source order",
+			src:     src,
+			address: "**.ingress",
+			ok:      true,
+			want: `ingress {
+  from_port = 80
+}
+
+ingress {
+  from_port = 443
+}
+
+ingress {
+  from_port = 22
+}
+`,
+		},
+		{
+			name:    "top level blocks",
+			src:     src,
+			address: "resource.aws_security_group./.*/",
+			ok:      true,
+			want: `resource "aws_security_group" "hoge" {
+  ingress {
+    from_port = 80
+  }
+  ingress {
+    from_port = 443
+  }
+}
+
+resource "aws_security_group" "fuga" {
+  ingress {
+    from_port = 22
+  }
+}
+`,
+		},
+		{
+			name:    "no match",
+			src:     src,
+			address: "hoge",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "empty",
+			src:     src,
+			address: "",
+			ok:      false,
+			want:    "",
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			inStream := bytes.NewBufferString(tc.src)
 			outStream := new(bytes.Buffer)
-			err := GetBlock(inStream, outStream, "test", tc.address)
+			err := GetBlockAll(inStream, outStream, "test", tc.address)
 			if tc.ok && err != nil {
 				t.Fatalf("unexpected err = %s", err)
 			}
@@ -0,0 +1,25 @@
+package editor
+
+import (
+	"io"
+)
+
+// HasBlock reads HCL from io.Reader, and returns true if at least one block
+// matches a given address, false otherwise.
+// The address is resolved by findLongestMatchingBlocks, so that a wildcard, a
+// regular expression, an index suffix and a recursive descent can be used as
+// well as block get --all. It writes nothing.
+// Note that a filename is used only for an error message.
+func HasBlock(r io.Reader, filename string, address string) (bool, error) {
+	inFile, err := parseInput(r, filename)
+	if err != nil {
+		return false, err
+	}
+
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), address)
+	if err != nil {
+		return false, err
+	}
+
+	return len(blocks) != 0, nil
+}
@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHasBlock(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    bool
+	}{
+		{
+			name: "top level block",
+			src: `
+b1 "l1" {
+  a1 = v1
+}
+`,
+			address: "b1.l1",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "nested block",
+			src: `
+b1 "l1" {
+  b2 {
+    a2 = v2
+  }
+}
+`,
+			address: "b1.l1.b2",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "wildcard",
+			src: `
+b1 "l1" {
+}
+`,
+			address: "b1.*",
+			ok:      true,
+			want:    true,
+		},
+		{
+			name: "not found",
+			src: `
+b1 "l1" {
+}
+`,
+			address: "b1.l2",
+			ok:      true,
+			want:    false,
+		},
+		{
+			name: "empty address",
+			src: `
+b1 {
+}
+`,
+			address: "",
+			ok:      false,
+			want:    false,
+		},
+		{
+			name:    "parse error",
+			src:     `b1 {`,
+			address: "b1",
+			ok:      false,
+			want:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			got, err := HasBlock(inStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %t", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// GetBlockLabels reads HCL from io.Reader, and writes labels of all top level
+// blocks of a given type to io.Writer.
+// Each line corresponds to a block and labels are joined with tabs, because
+// labels may contain spaces. A block without labels is written as an empty
+// line, so that the number of lines equals to the number of blocks.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func GetBlockLabels(r io.Reader, w io.Writer, filename string, blockType string, opts ...Option) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &blockLabels{blockType: blockType},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockLabels is a Sink implementation to get labels of blocks.
+type blockLabels struct {
+	blockType string
+}
+
+// Sink reads HCL and writes labels of matched blocks.
+func (s *blockLabels) Sink(inFile *hclwrite.File) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, b := range allMatchingBlocksByType(inFile.Body(), s.blockType) {
+		buf.WriteString(strings.Join(b.Labels(), "\t") + "\n")
+	}
+
+	return buf.Bytes(), nil
+}
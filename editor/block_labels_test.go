@@ -0,0 +1,98 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockLabels(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		blockType string
+		opts      []Option
+		ok        bool
+		want      string
+	}{
+		{
+			name: "simple",
+			src: `
+resource "foo" "bar" {
+}
+
+data "foo" "bar" {
+}
+
+resource "foo" "baz" {
+  nested {
+  }
+}
+
+resource {
+}
+
+resource "foo" "with space" {
+}
+`,
+			blockType: "resource",
+			ok:        true,
+			want:      "foo\tbar\nfoo\tbaz\n\nfoo\twith space\n",
+		},
+		{
+			name: "no match",
+			src: `
+resource "foo" "bar" {
+}
+`,
+			blockType: "nested",
+			ok:        true,
+			want:      "",
+		},
+		{
+			name: "with filters",
+			src: `
+resource "foo" "bar" {
+  a = 1
+}
+
+resource "foo" "baz" {
+  a = 2
+}
+`,
+			blockType: "resource",
+			opts:      []Option{WithFilters(mustBlockWhereFilter(t, "a == 2"))},
+			ok:        true,
+			want:      "foo\tbaz\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := GetBlockLabels(inStream, outStream, "test", tc.blockType, tc.opts...)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// mustBlockWhereFilter returns a filter by NewBlockWhereFilter for testing.
+func mustBlockWhereFilter(t *testing.T, predicate string) Filter {
+	t.Helper()
+	filter, err := NewBlockWhereFilter(predicate)
+	if err != nil {
+		t.Fatalf("unexpected err = %s", err)
+	}
+	return filter
+}
@@ -1,6 +1,7 @@
 package editor
 
 import (
+	"fmt"
 	"io"
 	"strings"
 
@@ -8,27 +9,123 @@ import (
 )
 
 // ListBlock reads HCL from io.Reader, and writes a list of block addresses to io.Writer.
+// Blocks can be narrowed down by a filter such as NewBlockTypeFilter or
+// NewBlockAddressFilter given with WithFilters.
+// The default sink can be replaced with WithSink such as NewTemplateSink,
+// which is given only top level blocks.
 // Note that a filename is used only for an error message.
 // If an error occurs, Nothing is written to the output stream.
-func ListBlock(r io.Reader, w io.Writer, filename string) error {
+func ListBlock(r io.Reader, w io.Writer, filename string, opts ...Option) error {
 	e := &Editor{
-		source:  &parser{filename: filename},
+		source: &parser{filename: filename},
+		filters: []Filter{
+			// leave only blocks for a custom sink.
+			&blockTypeFilter{},
+		},
+		sink: &blockList{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// ListBlockJSON is the same as ListBlock, but writes a JSON array of objects
+// with addresses and source ranges of blocks.
+// Note that a filename is used only for an error message and source ranges.
+// If an error occurs, Nothing is written to the output stream.
+func ListBlockJSON(r io.Reader, w io.Writer, filename string, opts ...Option) error {
+	ranges := &sourceRanges{filename: filename}
+	e := &Editor{
+		source:  &rangeParser{parser: parser{filename: filename}, ranges: ranges},
+		filters: []Filter{},
+		sink:    &blockJSON{ranges: ranges},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// ListBlockWithPositions is the same as ListBlock, but writes a source
+// position of each block before its address as a TSV line of
+// file:line:column, a start byte offset, an end byte offset and an address.
+// The byte offsets are 0-based and the end is exclusive.
+// Note that a filename is used only for an error message and positions.
+// If an error occurs, Nothing is written to the output stream.
+func ListBlockWithPositions(r io.Reader, w io.Writer, filename string, opts ...Option) error {
+	ranges := &sourceRanges{filename: filename}
+	e := &Editor{
+		source:  &rangeParser{parser: parser{filename: filename}, ranges: ranges},
 		filters: []Filter{},
-		sink:    &blockList{},
+		sink:    &blockList{ranges: ranges},
 	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
 
+// NewBlockTypeFilter returns a Filter which leaves only top level blocks of a
+// given type whose leading labels match given labels, so that blocks can be
+// listed without spelling a full address. If blockType is empty, blocks of
+// any type are left. Each label may be a wildcard (*) or a regular expression
+// wrapped in slashes as well as a segment of address.
+func NewBlockTypeFilter(blockType string, labels []string) Filter {
+	return &blockTypeFilter{blockType: blockType, labels: labels}
+}
+
+// NewBlockAddressFilter returns a Filter which leaves only top level blocks
+// matched at a given address as well as block get, so that blocks can be
+// listed with a wildcard (*) or a regular expression in the address such as
+// resource.aws_instance.*.
+func NewBlockAddressFilter(address string) Filter {
+	return &blockFilter{address: address}
+}
+
+// blockTypeFilter is a filter implementation to select blocks by type and
+// labels.
+type blockTypeFilter struct {
+	blockType string
+	labels    []string
+}
+
+// Filter reads HCL and writes only blocks matched by type and labels.
+func (f *blockTypeFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	blocks := inFile.Body().Blocks()
+	if len(f.blockType) != 0 {
+		blocks = allMatchingBlocksByType(inFile.Body(), f.blockType)
+	}
+
+	outFile := hclwrite.NewEmptyFile()
+	for _, b := range blocks {
+		matched, err := longestMatchingLabels(b.Labels(), f.labels)
+		if err != nil {
+			return nil, err
+		}
+		if len(matched) == len(f.labels) {
+			outFile.Body().AppendBlock(b)
+		}
+	}
+
+	return outFile, nil
+}
+
 // blockList is a Sink implementation to get a list of block addresses.
 type blockList struct {
+	// ranges is a record of source ranges used for writing positions.
+	// If nil, only addresses are written.
+	ranges *sourceRanges
 }
 
 // Sink reads HCL and writes a list of block addresses.
 func (l *blockList) Sink(inFile *hclwrite.File) ([]byte, error) {
 	addrs := []string{}
 	for _, b := range inFile.Body().Blocks() {
-		addrs = append(addrs, toAddress(b))
+		addr := toAddress(b)
+		if l.ranges != nil {
+			if r, ok := l.ranges.rangeOf(b.BuildTokens(nil)); ok {
+				addr = fmt.Sprintf("%s:%d:%d\t%d\t%d\t%s", r.Filename, r.Start.Line, r.Start.Column, r.Start.Byte, r.End.Byte, addr)
+			}
+		}
+		addrs = append(addrs, addr)
 	}
 
 	out := strings.Join(addrs, "\n")
@@ -39,9 +136,14 @@ func (l *blockList) Sink(inFile *hclwrite.File) ([]byte, error) {
 	return []byte(out), nil
 }
 
+// toAddress returns an address of a given block.
+// Labels which cannot be written as they are, such as ones containing dots,
+// are quoted so that the address can be parsed again.
 func toAddress(b *hclwrite.Block) string {
 	addr := []string{}
 	addr = append(addr, b.Type())
-	addr = append(addr, (b.Labels())...)
+	for _, l := range b.Labels() {
+		addr = append(addr, quoteSegment(l))
+	}
 	return strings.Join(addr, ".")
 }
@@ -3,6 +3,7 @@ package editor
 import (
 	"bytes"
 	"testing"
+	"text/template"
 )
 
 func TestBlockList(t *testing.T) {
@@ -26,6 +27,16 @@ b2 l1 {
 			ok: true,
 			want: `b1
 b2.l1
+`,
+		},
+		{
+			name: "labels containing dots are quoted",
+			src: `
+module "my.module" {
+}
+`,
+			ok: true,
+			want: `module."my.module"
 `,
 		},
 		{
@@ -56,3 +67,293 @@ b2.l1
 		})
 	}
 }
+
+func TestBlockListWithBlockTypeFilter(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+}
+resource "aws_s3_bucket" "logs" {
+}
+data "aws_instance" "db" {
+}
+resource "aws_instance" "db" {
+}
+terraform {
+}
+`
+
+	cases := []struct {
+		name      string
+		blockType string
+		labels    []string
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "type",
+			blockType: "resource",
+			ok:        true,
+			want: `resource.aws_instance.web
+resource.aws_s3_bucket.logs
+resource.aws_instance.db
+`,
+		},
+		{
+			name:      "type and label",
+			blockType: "resource",
+			labels:    []string{"aws_instance"},
+			ok:        true,
+			want: `resource.aws_instance.web
+resource.aws_instance.db
+`,
+		},
+		{
+			name:   "label only",
+			labels: []string{"*", "db"},
+			ok:     true,
+			want: `data.aws_instance.db
+resource.aws_instance.db
+`,
+		},
+		{
+			name:      "regexp label",
+			blockType: "resource",
+			labels:    []string{"/aws_s3_.*/"},
+			ok:        true,
+			want: `resource.aws_s3_bucket.logs
+`,
+		},
+		{
+			name:      "too many labels",
+			blockType: "terraform",
+			labels:    []string{"*"},
+			ok:        true,
+			want:      "",
+		},
+		{
+			name:      "invalid regexp",
+			blockType: "resource",
+			labels:    []string{"/(/"},
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := ListBlock(inStream, outStream, "test", WithFilters(NewBlockTypeFilter(tc.blockType, tc.labels)))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockListWithBlockAddressFilter(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+}
+resource "aws_s3_bucket" "logs" {
+}
+data "aws_instance" "db" {
+}
+resource "aws_instance" "db" {
+  instance_type = "m5.large"
+}
+`
+
+	cases := []struct {
+		name      string
+		address   string
+		predicate string
+		ok        bool
+		want      string
+	}{
+		{
+			name:    "wildcard",
+			address: "resource.aws_instance.*",
+			ok:      true,
+			want: `resource.aws_instance.web
+resource.aws_instance.db
+`,
+		},
+		{
+			name:    "regexp",
+			address: "*./aws_.*/.db",
+			ok:      true,
+			want: `data.aws_instance.db
+resource.aws_instance.db
+`,
+		},
+		{
+			name:    "no match",
+			address: "resource.aws_instance",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:      "with where",
+			address:   "resource.aws_instance.*",
+			predicate: `instance_type == "t2.micro"`,
+			ok:        true,
+			want: `resource.aws_instance.web
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters := []Filter{NewBlockAddressFilter(tc.address)}
+			if len(tc.predicate) != 0 {
+				where, err := NewBlockWhereFilter(tc.predicate)
+				if err != nil {
+					t.Fatalf("unexpected err = %s", err)
+				}
+				filters = append(filters, where)
+			}
+
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := ListBlock(inStream, outStream, "test", WithFilters(filters...))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockListWithPositions(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			src: `a0 = v0
+# comment
+b1 {
+  a2 = v2
+}
+
+b2 l1 {
+}
+`,
+			ok: true,
+			want: "test:3:1\t18\t34\tb1\n" +
+				"test:7:1\t36\t45\tb2.l1\n",
+		},
+		{
+			name: "empty",
+			src:  "",
+			ok:   true,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ListBlockWithPositions(inStream, outStream, "test")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockListWithTemplateSink(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		format string
+		ok     bool
+		want   string
+	}{
+		{
+			name: "simple",
+			src: `a0 = v0
+b1 {
+}
+
+b2 l1 "l2" {
+}
+`,
+			format: "{{.Address}} {{len .Labels}} {{.Range.Start.Line}}:{{.Range.End.Line}}\n",
+			ok:     true,
+			want:   "b1 0 2:3\nb2.l1.l2 2 5:6\n",
+		},
+		{
+			name:   "labels",
+			src:    `b1 "l1" "l2" {}`,
+			format: `{{range .Labels}}[{{.}}]{{end}}{{"\n"}}`,
+			ok:     true,
+			want:   "[l1][l2]\n",
+		},
+		{
+			name:   "empty",
+			src:    "",
+			format: "{{.Address}}",
+			ok:     true,
+			want:   "",
+		},
+		{
+			name:   "execution error",
+			src:    "b1 {}",
+			format: "{{.Foo}}",
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			tmpl := template.Must(template.New("test").Parse(tc.format))
+			err := ListBlock(inStream, outStream, "test", WithSink(NewTemplateSink(tmpl)))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// MapBlockLabels reads HCL from io.Reader, and rewrites labels of all top
+// level blocks of a given type with a function, and writes the updated HCL
+// to io.Writer.
+// The function fn receives the current labels and returns new ones.
+// If fn returns a different number of labels from the original, it returns
+// an error unless allowArityChange is true.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func MapBlockLabels(r io.Reader, w io.Writer, filename string, blockType string, fn func(labels []string) []string, allowArityChange bool, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockMapLabels{blockType: blockType, fn: fn, allowArityChange: allowArityChange},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockMapLabels is a filter implementation for rewriting labels of blocks.
+type blockMapLabels struct {
+	blockType        string
+	fn               func(labels []string) []string
+	allowArityChange bool
+}
+
+// Filter reads HCL and rewrites labels of matched blocks.
+func (f *blockMapLabels) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matched := allMatchingBlocksByType(inFile.Body(), f.blockType)
+
+	for _, b := range matched {
+		labels := b.Labels()
+		newLabels := f.fn(labels)
+		if len(newLabels) != len(labels) && !f.allowArityChange {
+			return nil, fmt.Errorf("failed to map labels of block %s: the number of labels changed from %d to %d", toAddress(b), len(labels), len(newLabels))
+		}
+		b.SetLabels(newLabels)
+	}
+
+	return inFile, nil
+}
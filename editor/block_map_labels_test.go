@@ -0,0 +1,118 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMapBlockLabels(t *testing.T) {
+	prefix := func(labels []string) []string {
+		if len(labels) != 2 {
+			return labels
+		}
+		return []string{labels[0], "prod_" + labels[1]}
+	}
+
+	drop := func(labels []string) []string {
+		return labels[:len(labels)-1]
+	}
+
+	cases := []struct {
+		name             string
+		src              string
+		blockType        string
+		fn               func(labels []string) []string
+		allowArityChange bool
+		ok               bool
+		want             string
+	}{
+		{
+			name: "simple",
+			src: `
+resource "foo" "bar" {
+  a1 = v1
+}
+
+resource "foo" "baz" {
+}
+
+data "foo" "bar" {
+}
+`,
+			blockType: "resource",
+			fn:        prefix,
+			ok:        true,
+			want: `
+resource "foo" "prod_bar" {
+  a1 = v1
+}
+
+resource "foo" "prod_baz" {
+}
+
+data "foo" "bar" {
+}
+`,
+		},
+		{
+			name: "no match",
+			src: `
+data "foo" "bar" {
+}
+`,
+			blockType: "resource",
+			fn:        prefix,
+			ok:        true,
+			want: `
+data "foo" "bar" {
+}
+`,
+		},
+		{
+			name: "arity change is not allowed by default",
+			src: `
+resource "foo" "bar" {
+}
+`,
+			blockType: "resource",
+			fn:        drop,
+			ok:        false,
+			want:      "",
+		},
+		{
+			name: "arity change is allowed",
+			src: `
+resource "foo" "bar" {
+}
+`,
+			blockType:        "resource",
+			fn:               drop,
+			allowArityChange: true,
+			ok:               true,
+			want: `
+resource "foo" {
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := MapBlockLabels(inStream, outStream, "test", tc.blockType, tc.fn, tc.allowArityChange)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"io"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// MergeDuplicateNestedBlocks reads HCL from io.Reader, and merges blocks of a
+// given type which have the same labels in the same body, and writes the
+// updated HCL to io.Writer. It is applied to all bodies recursively.
+// Attributes and nested blocks of the duplicated blocks are merged into the
+// first occurrence and the rest are removed.
+// If attributes conflict, the earliest one is preferred and a warning is logged.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func MergeDuplicateNestedBlocks(r io.Reader, w io.Writer, filename string, blockType string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockMergeDuplicate{blockType: blockType},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockMergeDuplicate is a filter implementation for merging duplicated blocks.
+type blockMergeDuplicate struct {
+	blockType string
+}
+
+// Filter reads HCL and merges duplicated blocks.
+// Merged items are appended as tokens to preserve their comments and the file
+// is parsed again. Merging blocks may produce new duplicates in nested
+// blocks, so we repeat it until nothing is merged.
+func (f *blockMergeDuplicate) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	current := inFile
+	for mergeDuplicateBlocks(current.Body(), f.blockType) {
+		next, err := safeParseConfig(current.Bytes(), "generated_by_blockMergeDuplicate", hcl.Pos{Line: 1, Column: 1})
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// mergeDuplicateBlocks merges duplicated blocks in a given body and nested
+// blocks recursively, and returns true if anything is merged.
+func mergeDuplicateBlocks(body *hclwrite.Body, blockType string) bool {
+	merged := false
+	for _, b := range body.Blocks() {
+		if mergeDuplicateBlocks(b.Body(), blockType) {
+			merged = true
+		}
+	}
+
+	groups := make(map[string][]*hclwrite.Block)
+	order := []string{}
+	for _, b := range allMatchingBlocksByType(body, blockType) {
+		addr := toAddress(b)
+		if _, ok := groups[addr]; !ok {
+			order = append(order, addr)
+		}
+		groups[addr] = append(groups[addr], b)
+	}
+
+	for _, addr := range order {
+		blocks := groups[addr]
+		if len(blocks) < 2 {
+			continue
+		}
+
+		first := blocks[0]
+		seen := make(map[string]string)
+		for name, attr := range first.Body().Attributes() {
+			seen[name] = getExpressionAsString(attr.Expr())
+		}
+
+		for _, dup := range blocks[1:] {
+			tokens := mergeableTokens(dup, seen, addr)
+			first.Body().AppendUnstructuredTokens(tokens)
+			body.RemoveBlock(dup)
+		}
+		merged = true
+	}
+
+	return merged
+}
+
+// mergeableTokens returns tokens of attributes and nested blocks of a given
+// duplicated block in the source order. Attributes which already exist in
+// seen are skipped, and seen is updated with new attributes.
+func mergeableTokens(dup *hclwrite.Block, seen map[string]string, addr string) hclwrite.Tokens {
+	bodyTokens := dup.Body().BuildTokens(nil)
+
+	type span struct{ start, end int }
+	spans := []span{}
+
+	for _, name := range attributeNames(dup.Body()) {
+		attr := dup.Body().GetAttribute(name)
+		value := getExpressionAsString(attr.Expr())
+		if v, ok := seen[name]; ok {
+			if v != value {
+				log.Printf("[WARN] conflicting attribute %s in %s: prefer the earliest value %s, ignore %s", name, addr, v, value)
+			}
+			continue
+		}
+		seen[name] = value
+		start, end := attributeWithLeadingComments(bodyTokens, attr)
+		spans = append(spans, span{start, end})
+	}
+
+	for _, nested := range dup.Body().Blocks() {
+		start, end := itemWithLeadingComments(bodyTokens, nested.BuildTokens(nil))
+		spans = append(spans, span{start, end})
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var tokens hclwrite.Tokens
+	for _, s := range spans {
+		tokens = append(tokens, withTrailingNewline(bodyTokens[s.start:s.end])...)
+	}
+
+	return tokens
+}
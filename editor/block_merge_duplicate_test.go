@@ -0,0 +1,147 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMergeDuplicateNestedBlocks(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		blockType string
+		ok        bool
+		want      string
+	}{
+		{
+			name: "simple",
+			src: `
+resource "foo" "bar" {
+  lifecycle {
+    create_before_destroy = true
+  }
+  a1 = v1
+  lifecycle {
+    # comment
+    ignore_changes = [tags]
+  }
+}
+`,
+			blockType: "lifecycle",
+			ok:        true,
+			want: `
+resource "foo" "bar" {
+  lifecycle {
+    create_before_destroy = true
+    # comment
+    ignore_changes = [tags]
+  }
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "conflicting attributes prefer the earliest",
+			src: `
+b1 {
+  b2 {
+    a1 = v1
+  }
+  b2 {
+    a1 = v2
+    a2 = v2
+  }
+  b2 {
+    a2 = v3
+    a3 = v3
+  }
+}
+`,
+			blockType: "b2",
+			ok:        true,
+			want: `
+b1 {
+  b2 {
+    a1 = v1
+    a2 = v2
+    a3 = v3
+  }
+}
+`,
+		},
+		{
+			name: "different labels are not merged",
+			src: `
+b1 {
+  b2 "l1" {
+    a1 = v1
+  }
+  b2 "l2" {
+    a1 = v2
+  }
+}
+`,
+			blockType: "b2",
+			ok:        true,
+			want: `
+b1 {
+  b2 "l1" {
+    a1 = v1
+  }
+  b2 "l2" {
+    a1 = v2
+  }
+}
+`,
+		},
+		{
+			name: "nested duplicates produced by merging",
+			src: `
+b1 {
+  b2 {
+    b2 {
+      a1 = v1
+    }
+  }
+  b2 {
+    b2 {
+      a2 = v2
+    }
+  }
+}
+`,
+			blockType: "b2",
+			ok:        true,
+			want: `
+b1 {
+  b2 {
+    b2 {
+      a1 = v1
+      a2 = v2
+    }
+  }
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := MergeDuplicateNestedBlocks(inStream, outStream, "test", tc.blockType)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
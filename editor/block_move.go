@@ -0,0 +1,105 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// MoveBlock reads HCL from io.Reader, and relocates matched blocks at a given
+// from address into the body of a block at a given to address, and writes the
+// updated HCL to io.Writer.
+// The blocks are detached with all tokens including leading comments, and
+// appended at the end of the destination body in the source order.
+// Unlike RenameBlock, the type and labels of the blocks are not changed.
+// If no block matches the from address, the input is written as it is.
+// It returns an error if the destination block is not found or ambiguous, or
+// it is one of the moved blocks or nested in them.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func MoveBlock(r io.Reader, w io.Writer, filename string, from string, to string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockMove{from: from, to: to},
+		},
+		sink: &verticalFormater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockMove is a filter implementation for relocating block.
+type blockMove struct {
+	from string
+	to   string
+}
+
+// Filter reads HCL and relocates matched blocks into a destination block.
+func (f *blockMove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	srcs, err := findLongestMatchingBlocks(inFile.Body(), f.from)
+	if err != nil {
+		return nil, err
+	}
+	if len(srcs) == 0 {
+		return inFile, nil
+	}
+
+	dsts, err := findLongestMatchingBlocks(inFile.Body(), f.to)
+	if err != nil {
+		return nil, err
+	}
+	switch len(dsts) {
+	case 0:
+		return nil, fmt.Errorf("failed to move block. destination block not found: %s", f.to)
+	case 1:
+	default:
+		return nil, fmt.Errorf("failed to move block. destination block is ambiguous: %s", f.to)
+	}
+	dst := dsts[0]
+
+	parents := parentBodies(inFile.Body())
+	for _, src := range srcs {
+		if src == dst || containsBlock(src.Body(), dst) {
+			return nil, fmt.Errorf("failed to move block. destination block is in the moved block: %s", f.to)
+		}
+	}
+
+	for _, src := range srcs {
+		tokens := withTrailingNewline(copyTokens(src.BuildTokens(nil)))
+		parents[src].RemoveBlock(src)
+		dst.Body().AppendUnstructuredTokens(tokens)
+	}
+
+	// parse the result again to make appended tokens a structured block.
+	return safeParseConfig(inFile.BuildTokens(nil).Bytes(), "generated_by_blockMove", hcl.Pos{Line: 1, Column: 1})
+}
+
+// parentBodies returns a map of all blocks in a given body and nested blocks
+// to their parent bodies.
+func parentBodies(body *hclwrite.Body) map[*hclwrite.Block]*hclwrite.Body {
+	parents := make(map[*hclwrite.Block]*hclwrite.Body)
+	for _, b := range body.Blocks() {
+		parents[b] = body
+		for nested, parent := range parentBodies(b.Body()) {
+			parents[nested] = parent
+		}
+	}
+
+	return parents
+}
+
+// containsBlock returns true if a given block is nested in a given body at
+// any depth.
+func containsBlock(body *hclwrite.Body, block *hclwrite.Block) bool {
+	for _, b := range body.Blocks() {
+		if b == block || containsBlock(b.Body(), block) {
+			return true
+		}
+	}
+
+	return false
+}
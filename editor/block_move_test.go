@@ -0,0 +1,130 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockMove(t *testing.T) {
+	src := `resource "aws_security_group" "hoge" {
+  name = "hoge"
+  # allow http
+  ingress {
+    from_port = 80
+  }
+  egress {
+    from_port = 0
+  }
+}
+
+resource "aws_security_group" "fuga" {
+  name = "fuga"
+}
+`
+
+	cases := []struct {
+		name string
+		src  string
+		from string
+		to   string
+		ok   bool
+		want string
+	}{
+		{
+			name: "nested block with comments",
+			src:  src,
+			from: "resource.aws_security_group.hoge.ingress",
+			to:   "resource.aws_security_group.fuga",
+			ok:   true,
+			want: `resource "aws_security_group" "hoge" {
+  name = "hoge"
+  egress {
+    from_port = 0
+  }
+}
+
+resource "aws_security_group" "fuga" {
+  name = "fuga"
+  # allow http
+  ingress {
+    from_port = 80
+  }
+}
+`,
+		},
+		{
+			name: "multiple blocks",
+			src:  src,
+			from: "resource.aws_security_group.hoge.*",
+			to:   "resource.aws_security_group.fuga",
+			ok:   true,
+			want: `resource "aws_security_group" "hoge" {
+  name = "hoge"
+}
+
+resource "aws_security_group" "fuga" {
+  name = "fuga"
+  # allow http
+  ingress {
+    from_port = 80
+  }
+  egress {
+    from_port = 0
+  }
+}
+`,
+		},
+		{
+			name: "no match",
+			src:  src,
+			from: "resource.aws_security_group.fuga.ingress",
+			to:   "resource.aws_security_group.hoge",
+			ok:   true,
+			want: src,
+		},
+		{
+			name: "destination not found",
+			src:  src,
+			from: "resource.aws_security_group.hoge.ingress",
+			to:   "resource.aws_security_group.piyo",
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "destination is ambiguous",
+			src:  src,
+			from: "resource.aws_security_group.hoge.ingress",
+			to:   "resource.aws_security_group.*",
+			ok:   false,
+			want: "",
+		},
+		{
+			name: "destination is in the moved block",
+			src:  src,
+			from: "resource.aws_security_group.hoge",
+			to:   "resource.aws_security_group.hoge.egress",
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := MoveBlock(inStream, outStream, "test", tc.from, tc.to)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -10,7 +10,7 @@ import (
 // and writes the updated HCL to io.Writer.
 // Note that a filename is used only for an error message.
 // If an error occurs, Nothing is written to the output stream.
-func RemoveBlock(r io.Reader, w io.Writer, filename string, address string) error {
+func RemoveBlock(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
 	e := &Editor{
 		source: &parser{filename: filename},
 		filters: []Filter{
@@ -18,6 +18,7 @@ func RemoveBlock(r io.Reader, w io.Writer, filename string, address string) erro
 		},
 		sink: &verticalFormater{},
 	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
@@ -29,13 +30,11 @@ type blockRemove struct {
 
 // Filter reads HCL and removes only matched blocks at a given address.
 func (f *blockRemove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
-	typeName, labels, err := parseAddress(f.address)
+	matched, err := findBlocksByAddress(inFile.Body(), f.address)
 	if err != nil {
 		return nil, err
 	}
 
-	matched := findBlocks(inFile.Body(), typeName, labels)
-
 	for _, b := range matched {
 		inFile.Body().RemoveBlock(b)
 	}
@@ -10,7 +10,7 @@ import (
 // the updated HCL to io.Writer.
 // Note that a filename is used only for an error message.
 // If an error occurs, Nothing is written to the output stream.
-func RenameBlock(r io.Reader, w io.Writer, filename string, from string, to string) error {
+func RenameBlock(r io.Reader, w io.Writer, filename string, from string, to string, opts ...Option) error {
 	e := &Editor{
 		source: &parser{filename: filename},
 		filters: []Filter{
@@ -18,6 +18,7 @@ func RenameBlock(r io.Reader, w io.Writer, filename string, from string, to stri
 		},
 		sink: &formater{},
 	}
+	e.setOptions(opts)
 
 	return e.Apply(r, w)
 }
@@ -35,18 +36,16 @@ type blockRename struct {
 // context, but filters can chain to others and the later filter may edit its
 // attributes. So we allow this filter to any block type and labels.
 func (f *blockRename) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
-	fromTypeName, fromLabels, err := parseAddress(f.from)
+	toTypeName, toLabels, err := parseAddress(f.to)
 	if err != nil {
 		return nil, err
 	}
 
-	toTypeName, toLabels, err := parseAddress(f.to)
+	matched, err := findBlocksByAddress(inFile.Body(), f.from)
 	if err != nil {
 		return nil, err
 	}
 
-	matched := findBlocks(inFile.Body(), fromTypeName, fromLabels)
-
 	for _, b := range matched {
 		b.SetType(toTypeName)
 		b.SetLabels(toLabels)
@@ -0,0 +1,144 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// RenameBlockLabels reads HCL from io.Reader, and rewrites only labels of a
+// matched block, and writes the updated HCL to io.Writer.
+// The labels are given as a dot-separated string such as aws_instance.web,
+// which can be quoted and escaped as well as an address. The number of labels
+// may differ from the original. The block type and the body are untouched.
+// If no block matches, the input is written as it is. It returns an error if
+// multiple blocks match, because they would end up with the same labels.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func RenameBlockLabels(r io.Reader, w io.Writer, filename string, address string, labels string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockRenameLabels{address: address, labels: labels},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// RenameBlockLabelsWithMoved is the same as RenameBlockLabels, but also
+// appends a Terraform moved block from the old address to the new one to the
+// end of the file, so that state migrations follow the rename.
+// It is supported only for resource and module blocks, and returns an error
+// for other block types. If the labels are not changed, no moved block is
+// appended.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func RenameBlockLabelsWithMoved(r io.Reader, w io.Writer, filename string, address string, labels string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockRenameLabels{address: address, labels: labels, moved: true},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockRenameLabels is a filter implementation for renaming labels of block.
+type blockRenameLabels struct {
+	address string
+	labels  string
+	// moved is a flag to append a moved block for the rename.
+	moved bool
+}
+
+// Filter reads HCL and rewrites labels of a matched block at a given address.
+func (f *blockRenameLabels) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	segments, err := splitAddress(f.labels)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse labels: %s", err)
+	}
+	labels := []string{}
+	for _, s := range segments {
+		labels = append(labels, unquoteSegment(s))
+	}
+
+	matched, err := findLongestMatchingBlocks(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(matched) {
+	case 0:
+		return inFile, nil
+	case 1:
+		if f.moved {
+			if err := appendMovedBlock(inFile.Body(), matched[0], labels); err != nil {
+				return nil, err
+			}
+		}
+		matched[0].SetLabels(labels)
+		return inFile, nil
+	default:
+		return nil, fmt.Errorf("failed to rename labels. multiple blocks match: %s", f.address)
+	}
+}
+
+// appendMovedBlock appends a moved block from the current labels of a given
+// block to new labels to a body. If the labels are the same, it does nothing.
+func appendMovedBlock(body *hclwrite.Body, b *hclwrite.Block, labels []string) error {
+	from, err := movedTraversal(b.Type(), b.Labels())
+	if err != nil {
+		return err
+	}
+	to, err := movedTraversal(b.Type(), labels)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(b.Labels(), labels) {
+		return nil
+	}
+
+	body.AppendNewline()
+	moved := body.AppendNewBlock("moved", nil)
+	moved.Body().SetAttributeTraversal("from", from)
+	moved.Body().SetAttributeTraversal("to", to)
+
+	return nil
+}
+
+// movedTraversal returns a Terraform address of a block with a given type and
+// labels as a traversal, such as aws_instance.web and module.foo.
+func movedTraversal(typeName string, labels []string) (hcl.Traversal, error) {
+	var names []string
+	switch {
+	case typeName == "resource" && len(labels) == 2:
+		names = labels
+	case typeName == "module" && len(labels) == 1:
+		names = []string{"module", labels[0]}
+	default:
+		return nil, fmt.Errorf("failed to generate moved block. only resource and module blocks are supported: %s %s", typeName, strings.Join(labels, "."))
+	}
+
+	for _, n := range names {
+		if !hclsyntax.ValidIdentifier(n) {
+			return nil, fmt.Errorf("failed to generate moved block. invalid name: %s", n)
+		}
+	}
+
+	return hcl.Traversal{
+		hcl.TraverseRoot{Name: names[0]},
+		hcl.TraverseAttr{Name: names[1]},
+	}, nil
+}
@@ -0,0 +1,232 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockRenameLabels(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  # comment
+  ami = "ami-123"
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-456"
+}
+`
+
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		labels  string
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "simple",
+			src:     src,
+			address: "resource.aws_instance.web",
+			labels:  "aws_instance.app",
+			ok:      true,
+			want: `resource "aws_instance" "app" {
+  # comment
+  ami = "ami-123"
+}
+
+resource "aws_instance" "db" {
+  ami = "ami-456"
+}
+`,
+		},
+		{
+			name:    "quoted label",
+			src:     src,
+			address: "resource.aws_instance.db",
+			labels:  `aws_instance."db.primary"`,
+			ok:      true,
+			want: `resource "aws_instance" "web" {
+  # comment
+  ami = "ami-123"
+}
+
+resource "aws_instance" "db.primary" {
+  ami = "ami-456"
+}
+`,
+		},
+		{
+			name: "change the number of labels",
+			src: `module "hoge" {
+  source = "./hoge"
+}
+`,
+			address: "module.hoge",
+			labels:  "fuga.piyo",
+			ok:      true,
+			want: `module "fuga" "piyo" {
+  source = "./hoge"
+}
+`,
+		},
+		{
+			name:    "no match",
+			src:     src,
+			address: "resource.aws_instance.hoge",
+			labels:  "aws_instance.app",
+			ok:      true,
+			want:    src,
+		},
+		{
+			name:    "multiple matches",
+			src:     src,
+			address: "resource.aws_instance.*",
+			labels:  "aws_instance.app",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "empty labels",
+			src:     src,
+			address: "resource.aws_instance.web",
+			labels:  "",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := RenameBlockLabels(inStream, outStream, "test", tc.address, tc.labels)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockRenameLabelsWithMoved(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		labels  string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "resource",
+			src: `resource "aws_instance" "web" {
+  ami = "ami-123"
+}
+`,
+			address: "resource.aws_instance.web",
+			labels:  "aws_instance.app",
+			ok:      true,
+			want: `resource "aws_instance" "app" {
+  ami = "ami-123"
+}
+
+moved {
+  from = aws_instance.web
+  to   = aws_instance.app
+}
+`,
+		},
+		{
+			name: "module",
+			src: `module "foo" {
+  source = "./foo"
+}
+`,
+			address: "module.foo",
+			labels:  "bar",
+			ok:      true,
+			want: `module "bar" {
+  source = "./foo"
+}
+
+moved {
+  from = module.foo
+  to   = module.bar
+}
+`,
+		},
+		{
+			name: "same labels",
+			src: `resource "aws_instance" "web" {
+}
+`,
+			address: "resource.aws_instance.web",
+			labels:  "aws_instance.web",
+			ok:      true,
+			want: `resource "aws_instance" "web" {
+}
+`,
+		},
+		{
+			name: "not found",
+			src: `resource "aws_instance" "web" {
+}
+`,
+			address: "resource.aws_instance.db",
+			labels:  "aws_instance.app",
+			ok:      true,
+			want: `resource "aws_instance" "web" {
+}
+`,
+		},
+		{
+			name: "unsupported block type",
+			src: `data "aws_ami" "web" {
+}
+`,
+			address: "data.aws_ami.web",
+			labels:  "aws_ami.app",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "invalid name",
+			src: `resource "aws_instance" "web" {
+}
+`,
+			address: "resource.aws_instance.web",
+			labels:  `aws_instance."my app"`,
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := RenameBlockLabelsWithMoved(inStream, outStream, "test", tc.address, tc.labels)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
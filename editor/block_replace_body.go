@@ -0,0 +1,99 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// ReplaceBlockBody reads HCL from io.Reader, and replaces a body of a matched
+// block with a given HCL, and writes the updated HCL to io.Writer.
+// The type and labels of the block are kept as they are.
+// If the address matches multiple blocks, it returns an error.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ReplaceBlockBody(r io.Reader, w io.Writer, filename string, address string, bodyHCL string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockReplaceBody{address: address, body: bodyHCL},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockReplaceBody is a filter implementation for replacing a body of block.
+type blockReplaceBody struct {
+	address string
+	body    string
+}
+
+// Filter reads HCL and replaces a body of a matched block at a given address.
+func (f *blockReplaceBody) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matched, err := findBlocksByAddress(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return inFile, nil
+	}
+	if len(matched) > 1 {
+		return nil, fmt.Errorf("failed to replace block body. the address matches %d blocks: %s", len(matched), f.address)
+	}
+
+	// parse the new body as a standalone body to validate it.
+	bodyFile, err := safeParseConfig([]byte(f.body), "generated_by_blockReplaceBody", hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse block body: %s", err)
+	}
+	bodyTokens := trimLeadingNewLine(trimEOF(bodyFile.BuildTokens(nil)))
+	if len(bodyTokens) != 0 {
+		bodyTokens = withTrailingNewline(bodyTokens)
+	}
+
+	blockTokens := matched[0].BuildTokens(nil)
+	open, close := blockBraces(blockTokens)
+	if open < 0 {
+		return nil, fmt.Errorf("failed to find braces of block: %s", f.address)
+	}
+
+	var newTokens hclwrite.Tokens
+	newTokens = append(newTokens, blockTokens[:open+1]...)
+	newTokens = append(newTokens, &hclwrite.Token{
+		Type:  hclsyntax.TokenNewline,
+		Bytes: []byte("\n"),
+	})
+	newTokens = append(newTokens, bodyTokens...)
+	newTokens = append(newTokens, blockTokens[close:]...)
+
+	// The new body is re-indented by the formater.
+	return replaceTokens(inFile, blockTokens, newTokens)
+}
+
+// blockBraces returns indexes of the open and close braces in tokens of block.
+// Labels cannot contain braces, so the first open brace begins the body and
+// the last close brace ends the body.
+// If not found, it returns (-1, -1).
+func blockBraces(blockTokens hclwrite.Tokens) (int, int) {
+	open, close := -1, -1
+	for i, t := range blockTokens {
+		if t.Type == hclsyntax.TokenOBrace && open < 0 {
+			open = i
+		}
+		if t.Type == hclsyntax.TokenCBrace {
+			close = i
+		}
+	}
+
+	if open < 0 || close < open {
+		return -1, -1
+	}
+
+	return open, close
+}
@@ -0,0 +1,144 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockReplaceBody(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		body    string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `
+// before block
+b1 "l1" {
+  a1 = v1
+}
+
+b1 "l2" {
+  a1 = v1
+}
+`,
+			address: "b1.l1",
+			body: `
+// new body
+a2 = v2
+b2 {
+a3 = v3
+}
+`,
+			ok: true,
+			want: `
+// before block
+b1 "l1" {
+  // new body
+  a2 = v2
+  b2 {
+    a3 = v3
+  }
+}
+
+b1 "l2" {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "single line block",
+			src: `
+b1 { a1 = v1 }
+`,
+			address: "b1",
+			body:    `a2 = v2`,
+			ok:      true,
+			want: `
+b1 {
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "empty body",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b1",
+			body:    "",
+			ok:      true,
+			want: `
+b1 {
+}
+`,
+		},
+		{
+			name: "no match",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			address: "b2",
+			body:    "a2 = v2",
+			ok:      true,
+			want: `
+b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "multiple matches",
+			src: `
+b1 "l1" {
+}
+
+b1 "l2" {
+}
+`,
+			address: "b1.*",
+			body:    "a2 = v2",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "invalid body",
+			src: `
+b1 {
+}
+`,
+			address: "b1",
+			body:    "a2 = ",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ReplaceBlockBody(inStream, outStream, "test", tc.address, tc.body)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package editor
+
+import (
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SortBlocksBy reads HCL from io.Reader, and sorts top level blocks of a
+// given type by a value of keyAttr in each block, and writes the updated HCL
+// to io.Writer.
+// Values are compared as strings. A string literal is compared by its
+// unquoted value, and any other expression is compared by its raw source.
+// Blocks lacking keyAttr sort last in original relative order.
+// The sorted blocks are placed in the positions originally occupied by blocks
+// of the type, so other items are not moved. Comments immediately above each
+// block move with it.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SortBlocksBy(r io.Reader, w io.Writer, filename string, blockType string, keyAttr string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockSort{blockType: blockType, keyAttr: keyAttr},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockSort is a filter implementation for sorting blocks.
+type blockSort struct {
+	blockType string
+	keyAttr   string
+}
+
+// blockSpan is a range [start, end) of tokens of a block in the body tokens.
+type blockSpan struct {
+	start int
+	end   int
+	// key is a value of the key attribute used for sorting.
+	key string
+	// hasKey is false if the block lacks the key attribute.
+	hasKey bool
+}
+
+// Filter reads HCL and sorts matched blocks.
+func (f *blockSort) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	tokens := inFile.BuildTokens(nil)
+
+	spans := []blockSpan{}
+	for _, b := range allMatchingBlocksByType(inFile.Body(), f.blockType) {
+		start, end := itemWithLeadingComments(tokens, b.BuildTokens(nil))
+		if start < 0 {
+			continue
+		}
+		span := blockSpan{start: start, end: end}
+		if attr := b.Body().GetAttribute(f.keyAttr); attr != nil {
+			span.key = sortKey(attr.Expr())
+			span.hasKey = true
+		}
+		spans = append(spans, span)
+	}
+
+	sorted := make([]blockSpan, len(spans))
+	copy(sorted, spans)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].hasKey != sorted[j].hasKey {
+			return sorted[i].hasKey
+		}
+		return sorted[i].key < sorted[j].key
+	})
+
+	// Rebuild tokens by filling original positions with sorted blocks.
+	var out hclwrite.Tokens
+	pos := 0
+	for i, span := range spans {
+		out = append(out, tokens[pos:span.start]...)
+		out = append(out, withTrailingNewline(copyTokens(tokens[sorted[i].start:sorted[i].end]))...)
+		pos = span.end
+	}
+	out = append(out, tokens[pos:]...)
+
+	return safeParseConfig(out.Bytes(), "generated_by_blockSort", hcl.Pos{Line: 1, Column: 1})
+}
+
+// sortKey returns a string representation of an expression for sorting.
+func sortKey(expr *hclwrite.Expression) string {
+	raw := getExpressionAsString(expr)
+	if s, ok := stringLiteralValue(raw); ok {
+		return s
+	}
+	return raw
+}
+
+// copyTokens returns a shallow copy of a given slice of tokens, so that
+// appending to it doesn't overwrite the underlying array.
+func copyTokens(tokens hclwrite.Tokens) hclwrite.Tokens {
+	return append(hclwrite.Tokens{}, tokens...)
+}
@@ -0,0 +1,132 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SortBlocks reads HCL from io.Reader, and sorts sibling blocks by type and
+// then by labels, and writes the updated HCL to io.Writer.
+// If address is empty, top level blocks are sorted. Otherwise, nested blocks
+// in the body of matched blocks at a given address are sorted.
+// The sorted blocks are placed in the positions originally occupied by
+// blocks, so attributes, blank lines and comments separated from a block by a
+// blank line are not moved. Comments immediately above each block move with
+// it, and tokens inside each block are preserved verbatim.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SortBlocks(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockTypeSort{address: address},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockTypeSort is a filter implementation for sorting blocks by type and
+// labels.
+type blockTypeSort struct {
+	address string
+}
+
+// blockLabelsSpan is a range [start, end) of tokens of a block in the file
+// tokens.
+type blockLabelsSpan struct {
+	start int
+	end   int
+	// keys is a block type followed by labels.
+	keys []string
+}
+
+// Filter reads HCL and sorts sibling blocks.
+func (f *blockTypeSort) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	bodies := []*hclwrite.Body{inFile.Body()}
+	if f.address != "" {
+		blocks, err := findLongestMatchingBlocks(inFile.Body(), f.address)
+		if err != nil {
+			return nil, err
+		}
+
+		bodies = []*hclwrite.Body{}
+		seen := make(map[*hclwrite.Block]bool)
+		for _, b := range blocks {
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+			bodies = append(bodies, b.Body())
+		}
+	}
+
+	tokens := inFile.BuildTokens(nil)
+
+	// spans is a list of original spans, and sorted is a list of spans to be
+	// placed there in the same order.
+	spans := []blockLabelsSpan{}
+	sorted := []blockLabelsSpan{}
+	for _, body := range bodies {
+		bodySpans := []blockLabelsSpan{}
+		for _, b := range body.Blocks() {
+			start, end := itemWithLeadingComments(tokens, b.BuildTokens(nil))
+			if start < 0 {
+				continue
+			}
+			keys := append([]string{b.Type()}, b.Labels()...)
+			bodySpans = append(bodySpans, blockLabelsSpan{start: start, end: end, keys: keys})
+		}
+
+		bodySorted := make([]blockLabelsSpan, len(bodySpans))
+		copy(bodySorted, bodySpans)
+		sort.SliceStable(bodySorted, func(i, j int) bool {
+			return lessKeys(bodySorted[i].keys, bodySorted[j].keys)
+		})
+
+		spans = append(spans, bodySpans...)
+		sorted = append(sorted, bodySorted...)
+	}
+
+	// Rebuild tokens by filling original positions with sorted blocks.
+	// Matched blocks may be nested when the address contains a wildcard, but
+	// sorting both an outer body and an inner one at once is ambiguous.
+	order := make([]int, len(spans))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return spans[order[i]].start < spans[order[j]].start
+	})
+
+	var out hclwrite.Tokens
+	pos := 0
+	for _, i := range order {
+		if spans[i].start < pos {
+			return nil, fmt.Errorf("failed to sort blocks: matched blocks are nested: %s", f.address)
+		}
+		out = append(out, tokens[pos:spans[i].start]...)
+		out = append(out, withTrailingNewline(copyTokens(tokens[sorted[i].start:sorted[i].end]))...)
+		pos = spans[i].end
+	}
+	out = append(out, tokens[pos:]...)
+
+	return safeParseConfig(out.Bytes(), "generated_by_blockTypeSort", hcl.Pos{Line: 1, Column: 1})
+}
+
+// lessKeys compares two lists of keys lexicographically.
+// A shorter list is less than a longer one if it is a prefix of the other.
+func lessKeys(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
@@ -0,0 +1,172 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockTypeSort(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `
+variable "c" {}
+
+variable "a" {
+  type = string
+}
+
+variable "b" {}
+`,
+			address: "",
+			ok:      true,
+			want: `
+variable "a" {
+  type = string
+}
+
+variable "b" {}
+
+variable "c" {}
+`,
+		},
+		{
+			name: "by type then labels",
+			src: `
+resource "foo" "b" {}
+variable "x" {}
+resource "foo" "a" {}
+resource "bar" "z" {}
+locals {}
+`,
+			address: "",
+			ok:      true,
+			want: `
+locals {}
+resource "bar" "z" {}
+resource "foo" "a" {}
+resource "foo" "b" {}
+variable "x" {}
+`,
+		},
+		{
+			name: "comments move with blocks and other items stay",
+			src: `# header comment
+
+a0 = v0
+
+// comment b
+variable "b" {
+  # inner comment
+  default = 1
+}
+
+/*
+  comment a
+*/
+variable "a" {}
+`,
+			address: "",
+			ok:      true,
+			want: `# header comment
+
+a0 = v0
+
+/*
+  comment a
+*/
+variable "a" {}
+
+// comment b
+variable "b" {
+  # inner comment
+  default = 1
+}
+`,
+		},
+		{
+			name: "nested blocks",
+			src: `
+resource "foo" "bar" {
+  b {}
+  attr = 1
+  a "y" {}
+  a "x" {}
+}
+
+resource "foo" "baz" {
+  b {}
+  a {}
+}
+`,
+			address: "resource.foo.bar",
+			ok:      true,
+			want: `
+resource "foo" "bar" {
+  a "x" {}
+  attr = 1
+  a "y" {}
+  b {}
+}
+
+resource "foo" "baz" {
+  b {}
+  a {}
+}
+`,
+		},
+		{
+			name: "no match",
+			src: `
+b {}
+a {}
+`,
+			address: "foo",
+			ok:      true,
+			want: `
+b {}
+a {}
+`,
+		},
+		{
+			name: "nested matches",
+			src: `
+a {
+  a {
+    c {}
+    b {}
+  }
+}
+`,
+			address: "**.a",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SortBlocks(inStream, outStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockSort(t *testing.T) {
+	cases := []struct {
+		name      string
+		src       string
+		blockType string
+		keyAttr   string
+		ok        bool
+		want      string
+	}{
+		{
+			name: "simple",
+			src: `
+resource "r" "c" {
+  name = "c"
+}
+
+resource "r" "a" {
+  name = "a"
+}
+
+resource "r" "b" {
+  name = "b"
+}
+`,
+			blockType: "resource",
+			keyAttr:   "name",
+			ok:        true,
+			want: `
+resource "r" "a" {
+  name = "a"
+}
+
+resource "r" "b" {
+  name = "b"
+}
+
+resource "r" "c" {
+  name = "c"
+}
+`,
+		},
+		{
+			name: "comments move with blocks and other items stay",
+			src: `
+a0 = v0
+
+// comment c
+b1 {
+  name = "c"
+}
+
+b2 {
+}
+
+/*
+  comment a
+*/
+b1 {
+  name = "a"
+}
+`,
+			blockType: "b1",
+			keyAttr:   "name",
+			ok:        true,
+			want: `
+a0 = v0
+
+/*
+  comment a
+*/
+b1 {
+  name = "a"
+}
+
+b2 {
+}
+
+// comment c
+b1 {
+  name = "c"
+}
+`,
+		},
+		{
+			name: "blocks lacking key sort last in original order",
+			src: `
+b1 "x" {
+}
+b1 "y" {
+  name = "b"
+}
+b1 "z" {
+}
+b1 "w" {
+  name = "a"
+}
+`,
+			blockType: "b1",
+			keyAttr:   "name",
+			ok:        true,
+			want: `
+b1 "w" {
+  name = "a"
+}
+b1 "y" {
+  name = "b"
+}
+b1 "x" {
+}
+b1 "z" {
+}
+`,
+		},
+		{
+			name: "raw expressions",
+			src: `
+b1 {
+  name = var.b
+}
+b1 {
+  name = "var.a"
+}
+b1 {
+  name = var.c
+}`,
+			blockType: "b1",
+			keyAttr:   "name",
+			ok:        true,
+			want: `
+b1 {
+  name = "var.a"
+}
+b1 {
+  name = var.b
+}
+b1 {
+  name = var.c
+}
+`,
+		},
+		{
+			name: "no match",
+			src: `
+b1 {
+  name = "a"
+}
+`,
+			blockType: "b2",
+			keyAttr:   "name",
+			ok:        true,
+			want: `
+b1 {
+  name = "a"
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SortBlocksBy(inStream, outStream, "test", tc.blockType, tc.keyAttr)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// UnwrapBlock reads HCL from io.Reader, and removes the header and braces of
+// matched blocks while hoisting their attributes and nested blocks into the
+// parent body, and writes the updated HCL to io.Writer.
+// This is useful for flattening wrapper blocks such as an obsolete lifecycle
+// or timeouts block. Leading comments of the removed block are kept.
+// If matched blocks are nested in each other, only the outermost ones are
+// unwrapped. It returns an error if a hoisted attribute already exists in the
+// parent body.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func UnwrapBlock(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockUnwrap{address: address},
+		},
+		sink: &verticalFormater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockUnwrap is a filter implementation for unwrapping block.
+type blockUnwrap struct {
+	address string
+}
+
+// Filter reads HCL and unwraps matched blocks at a given address.
+func (f *blockUnwrap) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	matched, err := findLongestMatchingBlocks(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return inFile, nil
+	}
+
+	parents := parentBodies(inFile.Body())
+	outermost := []*hclwrite.Block{}
+	for _, b := range matched {
+		nested := false
+		for _, other := range matched {
+			if other != b && containsBlock(other.Body(), b) {
+				nested = true
+				break
+			}
+		}
+		if nested {
+			continue
+		}
+
+		for name := range b.Body().Attributes() {
+			if parents[b].GetAttribute(name) != nil {
+				return nil, fmt.Errorf("failed to unwrap block. attribute already exists in the parent: %s", name)
+			}
+		}
+		outermost = append(outermost, b)
+	}
+
+	// replace tokens of the blocks in a single pass, because the replacement
+	// invalidates identities of other tokens.
+	type replacement struct {
+		start, end int
+		tokens     hclwrite.Tokens
+	}
+	tokens := inFile.BuildTokens(nil)
+	replacements := []replacement{}
+	for _, b := range outermost {
+		blockTokens := b.BuildTokens(nil)
+		start, end := findTokens(tokens, blockTokens)
+		if start < 0 {
+			return nil, fmt.Errorf("failed to find tokens of block: %s", toAddress(b))
+		}
+		replacements = append(replacements, replacement{start: start, end: end, tokens: unwrapTokens(b)})
+	}
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	var unwrapped hclwrite.Tokens
+	pos := 0
+	for _, r := range replacements {
+		unwrapped = append(unwrapped, tokens[pos:r.start]...)
+		unwrapped = append(unwrapped, r.tokens...)
+		pos = r.end
+	}
+	unwrapped = append(unwrapped, tokens[pos:]...)
+
+	return safeParseConfig(unwrapped.Bytes(), "generated_by_blockUnwrap", hcl.Pos{Line: 1, Column: 1})
+}
+
+// unwrapTokens returns tokens of leading comments and the body of a given
+// block without its header and braces.
+func unwrapTokens(b *hclwrite.Block) hclwrite.Tokens {
+	var tokens hclwrite.Tokens
+	for _, t := range b.BuildTokens(nil) {
+		if t.Type != hclsyntax.TokenComment {
+			break
+		}
+		tokens = append(tokens, t)
+	}
+
+	body := b.Body().BuildTokens(nil)
+	if len(body) != 0 && body[0].Type == hclsyntax.TokenNewline {
+		// trim a newline after the opening brace.
+		body = body[1:]
+	}
+	if len(body) != 0 {
+		tokens = append(tokens, withTrailingNewline(body)...)
+	}
+
+	return tokens
+}
@@ -0,0 +1,141 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockUnwrap(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "nested block",
+			src: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  # obsolete wrapper
+  timeouts {
+    create = "5m"
+    # nested
+    nested {
+      a = 1
+    }
+  }
+  tags = {}
+}
+`,
+			address: "resource.aws_instance.web.timeouts",
+			ok:      true,
+			want: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  # obsolete wrapper
+  create = "5m"
+  # nested
+  nested {
+    a = 1
+  }
+  tags = {}
+}
+`,
+		},
+		{
+			name: "multiple blocks",
+			src: `b1 "l1" {
+  w {
+    a1 = v1
+  }
+}
+b1 "l2" {
+  w {
+    a2 = v2
+  }
+}
+`,
+			address: "b1.*.w",
+			ok:      true,
+			want: `b1 "l1" {
+  a1 = v1
+}
+b1 "l2" {
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "empty block",
+			src: `b1 {
+  a1 = v1
+  w {
+  }
+}
+`,
+			address: "b1.w",
+			ok:      true,
+			want: `b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "top level block",
+			src: `locals {
+  a1 = v1
+}
+`,
+			address: "locals",
+			ok:      true,
+			want: `a1 = v1
+`,
+		},
+		{
+			name: "attribute conflicts",
+			src: `b1 {
+  a1 = v1
+  w {
+    a1 = v2
+  }
+}
+`,
+			address: "b1.w",
+			ok:      false,
+			want:    "",
+		},
+		{
+			name: "no match",
+			src: `b1 {
+  a1 = v1
+}
+`,
+			address: "b1.w",
+			ok:      true,
+			want: `b1 {
+  a1 = v1
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := UnwrapBlock(inStream, outStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// NewBlockWhereFilter returns a Filter which leaves only top level blocks
+// satisfying a given predicate such as `instance_type == "t2.micro"`.
+// The predicate is an HCL expression evaluated for each block, where
+// attributes of the block can be referred by their names. An attribute which
+// doesn't exist in the block is null, so that `ami == "ami-123"` is false for
+// a block without ami. Only attributes whose values are constant expressions
+// such as literals are known, and a predicate which depends on other
+// attributes doesn't match the block. Functions are not available.
+// It returns an error if the predicate cannot be parsed.
+func NewBlockWhereFilter(predicate string) (Filter, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(predicate), "predicate", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return nil, fmt.Errorf("failed to parse predicate: %s", diags)
+	}
+
+	return &blockWhere{predicate: predicate, expr: expr}, nil
+}
+
+// blockWhere is a filter implementation to select blocks by a predicate.
+type blockWhere struct {
+	// predicate is a source of the predicate used for an error message.
+	predicate string
+	expr      hclsyntax.Expression
+}
+
+// Filter reads HCL and writes only blocks satisfying the predicate.
+func (f *blockWhere) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	outFile := hclwrite.NewEmptyFile()
+	n := 0
+	for _, b := range inFile.Body().Blocks() {
+		ok, err := f.match(b)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		if n != 0 {
+			// when adding a new block, insert a new line before the block.
+			outFile.Body().AppendNewline()
+		}
+		outFile.Body().AppendBlock(b)
+		n++
+	}
+
+	return outFile, nil
+}
+
+// match evaluates the predicate with attributes of a given block.
+// It returns an error if the result is known but not a bool.
+func (f *blockWhere) match(b *hclwrite.Block) (bool, error) {
+	vars := blockVariables(b)
+	for _, traversal := range f.expr.Variables() {
+		if _, ok := vars[traversal.RootName()]; !ok {
+			vars[traversal.RootName()] = cty.NullVal(cty.DynamicPseudoType)
+		}
+	}
+	ctx := &hcl.EvalContext{
+		Variables: vars,
+	}
+
+	v, diags := f.expr.Value(ctx)
+	if diags.HasErrors() || !v.IsKnown() || v.IsNull() {
+		// a predicate which cannot be evaluated, such as one depending on
+		// non-constant attributes, never matches.
+		return false, nil
+	}
+
+	if v.Type() != cty.Bool {
+		return false, fmt.Errorf("failed to evaluate predicate. the result must be a bool, but got %s: %s", v.Type().FriendlyName(), f.predicate)
+	}
+
+	return v.True(), nil
+}
+
+// blockVariables returns values of attributes in a given block by name.
+// An attribute which is not a constant expression is unknown.
+func blockVariables(b *hclwrite.Block) map[string]cty.Value {
+	vars := make(map[string]cty.Value)
+	for name, attr := range b.Body().Attributes() {
+		parsed, diags := parseValueExpression(getExpressionAsString(attr.Expr()))
+		if diags.HasErrors() {
+			vars[name] = cty.DynamicVal
+			continue
+		}
+
+		v, diags := parsed.Value(nil)
+		if diags.HasErrors() {
+			vars[name] = cty.DynamicVal
+			continue
+		}
+		vars[name] = v
+	}
+
+	return vars
+}
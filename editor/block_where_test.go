@@ -0,0 +1,148 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockWhere(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+  count         = 2
+  tags = {
+    Name = "web"
+  }
+}
+
+resource "aws_instance" "db" {
+  instance_type = "m5.large"
+  count         = var.db_count
+}
+
+resource "aws_instance" "batch" {
+  ami = "ami-123"
+}
+`
+
+	cases := []struct {
+		name      string
+		address   string
+		predicate string
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "string equality",
+			address:   "resource.aws_instance.*",
+			predicate: `instance_type == "t2.micro"`,
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+  count         = 2
+  tags = {
+    Name = "web"
+  }
+}
+`,
+		},
+		{
+			name:      "missing attribute is null",
+			address:   "resource.aws_instance.*",
+			predicate: `instance_type != "t2.micro"`,
+			ok:        true,
+			want: `resource "aws_instance" "db" {
+  instance_type = "m5.large"
+  count         = var.db_count
+}
+
+resource "aws_instance" "batch" {
+  ami = "ami-123"
+}
+`,
+		},
+		{
+			name:      "non-constant attribute never matches",
+			address:   "resource.aws_instance.*",
+			predicate: `count > 1`,
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+  count         = 2
+  tags = {
+    Name = "web"
+  }
+}
+`,
+		},
+		{
+			name:      "multiple blocks",
+			address:   "resource.aws_instance.*",
+			predicate: `instance_type == "t2.micro" || ami == "ami-123"`,
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+  count         = 2
+  tags = {
+    Name = "web"
+  }
+}
+
+resource "aws_instance" "batch" {
+  ami = "ami-123"
+}
+`,
+		},
+		{
+			name:      "nested value",
+			address:   "resource.aws_instance.*",
+			predicate: `tags.Name == "web"`,
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  instance_type = "t2.micro"
+  count         = 2
+  tags = {
+    Name = "web"
+  }
+}
+`,
+		},
+		{
+			name:      "not a bool",
+			address:   "resource.aws_instance.*",
+			predicate: `instance_type`,
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := NewBlockWhereFilter(tc.predicate)
+			if err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err = GetBlock(inStream, outStream, "test", tc.address, WithFilters(filter))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewBlockWhereFilterInvalid(t *testing.T) {
+	if _, err := NewBlockWhereFilter(`instance_type ==`); err == nil {
+		t.Fatalf("expected to return an error, but no error")
+	}
+}
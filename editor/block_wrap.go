@@ -0,0 +1,130 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// WrapBlock reads HCL from io.Reader, and moves matched attributes and blocks
+// at given addresses into a new nested block, and writes the updated HCL to
+// io.Writer. This is the inverse of UnwrapBlock.
+// The into is an address of the new block relative to the parent such as
+// timeouts or dynamic.ingress. A new block is created for each parent body of
+// matched items at the position of the first item, and the items are moved
+// into it in the source order with their leading comments.
+// If nothing matches, the input is written as it is.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func WrapBlock(r io.Reader, w io.Writer, filename string, addresses []string, into string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&blockWrap{addresses: addresses, into: into},
+		},
+		sink: &verticalFormater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// blockWrap is a filter implementation for wrapping items into a new block.
+type blockWrap struct {
+	addresses []string
+	into      string
+}
+
+// wrapItem is a range of tokens of an item to be wrapped.
+type wrapItem struct {
+	start, end int
+	parent     *hclwrite.Body
+}
+
+// Filter reads HCL and moves matched items into a new block.
+func (f *blockWrap) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	typeName, labels, err := parseAddress(f.into)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := inFile.BuildTokens(nil)
+	parents := parentBodies(inFile.Body())
+	items := []wrapItem{}
+	seen := make(map[int]bool)
+	add := func(itemTokens hclwrite.Tokens, parent *hclwrite.Body) error {
+		start, end := findTokens(tokens, itemTokens)
+		if start < 0 {
+			return fmt.Errorf("failed to find tokens to be wrapped: %s", string(itemTokens.Bytes()))
+		}
+		if !seen[start] {
+			seen[start] = true
+			items = append(items, wrapItem{start: start, end: end, parent: parent})
+		}
+		return nil
+	}
+
+	for _, address := range f.addresses {
+		attrs, err := findTargetAttributes(inFile.Body(), address)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range attrs {
+			if err := add(m.attr.BuildTokens(nil), m.body); err != nil {
+				return nil, err
+			}
+		}
+
+		blocks, err := findLongestMatchingBlocks(inFile.Body(), address)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range blocks {
+			if err := add(b.BuildTokens(nil), parents[b]); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return inFile, nil
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].start < items[j].start
+	})
+	for i := 1; i < len(items); i++ {
+		if items[i].start < items[i-1].end {
+			return nil, fmt.Errorf("failed to wrap. matched items are nested in each other")
+		}
+	}
+
+	// group items by parent, and build tokens of a new block for each parent.
+	groups := make(map[*hclwrite.Body]*hclwrite.Block)
+	first := make(map[*hclwrite.Body]int)
+	for _, item := range items {
+		block, ok := groups[item.parent]
+		if !ok {
+			block = hclwrite.NewBlock(typeName, labels)
+			groups[item.parent] = block
+			first[item.parent] = item.start
+		}
+		block.Body().AppendUnstructuredTokens(withTrailingNewline(copyTokens(tokens[item.start:item.end])))
+	}
+
+	var wrapped hclwrite.Tokens
+	pos := 0
+	for _, item := range items {
+		wrapped = append(wrapped, tokens[pos:item.start]...)
+		if first[item.parent] == item.start {
+			wrapped = append(wrapped, groups[item.parent].BuildTokens(nil)...)
+		}
+		pos = item.end
+	}
+	wrapped = append(wrapped, tokens[pos:]...)
+
+	return safeParseConfig(wrapped.Bytes(), "generated_by_blockWrap", hcl.Pos{Line: 1, Column: 1})
+}
@@ -0,0 +1,139 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBlockWrap(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami = "ami-123"
+  # create timeout
+  create = "5m"
+  delete = "10m"
+  tags   = {}
+}
+`
+
+	cases := []struct {
+		name      string
+		src       string
+		addresses []string
+		into      string
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "attributes",
+			src:       src,
+			addresses: []string{"resource.aws_instance.web.create", "resource.aws_instance.web.delete"},
+			into:      "timeouts",
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  timeouts {
+    # create timeout
+    create = "5m"
+    delete = "10m"
+  }
+  tags = {}
+}
+`,
+		},
+		{
+			name:      "pattern and labels",
+			src:       src,
+			addresses: []string{"resource.aws_instance.*.create", "resource./aws_.*/.web.delete"},
+			into:      "dynamic.timeouts",
+			ok:        true,
+			want: `resource "aws_instance" "web" {
+  ami = "ami-123"
+  dynamic "timeouts" {
+    # create timeout
+    create = "5m"
+    delete = "10m"
+  }
+  tags = {}
+}
+`,
+		},
+		{
+			name: "blocks in multiple parents",
+			src: `b1 "l1" {
+  a1 = v1
+  ingress {
+    from_port = 80
+  }
+}
+b1 "l2" {
+  ingress {
+    from_port = 443
+  }
+}
+`,
+			addresses: []string{"b1.*.ingress"},
+			into:      "rules",
+			ok:        true,
+			want: `b1 "l1" {
+  a1 = v1
+  rules {
+    ingress {
+      from_port = 80
+    }
+  }
+}
+b1 "l2" {
+  rules {
+    ingress {
+      from_port = 443
+    }
+  }
+}
+`,
+		},
+		{
+			name:      "no match",
+			src:       src,
+			addresses: []string{"resource.aws_instance.web.hoge"},
+			into:      "timeouts",
+			ok:        true,
+			want:      src,
+		},
+		{
+			name:      "nested items",
+			src:       src,
+			addresses: []string{"resource.aws_instance.web", "resource.aws_instance.web.ami"},
+			into:      "timeouts",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "invalid into",
+			src:       src,
+			addresses: []string{"resource.aws_instance.web.create"},
+			into:      "",
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := WrapBlock(inStream, outStream, "test", tc.addresses, tc.into)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
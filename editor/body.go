@@ -0,0 +1,29 @@
+package editor
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// attributeNames returns names of attributes in a given body in the source
+// order. The hclwrite.Body.Attributes() returns a map and doesn't preserve
+// the order, so we sort them by positions of tokens in the body.
+func attributeNames(body *hclwrite.Body) []string {
+	tokens := body.BuildTokens(nil)
+	attrs := body.Attributes()
+
+	names := make([]string, 0, len(attrs))
+	pos := make(map[string]int)
+	for name, attr := range attrs {
+		start, _ := findTokens(tokens, attr.BuildTokens(nil))
+		names = append(names, name)
+		pos[name] = start
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return pos[names[i]] < pos[names[j]]
+	})
+
+	return names
+}
@@ -0,0 +1,76 @@
+package editor
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// attributeWithLeadingComments returns a range [start, end) of tokens of a
+// given attribute in tokens of the body containing it. The range includes the
+// comment block immediately above the attribute, that is, full-line comments
+// up to the preceding blank line or item.
+// It is intended to be used when relocating an attribute, so that
+// documentation stays attached to the attribute it describes.
+// If the attribute is not found in the body tokens, it returns (-1, -1).
+func attributeWithLeadingComments(bodyTokens hclwrite.Tokens, attr *hclwrite.Attribute) (int, int) {
+	return itemWithLeadingComments(bodyTokens, attr.BuildTokens(nil))
+}
+
+// itemWithLeadingComments is a generalized version of
+// attributeWithLeadingComments which accepts tokens of any body item.
+// The hclwrite parser already attaches single-line comments immediately
+// above an item as lead comments, but a multi-line comment (/* */) on its own
+// line is left in the body as an unstructured token, so we walk back the body
+// tokens to find them.
+func itemWithLeadingComments(bodyTokens hclwrite.Tokens, itemTokens hclwrite.Tokens) (int, int) {
+	start, end := findTokens(bodyTokens, itemTokens)
+	if start < 0 {
+		return -1, -1
+	}
+
+	for start > 0 {
+		prev := bodyTokens[start-1]
+		if isFullLineComment(bodyTokens, start-1) && endsWithNewline(prev) {
+			// single-line comment consumes its trailing newline.
+			start--
+			continue
+		}
+		if prev.Type == hclsyntax.TokenNewline && start >= 2 && isFullLineComment(bodyTokens, start-2) && !endsWithNewline(bodyTokens[start-2]) {
+			// multi-line comment followed by a newline.
+			start -= 2
+			continue
+		}
+		break
+	}
+
+	return start, end
+}
+
+// isFullLineComment returns true if tokens[i] is a comment which begins at
+// the start of a line, false otherwise.
+func isFullLineComment(tokens hclwrite.Tokens, i int) bool {
+	if tokens[i].Type != hclsyntax.TokenComment {
+		return false
+	}
+
+	if i == 0 {
+		return true
+	}
+
+	prev := tokens[i-1]
+	switch prev.Type {
+	case hclsyntax.TokenNewline, hclsyntax.TokenOBrace:
+		return true
+	case hclsyntax.TokenComment:
+		return endsWithNewline(prev)
+	default:
+		return false
+	}
+}
+
+// endsWithNewline returns true if a given token ends with a newline.
+func endsWithNewline(token *hclwrite.Token) bool {
+	return bytes.HasSuffix(token.Bytes, []byte("\n"))
+}
@@ -0,0 +1,96 @@
+package editor
+
+import (
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// RemoveComment reads HCL from io.Reader, and removes leading comments and a
+// trailing comment of matched attributes or blocks at a given address, and
+// writes the updated HCL to io.Writer.
+// The address is resolved in the same way as SetComment. Comments inside a
+// block and a value of an attribute are not removed.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func RemoveComment(r io.Reader, w io.Writer, filename string, address string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&commentRemove{address: address},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// commentRemove is a filter implementation for removing comments.
+type commentRemove struct {
+	address string
+}
+
+// Filter reads HCL and removes comments of matched items.
+func (f *commentRemove) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	items, err := findCommentTargets(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := inFile.BuildTokens(nil)
+
+	// removed marks indexes of comment tokens to be removed.
+	removed := make(map[int]bool)
+	for _, span := range leadingCommentSpans(tokens, items) {
+		for i := span.start; i < span.end; i++ {
+			removed[i] = true
+		}
+	}
+	for _, item := range items {
+		if i := trailingCommentIndex(tokens, item); i >= 0 {
+			removed[i] = true
+		}
+	}
+
+	var out hclwrite.Tokens
+	for i, t := range tokens {
+		if !removed[i] {
+			out = append(out, t)
+			continue
+		}
+		if t.Type == hclsyntax.TokenComment && endsWithNewline(t) && !isFullLineComment(tokens, i) {
+			// A single-line trailing comment consumes the newline of the item.
+			out = append(out, &hclwrite.Token{
+				Type:  hclsyntax.TokenNewline,
+				Bytes: []byte("\n"),
+			})
+		}
+	}
+
+	return safeParseConfig(out.Bytes(), "generated_by_commentRemove", hcl.Pos{Line: 1, Column: 1})
+}
+
+// trailingCommentIndex returns an index of a comment at the end of the last
+// line of a given item in the file tokens.
+// If not found, it returns -1.
+func trailingCommentIndex(tokens hclwrite.Tokens, item hclwrite.Tokens) int {
+	_, end := findTokens(tokens, item)
+	if end < 0 {
+		return -1
+	}
+
+	i := end - 1
+	if tokens[i].Type == hclsyntax.TokenNewline {
+		// A multi-line comment is followed by a newline.
+		i--
+	}
+
+	if i < 0 || tokens[i].Type != hclsyntax.TokenComment || isFullLineComment(tokens, i) {
+		return -1
+	}
+
+	return i
+}
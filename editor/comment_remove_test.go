@@ -0,0 +1,113 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCommentRemove(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "attribute",
+			src: `
+resource "foo" "bar" {
+  # leading
+  // leading again
+  attr1 = "val1" # trailing
+  attr2 = "val2" # keep
+}
+`,
+			address: "resource.foo.bar.attr1",
+			ok:      true,
+			want: `
+resource "foo" "bar" {
+  attr1 = "val1"
+  attr2 = "val2" # keep
+}
+`,
+		},
+		{
+			name: "multi-line comments",
+			src: `
+/* leading
+   comment */
+attr = "val" /* trailing */
+`,
+			address: "attr",
+			ok:      true,
+			want: `
+attr = "val"
+`,
+		},
+		{
+			name: "block",
+			src: `# header
+
+# leading
+resource "foo" "bar" { # inside
+  # inside
+  attr = "val" # inside
+} # trailing
+`,
+			address: "resource.foo.bar",
+			ok:      true,
+			want: `# header
+
+resource "foo" "bar" { # inside
+  # inside
+  attr = "val" # inside
+}
+`,
+		},
+		{
+			name: "no comments",
+			src: `
+a = 1
+`,
+			address: "a",
+			ok:      true,
+			want: `
+a = 1
+`,
+		},
+		{
+			name: "not found",
+			src: `
+# comment
+a = 1
+`,
+			address: "b",
+			ok:      true,
+			want: `
+# comment
+a = 1
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := RemoveComment(inStream, outStream, "test", tc.address)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SetComment reads HCL from io.Reader, and attaches a leading comment to
+// matched attributes or blocks at a given address, and writes the updated HCL
+// to io.Writer.
+// The address is resolved as an attribute first, and then as blocks if no
+// attribute matched. Existing comments immediately above each matched item
+// are replaced. Each line of the text is written as a `#` comment.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetComment(r io.Reader, w io.Writer, filename string, address string, text string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&commentSet{address: address, text: text},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// commentSet is a filter implementation for setting a leading comment.
+type commentSet struct {
+	address string
+	text    string
+}
+
+// Filter reads HCL and replaces leading comments of matched items.
+func (f *commentSet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	if f.text == "" {
+		return nil, fmt.Errorf("failed to set comment: text is empty")
+	}
+
+	items, err := findCommentTargets(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := commentTokens(f.text)
+	tokens := inFile.BuildTokens(nil)
+
+	// Edit tokens from the end of file so that earlier positions are kept.
+	spans := leadingCommentSpans(tokens, items)
+	for i := len(spans) - 1; i >= 0; i-- {
+		var edited hclwrite.Tokens
+		edited = append(edited, tokens[:spans[i].start]...)
+		edited = append(edited, copyTokens(comment)...)
+		edited = append(edited, tokens[spans[i].end:]...)
+		tokens = edited
+	}
+
+	return safeParseConfig(tokens.Bytes(), "generated_by_commentSet", hcl.Pos{Line: 1, Column: 1})
+}
+
+// findCommentTargets returns tokens of attributes or blocks at a given
+// address. The address is resolved as an attribute first, and then as blocks
+// if no attribute matched.
+func findCommentTargets(body *hclwrite.Body, address string) ([]hclwrite.Tokens, error) {
+	attrs, err := findTargetAttributes(body, address)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []hclwrite.Tokens{}
+	if len(attrs) != 0 {
+		for _, m := range attrs {
+			items = append(items, m.attr.BuildTokens(nil))
+		}
+		return items, nil
+	}
+
+	blocks, err := findLongestMatchingBlocks(body, address)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[*hclwrite.Block]bool)
+	for _, b := range blocks {
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+		items = append(items, b.BuildTokens(nil))
+	}
+
+	return items, nil
+}
+
+// commentSpan is a range [start, end) of tokens in the file tokens.
+type commentSpan struct {
+	start int
+	end   int
+}
+
+// leadingCommentSpans returns ranges of leading comments of given items in
+// the file tokens, sorted by position. A range is empty if the item has no
+// leading comments.
+func leadingCommentSpans(tokens hclwrite.Tokens, items []hclwrite.Tokens) []commentSpan {
+	spans := []commentSpan{}
+	for _, item := range items {
+		start, _ := itemWithLeadingComments(tokens, item)
+		if start < 0 {
+			continue
+		}
+		// The hclwrite parser attaches single-line comments to the item, so
+		// skip comments and newlines to find the beginning of the item itself.
+		end := start
+		for tokens[end].Type == hclsyntax.TokenComment || tokens[end].Type == hclsyntax.TokenNewline {
+			end++
+		}
+		spans = append(spans, commentSpan{start: start, end: end})
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	return spans
+}
@@ -0,0 +1,138 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCommentSet(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		text    string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "attribute without comments",
+			src: `
+resource "foo" "bar" {
+  attr1 = "val1"
+  attr2 = "val2"
+}
+`,
+			address: "resource.foo.bar.attr2",
+			text:    "compliance: reviewed",
+			ok:      true,
+			want: `
+resource "foo" "bar" {
+  attr1 = "val1"
+  # compliance: reviewed
+  attr2 = "val2"
+}
+`,
+		},
+		{
+			name: "replace existing comments",
+			src: `
+// old comment
+/* old multi-line
+   comment */
+attr = "val" # inline
+`,
+			address: "attr",
+			text:    "new comment",
+			ok:      true,
+			want: `
+# new comment
+attr = "val" # inline
+`,
+		},
+		{
+			name: "block with multi-line text",
+			src: `# header
+
+resource "foo" "bar" {
+  attr = "val"
+}
+`,
+			address: "resource.foo.bar",
+			text:    "line1\n\nline2\n",
+			ok:      true,
+			want: `# header
+
+# line1
+#
+# line2
+resource "foo" "bar" {
+  attr = "val"
+}
+`,
+		},
+		{
+			name: "all matched blocks",
+			src: `
+a "x" {
+}
+
+a "y" {
+}
+`,
+			address: "a",
+			text:    "comment",
+			ok:      true,
+			want: `
+# comment
+a "x" {
+}
+
+# comment
+a "y" {
+}
+`,
+		},
+		{
+			name: "not found",
+			src: `
+a = 1
+`,
+			address: "b",
+			text:    "comment",
+			ok:      true,
+			want: `
+a = 1
+`,
+		},
+		{
+			name: "empty text",
+			src: `
+a = 1
+`,
+			address: "a",
+			text:    "",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetComment(inStream, outStream, "test", tc.address, tc.text)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestAttributeWithLeadingComments(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		want    string
+	}{
+		{
+			name: "no comments",
+			src: `a0 = v0
+a1 = v1
+`,
+			address: "a1",
+			want: `a1 = v1
+`,
+		},
+		{
+			name: "leading comments up to the preceding item",
+			src: `a0 = v0 // inline a0
+// comment a1
+# comment a1 again
+a1 = v1
+`,
+			address: "a1",
+			want: `// comment a1
+# comment a1 again
+a1 = v1
+`,
+		},
+		{
+			name: "leading comments up to the preceding blank line",
+			src: `// comment for the file
+
+/* comment a0 */
+a0 = v0 // inline a0
+a1 = v1
+`,
+			address: "a0",
+			want: `/* comment a0 */
+a0 = v0 // inline a0
+`,
+		},
+		{
+			name: "multi-line comments",
+			src: `a0 = v0 /* inline a0 */
+/*
+  comment a1
+*/
+// comment a1 again
+a1 = v1
+`,
+			address: "a1",
+			want: `/*
+  comment a1
+*/
+// comment a1 again
+a1 = v1
+`,
+		},
+		{
+			name: "in block",
+			src: `b1 {
+  /* comment a1 */
+  a1 = v1
+}
+`,
+			address: "b1.a1",
+			want: `  /* comment a1 */
+  a1 = v1
+`,
+		},
+		{
+			name:    "no trailing newline",
+			src:     `a0 = v0`,
+			address: "a0",
+			want: `a0 = v0
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := safeParseConfig([]byte(tc.src), "test", hcl.Pos{Line: 1, Column: 1})
+			if err != nil {
+				t.Fatalf("failed to parse src: %s", err)
+			}
+
+			attr, body, err := findAttribute(f.Body(), tc.address)
+			if err != nil || attr == nil {
+				t.Fatalf("failed to find attribute: %s", tc.address)
+			}
+
+			bodyTokens := body.BuildTokens(nil)
+			start, end := attributeWithLeadingComments(bodyTokens, attr)
+			if start < 0 {
+				t.Fatalf("failed to find tokens of attribute: %s", tc.address)
+			}
+
+			got := string(withTrailingNewline(bodyTokens[start:end]).Bytes())
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of context lines in a unified diff.
+const diffContext = 3
+
+// diffOp is a single line operation of an edit script.
+type diffOp struct {
+	// kind is one of ' ' (equal), '-' (delete) or '+' (insert).
+	kind byte
+	// line is a line including a trailing newline if any.
+	line string
+}
+
+// Diff returns a unified diff between a and b with headers of a given
+// filename. If there is no difference, it returns an empty slice.
+func Diff(filename string, a []byte, b []byte) []byte {
+	return unifiedDiff(filename, a, b)
+}
+
+// unifiedDiff returns a unified diff between a and b.
+// If there is no difference, it returns an empty slice.
+func unifiedDiff(filename string, a []byte, b []byte) []byte {
+	if bytes.Equal(a, b) {
+		return []byte{}
+	}
+
+	ops := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	// aPos and bPos record the numbers of lines consumed before each op.
+	aPos := make([]int, len(ops)+1)
+	bPos := make([]int, len(ops)+1)
+	for i, op := range ops {
+		aPos[i+1] = aPos[i]
+		bPos[i+1] = bPos[i]
+		if op.kind != '+' {
+			aPos[i+1]++
+		}
+		if op.kind != '-' {
+			bPos[i+1]++
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- a/%s\n+++ b/%s\n", filename, filename)
+
+	i := 0
+	for i < len(ops) {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+
+		// found a change, extend the hunk while changes are close enough.
+		start := i - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+			end = next
+		}
+		end += diffContext
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		fmt.Fprintf(&buf, "@@ -%s +%s @@\n",
+			hunkRange(aPos[start], aPos[end]-aPos[start]),
+			hunkRange(bPos[start], bPos[end]-bPos[start]))
+		for _, op := range ops[start:end] {
+			buf.WriteByte(op.kind)
+			buf.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				buf.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		i = end
+	}
+
+	return buf.Bytes()
+}
+
+// hunkRange returns a range notation of hunk header.
+// The start is a 0-based index of the first line in the hunk.
+func hunkRange(start int, count int) string {
+	switch count {
+	case 0:
+		// an empty range refers to the line just before the hunk.
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
+
+// splitLines splits a string into lines including trailing newlines.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if len(lines) != 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns an edit script which converts a into b.
+// It is based on the linear space variant of the Myers' O(ND) difference
+// algorithm, so that a large file with a few edits far apart can be compared
+// in time proportional to the number of edits without a large table.
+func diffLines(a []string, b []string) []diffOp {
+	ops := []diffOp{}
+	var walk func(a []string, b []string)
+	walk = func(a []string, b []string) {
+		// Trim common prefix and suffix, because edits by hcledit are usually
+		// local. It also makes the base cases below simple.
+		prefix := 0
+		for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+			suffix++
+		}
+
+		for _, l := range a[:prefix] {
+			ops = append(ops, diffOp{kind: ' ', line: l})
+		}
+
+		x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+		switch {
+		case len(x) == 0:
+			for _, l := range y {
+				ops = append(ops, diffOp{kind: '+', line: l})
+			}
+		case len(y) == 0:
+			for _, l := range x {
+				ops = append(ops, diffOp{kind: '-', line: l})
+			}
+		default:
+			// Both are not empty and differ at the first and the last lines,
+			// so the number of edits is at least 2 and each half has less.
+			x0, y0, x1, y1 := middleSnake(x, y)
+			walk(x[:x0], y[:y0])
+			for _, l := range x[x0:x1] {
+				ops = append(ops, diffOp{kind: ' ', line: l})
+			}
+			walk(x[x1:], y[y1:])
+		}
+
+		for _, l := range a[len(a)-suffix:] {
+			ops = append(ops, diffOp{kind: ' ', line: l})
+		}
+	}
+
+	walk(a, b)
+	return ops
+}
+
+// middleSnake returns the middle snake of the shortest edit script which
+// converts a into b, as a range from (x0, y0) to (x1, y1), where x is an index
+// of a and y is an index of b. It searches the edit graph from both ends at
+// the same time until they overlap, and uses only O(N+M) space.
+func middleSnake(a []string, b []string) (int, int, int, int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	max := (n + m + 1) / 2
+	offset := max + 1
+	// forward[k] is the furthest x on the diagonal k = x - y from the start,
+	// and backward[k] is the furthest x on the diagonal k from the end,
+	// where x and y are counted from the ends of a and b.
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+
+	for d := 0; d <= max; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// The diagonal k from the start is delta - k from the end.
+			if delta%2 != 0 && delta-k >= -(d-1) && delta-k <= d-1 && x+backward[offset+delta-k] >= n {
+				return x0, y0, x, y
+			}
+		}
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			x0, y0 := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if delta%2 == 0 && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - x0, m - y0
+			}
+		}
+	}
+
+	// unreachable, because the paths always overlap within max steps.
+	return 0, 0, 0, 0
+}
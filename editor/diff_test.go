@@ -0,0 +1,269 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "no change",
+			a:    "a\nb\n",
+			b:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "change in the middle",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: `--- a/test
++++ b/test
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			name: "separated changes",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			want: `--- a/test
++++ b/test
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -9,4 +9,4 @@
+ 9
+ 10
+ 11
+-12
++twelve
+`,
+		},
+		{
+			name: "insert into empty",
+			a:    "",
+			b:    "a\n",
+			want: `--- a/test
++++ b/test
+@@ -0,0 +1 @@
++a
+`,
+		},
+		{
+			name: "no newline at end of file",
+			a:    "a\nb",
+			b:    "a\nc\n",
+			want: `--- a/test
++++ b/test
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++c
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(unifiedDiff("test", []byte(tc.a), []byte(tc.b)))
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffLargeInput(t *testing.T) {
+	// Edits near the start and the end of a large file can not be trimmed as
+	// a common prefix or suffix, so a table of the whole file would be too large.
+	n := 200000
+	var a, b strings.Builder
+	for i := 0; i < n; i++ {
+		line := fmt.Sprintf("a%d = v%d\n", i, i)
+		a.WriteString(line)
+		switch i {
+		case 1:
+			b.WriteString("a1 = changed\n")
+		case n - 2:
+			b.WriteString("added = true\n")
+			b.WriteString(line)
+		default:
+			b.WriteString(line)
+		}
+	}
+
+	got := string(unifiedDiff("test", []byte(a.String()), []byte(b.String())))
+	want := fmt.Sprintf(`--- a/test
++++ b/test
+@@ -1,5 +1,5 @@
+ a0 = v0
+-a1 = v1
++a1 = changed
+ a2 = v2
+ a3 = v3
+ a4 = v4
+@@ -%d,5 +%d,6 @@
+ a%d = v%d
+ a%d = v%d
+ a%d = v%d
++added = true
+ a%d = v%d
+ a%d = v%d
+`, n-4, n-4, n-5, n-5, n-4, n-4, n-3, n-3, n-2, n-2, n-1, n-1)
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiffLinesShortest(t *testing.T) {
+	// editDistance returns the number of inserted and deleted lines of the
+	// shortest edit script by a naive dynamic programming for comparison.
+	editDistance := func(a []string, b []string) int {
+		d := make([][]int, len(a)+1)
+		for i := range d {
+			d[i] = make([]int, len(b)+1)
+			d[i][0] = i
+		}
+		for j := range d[0] {
+			d[0][j] = j
+		}
+		for i := 1; i <= len(a); i++ {
+			for j := 1; j <= len(b); j++ {
+				if a[i-1] == b[j-1] {
+					d[i][j] = d[i-1][j-1]
+				} else if d[i-1][j] < d[i][j-1] {
+					d[i][j] = d[i-1][j] + 1
+				} else {
+					d[i][j] = d[i][j-1] + 1
+				}
+			}
+		}
+		return d[len(a)][len(b)]
+	}
+
+	r := rand.New(rand.NewSource(1))
+	gen := func() []string {
+		lines := make([]string, r.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + r.Intn(3)))
+		}
+		return lines
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := gen(), gen()
+		ops := diffLines(a, b)
+
+		var gotA, gotB []string
+		edits := 0
+		for _, op := range ops {
+			if op.kind != '+' {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("invalid edit script for a = %v, b = %v: %v", a, b, ops)
+		}
+		if want := editDistance(a, b); edits != want {
+			t.Fatalf("got %d edits, want %d for a = %v, b = %v: %v", edits, want, a, b, ops)
+		}
+	}
+}
+
+func TestEditorApplyDiff(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "simple",
+			src: `a0 = v0
+a1 = v1
+`,
+			address: "a0",
+			value:   "v2",
+			ok:      true,
+			want: `--- a/test
++++ b/test
+@@ -1,2 +1,2 @@
+-a0 = v0
++a0 = v2
+ a1 = v1
+`,
+		},
+		{
+			name: "no match",
+			src: `a0 = v0
+`,
+			address: "hoge",
+			value:   "v2",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "parse error",
+			src:     `a0 = `,
+			address: "a0",
+			value:   "v2",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &Editor{
+				source: &parser{filename: "test"},
+				filters: []Filter{
+					&attributeSet{address: tc.address, value: tc.value},
+				},
+				sink: &formater{},
+			}
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := e.ApplyDiff(inStream, outStream, "test")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
 )
 
 // Editor assembles a pipeline to edit HCL.
@@ -11,37 +13,180 @@ type Editor struct {
 	source  Source
 	filters []Filter
 	sink    Sink
+	// indent is an indentation unit of the output.
+	// An empty string means the default of the formatter.
+	indent string
+	// normalizeNewlines is a flag to remove redundant blank lines of the output.
+	normalizeNewlines bool
+	// stream is a flag to process the input in streaming mode.
+	stream bool
+	// streamAddress is an address of the operation, which is used for
+	// skipping chunks in streaming mode. An empty string means that the
+	// operation doesn't support streaming.
+	streamAddress string
+	// jsonEdit is an operation for an input in the JSON syntax.
+	// If nil, the operation doesn't support the JSON syntax.
+	jsonEdit func(src []byte) ([]byte, error)
+	// get is a set of options for operations which get values.
+	get getOptions
+	// getValues is a flag which indicates that the operation gets values and
+	// accepts options for getting values such as WithRaw.
+	getValues bool
+	// err is an error of a given option which doesn't apply to the operation.
+	// It is returned by Apply instead of ignoring the option silently.
+	err error
+}
+
+// Option is a functional option to customize Editor.
+type Option func(*Editor)
+
+// NewEditor returns a new Editor customized by given options.
+// By default, it parses the input as HCL, applies no filters, and writes the
+// result formatted, so it works as a formatter.
+// This allows library consumers to assemble a custom pipeline of their own
+// filters and sinks with the built-in source.
+func NewEditor(opts ...Option) *Editor {
+	e := &Editor{
+		source:  &parser{filename: "-"},
+		filters: []Filter{},
+		sink:    &formater{},
+		// a custom sink such as NewJSONSink may write values of attributes.
+		getValues: true,
+	}
+	e.setOptions(opts)
+
+	return e
+}
+
+// WithFilters returns an Option to append given filters to the pipeline.
+// Filters are applied in order.
+func WithFilters(filters ...Filter) Option {
+	return func(e *Editor) {
+		e.filters = append(e.filters, filters...)
+	}
+}
+
+// WithSink returns an Option to replace the sink of the pipeline.
+func WithSink(sink Sink) Option {
+	return func(e *Editor) {
+		e.sink = sink
+	}
+}
+
+// WithFilename returns an Option to set a filename of the input.
+// Note that a filename is used only for an error message.
+func WithFilename(filename string) Option {
+	return func(e *Editor) {
+		e.source = &parser{filename: filename}
+	}
+}
+
+// setOptions applies given options to the editor.
+func (e *Editor) setOptions(opts []Option) {
+	for _, opt := range opts {
+		opt(e)
+	}
 }
 
 // Apply reads an input stream, applies some filters, and writes an output stream.
 // The input and output streams contain arbitrary string (maybe HCL or not).
 func (e *Editor) Apply(r io.Reader, w io.Writer) error {
+	if e.stream {
+		return e.applyStream(r, w)
+	}
+
 	input, err := ioutil.ReadAll(r)
 	if err != nil {
 		return fmt.Errorf("failed to read input: %s", err)
 	}
 
-	inFile, err := e.source.Source(input)
+	out, err := e.apply(input)
 	if err != nil {
 		return err
 	}
 
-	tmpFile := inFile
-	for _, filter := range e.filters {
-		tmpFile, err = filter.Filter(tmpFile)
-		if err != nil {
-			return err
-		}
+	if _, err := w.Write(out); err != nil {
+		return fmt.Errorf("failed to write output: %s", err)
 	}
 
-	out, err := e.sink.Sink(tmpFile)
+	return nil
+}
+
+// ApplyDiff reads an input stream, applies some filters, and writes a
+// unified diff between the input and the result to an output stream instead
+// of the result itself. If nothing changed, nothing is written.
+// Note that a filename is used only for headers of the diff.
+func (e *Editor) ApplyDiff(r io.Reader, w io.Writer, filename string) error {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %s", err)
+	}
+
+	out, err := e.apply(input)
 	if err != nil {
 		return err
 	}
 
-	if _, err := w.Write(out); err != nil {
+	diff := unifiedDiff(filename, input, out)
+	if _, err := w.Write(diff); err != nil {
 		return fmt.Errorf("failed to write output: %s", err)
 	}
 
 	return nil
 }
+
+// apply applies some filters to a given input and returns the output of sink.
+func (e *Editor) apply(input []byte) ([]byte, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if isJSONSyntax(e.filename(), input) {
+		if e.jsonEdit == nil {
+			return nil, fmt.Errorf("the JSON syntax is not supported by this operation")
+		}
+		return e.jsonEdit(input)
+	}
+
+	inFile, err := e.source.Source(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.applyFile(inFile)
+}
+
+// filename returns a filename of the input if known.
+func (e *Editor) filename() string {
+	if p, ok := e.source.(*parser); ok {
+		return p.filename
+	}
+	return ""
+}
+
+// applyFile applies some filters to a given file and returns the output of sink.
+func (e *Editor) applyFile(inFile *hclwrite.File) ([]byte, error) {
+	ctx := newSinkContext()
+	ctx.get = e.get
+	if _, ok := e.sink.(contextSink); ok {
+		// record source ranges before filters rebuild the tree.
+		ctx.ranges.filename = e.filename()
+		ctx.ranges.record(inFile)
+	}
+
+	tmpFile, err := MultiFilter(e.filters).Filter(inFile)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := sinkWithContext(e.sink, tmpFile, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.normalizeNewlines {
+		out = normalizeNewlines(out)
+	}
+
+	return reindent(out, e.indent), nil
+}
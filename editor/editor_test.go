@@ -0,0 +1,212 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// appendAttributeFilter is a custom filter for testing, which appends a given
+// attribute to the top level body.
+type appendAttributeFilter struct {
+	name  string
+	value string
+}
+
+func (f *appendAttributeFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	inFile.Body().SetAttributeTraversal(f.name, hcl.Traversal{
+		hcl.TraverseRoot{Name: f.value},
+	})
+	return inFile, nil
+}
+
+// failFilter is a custom filter for testing, which always fails.
+type failFilter struct{}
+
+func (f *failFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	return nil, fmt.Errorf("failed")
+}
+
+func TestNewEditor(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		opts   []Option
+		ok     bool
+		errMsg string
+		want   string
+	}{
+		{
+			name: "default formats input",
+			src: `a0   =   v0
+`,
+			opts: []Option{},
+			ok:   true,
+			want: `a0 = v0
+`,
+		},
+		{
+			name: "with filters in order",
+			src: `a0 = v0
+`,
+			opts: []Option{
+				WithFilters(&appendAttributeFilter{name: "a1", value: "v1"}),
+				WithFilters(&appendAttributeFilter{name: "a2", value: "v2"}),
+			},
+			ok: true,
+			want: `a0 = v0
+a1 = v1
+a2 = v2
+`,
+		},
+		{
+			name: "with sink",
+			src: `b1 "l1" {
+}
+b2 {
+}
+`,
+			opts: []Option{
+				WithSink(&blockList{}),
+			},
+			ok: true,
+			want: `b1.l1
+b2
+`,
+		},
+		{
+			name: "with options for getting values",
+			src: `a0 = "v0"
+`,
+			opts: []Option{
+				WithSink(NewTemplateSink(template.Must(template.New("test").Parse("{{.Address}}={{.Value}}\n")))),
+				WithRaw(),
+			},
+			ok: true,
+			want: `a0=v0
+`,
+		},
+		{
+			name: "with filename",
+			src: `a0 = 
+`,
+			opts: []Option{
+				WithFilename("foo.hcl"),
+			},
+			ok:     false,
+			errMsg: "foo.hcl",
+			want:   "",
+		},
+		{
+			name: "filter error",
+			src: `a0 = v0
+`,
+			opts: []Option{
+				WithFilters(&failFilter{}),
+			},
+			ok:     false,
+			errMsg: "failed",
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := NewEditor(tc.opts...).Apply(inStream, outStream)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+				}
+				if !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected an error message containing %q, but got: %s", tc.errMsg, err)
+				}
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetOptionsNotSupported(t *testing.T) {
+	src := `a0 = v0
+b1 {
+  a1 = v1
+}
+`
+
+	cases := []struct {
+		name  string
+		apply func(r io.Reader, w io.Writer) error
+		want  string
+	}{
+		{
+			name: "set",
+			apply: func(r io.Reader, w io.Writer) error {
+				return SetAttribute(r, w, "test", "a0", "v2", WithRaw())
+			},
+			want: "WithRaw is not supported by this operation",
+		},
+		{
+			name: "remove",
+			apply: func(r io.Reader, w io.Writer) error {
+				return RemoveAttribute(r, w, "test", "a0", WithVars(map[string]string{"a0": "v2"}))
+			},
+			want: "WithVars is not supported by this operation",
+		},
+		{
+			name: "move",
+			apply: func(r io.Reader, w io.Writer) error {
+				return MoveAttribute(r, w, "test", "a0", "a2", WithEvaluate(), WithComments())
+			},
+			want: "WithEvaluate is not supported by this operation",
+		},
+		{
+			name: "stream",
+			apply: func(r io.Reader, w io.Writer) error {
+				return SetAttribute(r, w, "test", "b1.a1", "v2", WithStream(), WithComments())
+			},
+			want: "WithComments is not supported by this operation",
+		},
+		{
+			name: "get from all",
+			apply: func(r io.Reader, w io.Writer) error {
+				return GetAttributeFromAll(r, w, "test", "b1.a1", false, WithStrict())
+			},
+			want: "WithStrict is not supported by this operation",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := tc.apply(inStream, outStream)
+			if err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", outStream.String())
+			}
+
+			if err.Error() != tc.want {
+				t.Fatalf("got error: %s, want: %s", err, tc.want)
+			}
+
+			if got := outStream.String(); got != "" {
+				t.Fatalf("expected to write nothing, but got: %s", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package editor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NotFoundError is an error which indicates that nothing matches a given
+// address. It is returned only when the caller requires a match.
+type NotFoundError struct {
+	// Address is an address which doesn't match anything.
+	Address string
+}
+
+// Error returns an error message.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("not found: %s", e.Address)
+}
+
+// AmbiguousError is an error which indicates that a given address matches
+// multiple items where only one is expected. It is returned only when the
+// caller requires a unique match, or the address contains a recursive descent
+// (**) whose matches cannot be told apart otherwise.
+type AmbiguousError struct {
+	// Address is an address which matches multiple items.
+	Address string
+	// Matches is a list of full addresses of matched items with their
+	// positions in the form of address (line:column).
+	Matches []string
+}
+
+// Error returns an error message.
+func (e *AmbiguousError) Error() string {
+	return fmt.Sprintf("ambiguous: %s matches %d items: %s", e.Address, len(e.Matches), strings.Join(e.Matches, ", "))
+}
+
+// FileError is an error which occurs while processing a file.
+type FileError struct {
+	// Filename is a name of the file.
+	Filename string
+	// Err is an underlying error.
+	Err error
+}
+
+// Error returns an error message.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Filename, e.Err)
+}
+
+// Unwrap returns an underlying error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// FilesError is an error which collects errors for multiple files.
+type FilesError struct {
+	// Errors is a list of errors for each file in order.
+	Errors []*FileError
+}
+
+// Error returns an error message.
+func (e *FilesError) Error() string {
+	msgs := []string{}
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("failed to process %d file(s):\n%s", len(e.Errors), strings.Join(msgs, "\n"))
+}
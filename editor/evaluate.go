@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// evaluateExpression evaluates a given expression as a constant expression
+// and returns the resulting value in the HCL native syntax.
+// A value in vars can be referred as var.NAME in the expression, and each
+// value in vars is evaluated as a constant expression as well.
+// Functions are not available.
+func evaluateExpression(expr string, vars map[string]string) (string, error) {
+	ctx, err := newEvalContext(vars)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, diags := parseValueExpression(expr)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to parse expression: %s", diags)
+	}
+
+	v, diags := parsed.Value(ctx)
+	if diags.HasErrors() {
+		return "", fmt.Errorf("failed to evaluate expression: %s", diags)
+	}
+
+	if !v.IsWhollyKnown() {
+		return "", fmt.Errorf("failed to evaluate expression: the value is unknown: %s", expr)
+	}
+
+	return strings.TrimSpace(string(hclwrite.TokensForValue(v).Bytes())), nil
+}
+
+// newEvalContext returns an EvalContext with variables in vars.
+// If vars is empty, it returns an EvalContext without any variables.
+func newEvalContext(vars map[string]string) (*hcl.EvalContext, error) {
+	ctx := &hcl.EvalContext{}
+	if len(vars) == 0 {
+		return ctx, nil
+	}
+
+	// sort names for a stable error message.
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := make(map[string]cty.Value)
+	for _, name := range names {
+		parsed, diags := hclsyntax.ParseExpression([]byte(vars[name]), "", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse var %s: %s", name, diags)
+		}
+		v, diags := parsed.Value(nil)
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to evaluate var %s: %s", name, diags)
+		}
+		values[name] = v
+	}
+
+	ctx.Variables = map[string]cty.Value{
+		"var": cty.ObjectVal(values),
+	}
+
+	return ctx, nil
+}
@@ -0,0 +1,308 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteFileAtomic writes data to a given file through a temporary file in the
+// same directory and renames it, so that the file is never left partially
+// written. The permission of the existing file is kept.
+// If backupSuffix is not empty, the original contents are saved to a file
+// whose name is the filename followed by the suffix before replacing.
+func WriteFileAtomic(filename string, data []byte, backupSuffix string) error {
+	return writeFileAtomic(filename, backupSuffix, func(w io.Writer) error {
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write temporary file: %s", err)
+		}
+		return nil
+	})
+}
+
+// writeFileAtomic is the same as WriteFileAtomic, but calls a given function
+// to write contents to the temporary file, so that they can be streamed
+// without holding them in memory. An error of the function is returned as it
+// is, and the file is not replaced.
+func writeFileAtomic(filename string, backupSuffix string, write func(w io.Writer) error) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fmt.Errorf("failed to update file: %s", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %s", err)
+	}
+	// remove the temporary file if something goes wrong before renaming.
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permission of temporary file: %s", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %s", err)
+	}
+
+	// save the original contents only if the new contents are written.
+	if len(backupSuffix) != 0 {
+		if err := copyFile(filename, filename+backupSuffix, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write backup file: %s", err)
+		}
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to update file: %s", err)
+	}
+
+	return nil
+}
+
+// copyFile copies contents of a file to another file with a given permission.
+func copyFile(src string, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// hclExtensions is a list of file extensions which are found recursively in
+// directories by FindFiles.
+var hclExtensions = []string{".tf", ".hcl"}
+
+// FindFiles returns a list of files matched by given glob patterns and files
+// with the extension of .tf or .hcl found recursively in given directories.
+// The patterns are expanded in the given order, and files in a directory are
+// sorted in lexical order. Each file appears only once.
+// It returns an error if a pattern matches nothing, so that typos are not
+// silently ignored.
+func FindFiles(patterns []string, dirs []string) ([]string, error) {
+	files := []string{}
+	seen := make(map[string]bool)
+	add := func(f string) {
+		f = filepath.Clean(f)
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand file pattern: %s: %s", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no such file: %s", pattern)
+		}
+		for _, m := range matches {
+			add(m)
+		}
+	}
+
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if path != dir && strings.HasPrefix(info.Name(), ".") {
+					// skip hidden directories such as .git and .terraform.
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			for _, ext := range hclExtensions {
+				if filepath.Ext(path) == ext {
+					add(path)
+					break
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find files in directory: %s: %s", dir, err)
+		}
+	}
+
+	return files, nil
+}
+
+// ApplyFiles runs a given function for each file with a reader of its
+// contents and a writer, so that the same editing operation can be applied
+// to multiple files. The function receives the filename for error messages.
+// If update is true, the result is written back to each file by
+// WriteFileAtomic only if changed. Otherwise results are written to w in order.
+// While updating, each file is locked with an advisory lock such as flock, so
+// that concurrent updates of the same file by other processes are serialized
+// instead of losing changes.
+// Errors are collected per file as a FilesError, and the remaining files are
+// processed.
+func ApplyFiles(filenames []string, w io.Writer, update bool, backupSuffix string, f func(r io.Reader, w io.Writer, filename string) error) error {
+	return ApplyFilesParallel(filenames, w, update, backupSuffix, 1, f)
+}
+
+// ApplyFilesParallel is the same as ApplyFiles, but processes files
+// concurrently with a bounded pool of a given number of workers.
+// Results and errors are reported in the order of filenames regardless of
+// the order of completion, so the output is the same as ApplyFiles.
+// Note that the function is called concurrently, so it must be safe for
+// concurrent use. If workers is less than 1, it is treated as 1.
+func ApplyFilesParallel(filenames []string, w io.Writer, update bool, backupSuffix string, workers int, f func(r io.Reader, w io.Writer, filename string) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type result struct {
+		out  []byte
+		err  error
+		done chan struct{}
+	}
+	results := make([]*result, len(filenames))
+	for i := range results {
+		results[i] = &result{done: make(chan struct{})}
+	}
+
+	queue := make(chan int)
+	go func() {
+		for i := range filenames {
+			queue <- i
+		}
+		close(queue)
+	}()
+
+	for n := 0; n < workers; n++ {
+		go func() {
+			for i := range queue {
+				res := results[i]
+				res.out, res.err = applyFile(filenames[i], update, backupSuffix, f)
+				close(res.done)
+			}
+		}()
+	}
+
+	errs := []*FileError{}
+	for i, filename := range filenames {
+		res := results[i]
+		<-res.done
+		err := res.err
+		if err == nil && !update {
+			if _, werr := w.Write(res.out); werr != nil {
+				err = fmt.Errorf("failed to write output: %s", werr)
+			}
+		}
+		if err != nil {
+			errs = append(errs, &FileError{Filename: filename, Err: err})
+		}
+	}
+
+	if len(errs) != 0 {
+		return &FilesError{Errors: errs}
+	}
+
+	return nil
+}
+
+// ApplyFilesStream is the same as ApplyFiles, but streams each file to w or
+// a temporary file instead of reading it into memory, which is intended to be
+// used with WithStream for very large files. Files are processed one by one.
+// If update is true, the file is replaced even if not changed, because the
+// result is not compared with the original contents.
+// Note that unlike ApplyFiles, a part of the output may be written to w if an
+// error occurs.
+func ApplyFilesStream(filenames []string, w io.Writer, update bool, backupSuffix string, f func(r io.Reader, w io.Writer, filename string) error) error {
+	errs := []*FileError{}
+	for _, filename := range filenames {
+		if err := streamFile(filename, w, update, backupSuffix, f); err != nil {
+			errs = append(errs, &FileError{Filename: filename, Err: err})
+		}
+	}
+
+	if len(errs) != 0 {
+		return &FilesError{Errors: errs}
+	}
+
+	return nil
+}
+
+// streamFile runs a given function for a single file with a reader of the
+// file. If update is true, the output is written back to the file through a
+// temporary file. Otherwise it is written to w. See ApplyFilesStream.
+func streamFile(filename string, w io.Writer, update bool, backupSuffix string, f func(r io.Reader, w io.Writer, filename string) error) error {
+	if update {
+		unlock, err := lockFile(filename)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	in, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %s", err)
+	}
+	defer in.Close()
+
+	if !update {
+		return f(in, w, filename)
+	}
+
+	return writeFileAtomic(filename, backupSuffix, func(tmp io.Writer) error {
+		return f(in, tmp, filename)
+	})
+}
+
+// applyFile runs a given function for a single file, and returns the output.
+// If update is true, the output is written back to the file instead, and
+// nothing is returned. See ApplyFiles.
+// While updating, the file is locked from other processes updating it.
+func applyFile(filename string, update bool, backupSuffix string, f func(r io.Reader, w io.Writer, filename string) error) ([]byte, error) {
+	if update {
+		unlock, err := lockFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer unlock()
+	}
+
+	input, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %s", err)
+	}
+
+	var out bytes.Buffer
+	if err := f(bytes.NewReader(input), &out, filename); err != nil {
+		return nil, err
+	}
+
+	if !update {
+		return out.Bytes(), nil
+	}
+
+	if bytes.Equal(input, out.Bytes()) {
+		return nil, nil
+	}
+
+	return nil, WriteFileAtomic(filename, out.Bytes(), backupSuffix)
+}
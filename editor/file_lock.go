@@ -0,0 +1,51 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+)
+
+// lockFile acquires an exclusive advisory lock of a given file, which blocks
+// until other processes release it, and returns a function to release it.
+// This prevents concurrent read-modify-write of the same file, such as
+// parallel CI jobs editing a shared tfvars file, from losing updates.
+// Note that WriteFileAtomic replaces the file with a new one, so it checks the
+// locked file is still at the path after acquiring the lock, and otherwise
+// retries with the new one.
+// It is a no-op on platforms which don't support file locking.
+func lockFile(filename string) (func(), error) {
+	for {
+		f, err := openLockFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file for locking: %s", err)
+		}
+
+		if err := lockFD(f); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock file: %s: %s", filename, err)
+		}
+
+		unlock := func() {
+			_ = unlockFD(f)
+			f.Close()
+		}
+
+		locked, err := f.Stat()
+		if err != nil {
+			unlock()
+			return nil, fmt.Errorf("failed to lock file: %s: %s", filename, err)
+		}
+		current, err := os.Stat(filename)
+		if err != nil {
+			unlock()
+			return nil, fmt.Errorf("failed to lock file: %s: %s", filename, err)
+		}
+
+		if os.SameFile(locked, current) {
+			return unlock, nil
+		}
+
+		// the file was replaced while waiting the lock.
+		unlock()
+	}
+}
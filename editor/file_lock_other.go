@@ -0,0 +1,23 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+package editor
+
+import (
+	"os"
+)
+
+// openLockFile opens a given file to be locked.
+func openLockFile(filename string) (*os.File, error) {
+	return os.Open(filename)
+}
+
+// lockFD does nothing because file locking is not supported on this platform.
+func lockFD(f *os.File) error {
+	return nil
+}
+
+// unlockFD does nothing because file locking is not supported on this platform.
+func unlockFD(f *os.File) error {
+	return nil
+}
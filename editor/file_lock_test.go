@@ -0,0 +1,67 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows
+// +build darwin dragonfly freebsd linux netbsd openbsd windows
+
+package editor
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestApplyFilesLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "counter.tfvars")
+	if err := ioutil.WriteFile(path, []byte("count = 0\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	// increment reads the current count and writes the next one, which loses
+	// updates if concurrent read-modify-write of the file are not serialized.
+	increment := func(r io.Reader, w io.Writer, filename string) error {
+		input, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(string(input), "count =")))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "count = %d\n", n+1)
+		return err
+	}
+
+	// Each ApplyFiles opens the file on its own, so concurrent calls behave as
+	// separate processes with respect to flock.
+	n := 20
+	errCh := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			errCh <- ApplyFiles([]string{path}, ioutil.Discard, true, "", increment)
+		}()
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errCh; err != nil {
+			t.Fatalf("unexpected err = %s", err)
+		}
+	}
+
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %s", err)
+	}
+	want := fmt.Sprintf("count = %d\n", n)
+	if string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", string(got), want)
+	}
+}
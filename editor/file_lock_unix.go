@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package editor
+
+import (
+	"os"
+	"syscall"
+)
+
+// openLockFile opens a given file to be locked.
+func openLockFile(filename string) (*os.File, error) {
+	return os.Open(filename)
+}
+
+// lockFD acquires an exclusive lock of a given file with flock(2).
+func lockFD(f *os.File) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+// unlockFD releases a lock of a given file.
+func unlockFD(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,61 @@
+//go:build windows
+// +build windows
+
+package editor
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+// lockfileExclusiveLock is a flag of LockFileEx to request an exclusive lock.
+const lockfileExclusiveLock = 0x00000002
+
+// lockOffset is an offset of a locked byte range. A lock of the LockFileEx is
+// mandatory, so we lock a range far beyond the end of file so as not to block
+// reading the contents.
+const lockOffset = ^uint32(0)
+
+// openLockFile opens a given file to be locked.
+// The file is opened with FILE_SHARE_DELETE so that it can be replaced by
+// WriteFileAtomic while locked.
+func openLockFile(filename string) (*os.File, error) {
+	path, err := syscall.UTF16PtrFromString(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := syscall.CreateFile(path, syscall.GENERIC_READ, syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil, syscall.OPEN_EXISTING, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: err}
+	}
+
+	return os.NewFile(uintptr(h), filename), nil
+}
+
+// lockFD acquires an exclusive lock of a given file with LockFileEx.
+func lockFD(f *os.File) error {
+	ol := &syscall.Overlapped{Offset: lockOffset, OffsetHigh: lockOffset}
+	r, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// unlockFD releases a lock of a given file.
+func unlockFD(f *os.File) error {
+	ol := &syscall.Overlapped{Offset: lockOffset, OffsetHigh: lockOffset}
+	r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,385 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	cases := []struct {
+		name         string
+		create       bool
+		backupSuffix string
+		ok           bool
+	}{
+		{
+			name:         "simple",
+			create:       true,
+			backupSuffix: "",
+			ok:           true,
+		},
+		{
+			name:         "with backup",
+			create:       true,
+			backupSuffix: ".bak",
+			ok:           true,
+		},
+		{
+			name:         "file not found",
+			create:       false,
+			backupSuffix: "",
+			ok:           false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hcledit")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			filename := filepath.Join(dir, "main.tf")
+			if tc.create {
+				if err := ioutil.WriteFile(filename, []byte("a0 = v0\n"), 0600); err != nil {
+					t.Fatalf("failed to write file: %s", err)
+				}
+			}
+
+			err = WriteFileAtomic(filename, []byte("a0 = v1\n"), tc.backupSuffix)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error")
+				}
+				return
+			}
+
+			got, err := ioutil.ReadFile(filename)
+			if err != nil {
+				t.Fatalf("failed to read file: %s", err)
+			}
+			if string(got) != "a0 = v1\n" {
+				t.Fatalf("got:\n%s\nwant:\n%s", string(got), "a0 = v1\n")
+			}
+
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatalf("failed to stat file: %s", err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Fatalf("permission is not kept: %s", info.Mode().Perm())
+			}
+
+			if len(tc.backupSuffix) != 0 {
+				backup, err := ioutil.ReadFile(filename + tc.backupSuffix)
+				if err != nil {
+					t.Fatalf("failed to read backup file: %s", err)
+				}
+				if string(backup) != "a0 = v0\n" {
+					t.Fatalf("got backup:\n%s\nwant:\n%s", string(backup), "a0 = v0\n")
+				}
+			}
+
+			files, err := ioutil.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("failed to read dir: %s", err)
+			}
+			want := 1
+			if len(tc.backupSuffix) != 0 {
+				want = 2
+			}
+			if len(files) != want {
+				t.Fatalf("temporary file is left: %d files", len(files))
+			}
+		})
+	}
+}
+
+func TestFindFiles(t *testing.T) {
+	cases := []struct {
+		name     string
+		patterns []string
+		dirs     []string
+		ok       bool
+		want     []string
+	}{
+		{
+			name:     "files",
+			patterns: []string{"b.tf", "a.tf"},
+			dirs:     []string{},
+			ok:       true,
+			want:     []string{"b.tf", "a.tf"},
+		},
+		{
+			name:     "glob",
+			patterns: []string{"*.tf"},
+			dirs:     []string{},
+			ok:       true,
+			want:     []string{"a.tf", "b.tf"},
+		},
+		{
+			name:     "recursive",
+			patterns: []string{},
+			dirs:     []string{"."},
+			ok:       true,
+			want:     []string{"a.tf", "b.tf", "sub/c.hcl", "sub/d.tf"},
+		},
+		{
+			name:     "duplicated",
+			patterns: []string{"b.tf"},
+			dirs:     []string{"."},
+			ok:       true,
+			want:     []string{"b.tf", "a.tf", "sub/c.hcl", "sub/d.tf"},
+		},
+		{
+			name:     "no match",
+			patterns: []string{"*.hcl"},
+			dirs:     []string{},
+			ok:       false,
+			want:     nil,
+		},
+		{
+			name:     "dir not found",
+			patterns: []string{},
+			dirs:     []string{"notfound"},
+			ok:       false,
+			want:     nil,
+		},
+	}
+
+	dir, err := ioutil.TempDir("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, f := range []string{"a.tf", "b.tf", "README.md", "sub/c.hcl", "sub/d.tf", ".terraform/e.tf"} {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("failed to create dir: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(""), 0600); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			patterns := []string{}
+			for _, p := range tc.patterns {
+				patterns = append(patterns, filepath.Join(dir, p))
+			}
+			dirs := []string{}
+			for _, d := range tc.dirs {
+				dirs = append(dirs, filepath.Join(dir, d))
+			}
+
+			got, err := FindFiles(patterns, dirs)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error, got: %#v", got)
+				}
+				return
+			}
+
+			want := []string{}
+			for _, f := range tc.want {
+				want = append(want, filepath.Join(dir, f))
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got: %#v, want: %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyFiles(t *testing.T) {
+	cases := []struct {
+		name    string
+		update  bool
+		workers int
+		ok      bool
+		stdout  string
+		files   []string
+	}{
+		{
+			name:    "stdout",
+			update:  false,
+			workers: 1,
+			ok:      false,
+			stdout:  "a0 = v1\na0 = v1\n",
+			files:   []string{"a0 = v0\n", "a0 = v0\n", "a0 = \n"},
+		},
+		{
+			name:    "update",
+			update:  true,
+			workers: 1,
+			ok:      false,
+			stdout:  "",
+			files:   []string{"a0 = v1\n", "a0 = v1\n", "a0 = \n"},
+		},
+		{
+			name:    "parallel stdout in order",
+			update:  false,
+			workers: 3,
+			ok:      false,
+			stdout:  "a0 = v1\na0 = v1\n",
+			files:   []string{"a0 = v0\n", "a0 = v0\n", "a0 = \n"},
+		},
+		{
+			name:    "parallel update",
+			update:  true,
+			workers: 2,
+			ok:      false,
+			stdout:  "",
+			files:   []string{"a0 = v1\n", "a0 = v1\n", "a0 = \n"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hcledit")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			filenames := []string{}
+			for i, src := range []string{"a0 = v0\n", "a0 = v0\n", "a0 = \n"} {
+				filename := filepath.Join(dir, fmt.Sprintf("%d.tf", i))
+				if err := ioutil.WriteFile(filename, []byte(src), 0600); err != nil {
+					t.Fatalf("failed to write file: %s", err)
+				}
+				filenames = append(filenames, filename)
+			}
+
+			var stdout bytes.Buffer
+			err = ApplyFilesParallel(filenames, &stdout, tc.update, "", tc.workers, func(r io.Reader, w io.Writer, filename string) error {
+				return SetAttribute(r, w, filename, "a0", "v1")
+			})
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if stdout.String() != tc.stdout {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout.String(), tc.stdout)
+			}
+
+			for i, want := range tc.files {
+				got, err := ioutil.ReadFile(filenames[i])
+				if err != nil {
+					t.Fatalf("failed to read file: %s", err)
+				}
+				if string(got) != want {
+					t.Fatalf("got file %d:\n%s\nwant:\n%s", i, string(got), want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyFilesStream(t *testing.T) {
+	cases := []struct {
+		name         string
+		update       bool
+		backupSuffix string
+		ok           bool
+		stdout       string
+		files        []string
+		backups      []string
+	}{
+		{
+			name:   "stdout",
+			update: false,
+			ok:     false,
+			stdout: "a0 = v1\na0 = v1\n",
+			files:  []string{"a0 = v0\n", "a0 = v0\n", "a0 = \n"},
+		},
+		{
+			name:   "update",
+			update: true,
+			ok:     false,
+			stdout: "",
+			files:  []string{"a0 = v1\n", "a0 = v1\n", "a0 = \n"},
+		},
+		{
+			name:         "update with backup",
+			update:       true,
+			backupSuffix: ".bak",
+			ok:           false,
+			stdout:       "",
+			files:        []string{"a0 = v1\n", "a0 = v1\n", "a0 = \n"},
+			backups:      []string{"a0 = v0\n", "a0 = v0\n"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "hcledit")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			filenames := []string{}
+			for i, src := range []string{"a0 = v0\n", "a0 = v0\n", "a0 = \n"} {
+				filename := filepath.Join(dir, fmt.Sprintf("%d.tf", i))
+				if err := ioutil.WriteFile(filename, []byte(src), 0600); err != nil {
+					t.Fatalf("failed to write file: %s", err)
+				}
+				filenames = append(filenames, filename)
+			}
+
+			var stdout bytes.Buffer
+			err = ApplyFilesStream(filenames, &stdout, tc.update, tc.backupSuffix, func(r io.Reader, w io.Writer, filename string) error {
+				return SetAttribute(r, w, filename, "a0", "v1", WithStream())
+			})
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if stdout.String() != tc.stdout {
+				t.Fatalf("got stdout:\n%s\nwant:\n%s", stdout.String(), tc.stdout)
+			}
+
+			for i, want := range tc.files {
+				got, err := ioutil.ReadFile(filenames[i])
+				if err != nil {
+					t.Fatalf("failed to read file: %s", err)
+				}
+				if string(got) != want {
+					t.Fatalf("got file %d:\n%s\nwant:\n%s", i, string(got), want)
+				}
+			}
+
+			for i, want := range tc.backups {
+				got, err := ioutil.ReadFile(filenames[i] + tc.backupSuffix)
+				if err != nil {
+					t.Fatalf("failed to read backup file: %s", err)
+				}
+				if string(got) != want {
+					t.Fatalf("got backup file %d:\n%s\nwant:\n%s", i, string(got), want)
+				}
+			}
+		})
+	}
+}
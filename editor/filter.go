@@ -9,3 +9,34 @@ type Filter interface {
 	// Filter reads HCL and writes HCL
 	Filter(*hclwrite.File) (*hclwrite.File, error)
 }
+
+// FilterFunc is an adapter to use an ordinary function as a Filter.
+type FilterFunc func(*hclwrite.File) (*hclwrite.File, error)
+
+// Filter calls f(inFile).
+func (f FilterFunc) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	return f(inFile)
+}
+
+// MultiFilter is a Filter which chains multiple filters into a pipeline.
+// Filters are applied in order, and the output of each filter is passed to
+// the next one, so a custom filter can be inserted between built-in ones.
+// Note that a filter may modify a given file in place and return it, so a
+// later filter sees changes made by earlier filters.
+// If any of filters returns an error, the pipeline stops and returns the
+// error. An empty MultiFilter returns the input as it is.
+type MultiFilter []Filter
+
+// Filter applies filters in order.
+func (m MultiFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	tmpFile := inFile
+	for _, filter := range m {
+		var err error
+		tmpFile, err = filter.Filter(tmpFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpFile, nil
+}
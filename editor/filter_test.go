@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+func TestMultiFilter(t *testing.T) {
+	buildFilter := func(op Operation) Filter {
+		f, err := op.BuildFilter()
+		if err != nil {
+			t.Fatalf("failed to build filter: %s", err)
+		}
+		return f
+	}
+
+	// renameAll renames all attributes in the top level body with a given suffix.
+	renameAll := func(suffix string) Filter {
+		return FilterFunc(func(inFile *hclwrite.File) (*hclwrite.File, error) {
+			body := inFile.Body()
+			for name, attr := range body.Attributes() {
+				tokens := attr.Expr().BuildTokens(nil)
+				body.RemoveAttribute(name)
+				body.SetAttributeRaw(name+suffix, tokens)
+			}
+			return inFile, nil
+		})
+	}
+
+	cases := []struct {
+		name   string
+		src    string
+		filter MultiFilter
+		ok     bool
+		want   string
+	}{
+		{
+			name: "custom filter between built-in filters",
+			src: `a0 = v0
+`,
+			filter: MultiFilter{
+				buildFilter(Operation{Kind: OperationSet, Address: "a0", Value: "v1"}),
+				renameAll("_renamed"),
+				buildFilter(Operation{Kind: OperationAppendAttribute, Address: "a1", Value: "v2"}),
+			},
+			ok: true,
+			want: `a0_renamed = v1
+a1         = v2
+`,
+		},
+		{
+			name: "empty",
+			src: `a0 = v0
+`,
+			filter: MultiFilter{},
+			ok:     true,
+			want: `a0 = v0
+`,
+		},
+		{
+			name: "stop at error",
+			src: `a0 = v0
+`,
+			filter: MultiFilter{
+				FilterFunc(func(inFile *hclwrite.File) (*hclwrite.File, error) {
+					return nil, fmt.Errorf("failed")
+				}),
+				buildFilter(Operation{Kind: OperationSet, Address: "a0", Value: "v1"}),
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := NewEditor(WithFilters(tc.filter)).Apply(inStream, outStream)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package editor
+
+import (
+	"io"
+)
+
+// Format reads HCL from io.Reader, and writes the canonical formatting of
+// hclwrite to io.Writer without any edits.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func Format(r io.Reader, w io.Writer, filename string, opts ...Option) error {
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
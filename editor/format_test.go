@@ -0,0 +1,66 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		ok   bool
+		want string
+	}{
+		{
+			name: "simple",
+			src: `a0   =   v0
+b1   "l1"   {
+a1 = v1
+  a22 = v2
+}
+`,
+			ok: true,
+			want: `a0 = v0
+b1 "l1" {
+  a1  = v1
+  a22 = v2
+}
+`,
+		},
+		{
+			name: "already formatted",
+			src: `a0 = v0
+`,
+			ok: true,
+			want: `a0 = v0
+`,
+		},
+		{
+			name: "parse error",
+			src:  `a0 = `,
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := Format(inStream, outStream, "test")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// FragmentFunc is a function which transforms an HCL fragment of an attribute
+// or a block. It returns a new fragment, which may contain multiple items or
+// be empty to remove the original one.
+type FragmentFunc func(fragment string) (string, error)
+
+// FilterAttributeFragment reads HCL from io.Reader, and replaces matched
+// attributes at a given address with results of a given function, and writes
+// the updated HCL to io.Writer.
+// The function receives each matched attribute including comments as an HCL
+// fragment formatted on its own. This is intended for delegating a
+// transformation to an external program.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func FilterAttributeFragment(r io.Reader, w io.Writer, filename string, address string, fn FragmentFunc, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&tokensFilter{address: address, fn: fn.transform, find: findAttributeTokens},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// FilterBlockFragment reads HCL from io.Reader, and replaces matched top level
+// blocks at a given address with results of a given function, and writes the
+// updated HCL to io.Writer.
+// The function receives each matched block as an HCL fragment formatted on
+// its own. This is intended for delegating a transformation to an external
+// program.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func FilterBlockFragment(r io.Reader, w io.Writer, filename string, address string, fn FragmentFunc, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&tokensFilter{address: address, fn: fn.transform, find: findBlockTokens},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// transform converts tokens to a fragment, calls f, and converts the result
+// back to tokens. It returns an error if the result is not valid HCL.
+func (f FragmentFunc) transform(tokens hclwrite.Tokens) (hclwrite.Tokens, error) {
+	out, err := f(string(hclwrite.Format(tokens.Bytes())))
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := safeParseConfig([]byte(out), "generated_by_FragmentFunc", hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse a transformed fragment: %s", err)
+	}
+
+	transformed := trimEOF(file.BuildTokens(nil))
+	if len(transformed) == 0 {
+		return transformed, nil
+	}
+
+	return withTrailingNewline(transformed), nil
+}
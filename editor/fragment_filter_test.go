@@ -0,0 +1,200 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFilterAttributeFragment(t *testing.T) {
+	src := `a0 = "v0"
+b1 {
+  # comment
+  a1 = "v1"
+  a2 = "v2"
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		fn      FragmentFunc
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "replace",
+			address: "b1.a1",
+			fn: func(fragment string) (string, error) {
+				if fragment != "# comment\na1 = \"v1\"\n" {
+					return "", fmt.Errorf("unexpected fragment: %q", fragment)
+				}
+				return strings.ToUpper(fragment), nil
+			},
+			ok: true,
+			want: `a0 = "v0"
+b1 {
+  # COMMENT
+  A1 = "V1"
+  a2 = "v2"
+}
+`,
+		},
+		{
+			name:    "multiple items",
+			address: "a0",
+			fn: func(fragment string) (string, error) {
+				return fragment + "a3 = \"v3\"", nil
+			},
+			ok: true,
+			want: `a0 = "v0"
+a3 = "v3"
+b1 {
+  # comment
+  a1 = "v1"
+  a2 = "v2"
+}
+`,
+		},
+		{
+			name:    "empty",
+			address: "b1.a2",
+			fn: func(fragment string) (string, error) {
+				return "", nil
+			},
+			ok: true,
+			want: `a0 = "v0"
+b1 {
+  # comment
+  a1 = "v1"
+}
+`,
+		},
+		{
+			name:    "not found",
+			address: "a3",
+			fn: func(fragment string) (string, error) {
+				return "", fmt.Errorf("should not be called")
+			},
+			ok:   true,
+			want: src,
+		},
+		{
+			name:    "error",
+			address: "a0",
+			fn: func(fragment string) (string, error) {
+				return "", fmt.Errorf("failed")
+			},
+			ok:   false,
+			want: "",
+		},
+		{
+			name:    "invalid result",
+			address: "a0",
+			fn: func(fragment string) (string, error) {
+				return "a0 = {", nil
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := FilterAttributeFragment(inStream, outStream, "test", tc.address, tc.fn)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFilterBlockFragment(t *testing.T) {
+	src := `b1 "l1" {
+  a1 = "v1"
+}
+
+b1 "l2" {
+  a1 = "v2"
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		fn      FragmentFunc
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "wildcard",
+			address: "b1.*",
+			fn: func(fragment string) (string, error) {
+				return strings.Replace(fragment, "a1", "a2", 1), nil
+			},
+			ok: true,
+			want: `b1 "l1" {
+  a2 = "v1"
+}
+
+b1 "l2" {
+  a2 = "v2"
+}
+`,
+		},
+		{
+			name:    "remove",
+			address: "b1.l1",
+			fn: func(fragment string) (string, error) {
+				return "", nil
+			},
+			ok: true,
+			want: `
+b1 "l2" {
+  a1 = "v2"
+}
+`,
+		},
+		{
+			name:    "invalid result",
+			address: "b1.l1",
+			fn: func(fragment string) (string, error) {
+				return strings.TrimSuffix(fragment, "}\n"), nil
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := FilterBlockFragment(inStream, outStream, "test", tc.address, tc.fn)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package editor
+
+import (
+	"bytes"
+)
+
+// CheckIdempotent applies an editor built by make to a given source, and then
+// applies a fresh editor to the result, and reports whether the two outputs
+// are byte-identical. It is useful for catching filters which are not stable
+// in tests of automation built on top of the editor.
+// A fresh editor is built for each application, so that a stateful filter
+// doesn't leak state. The source is never mutated.
+func CheckIdempotent(source []byte, make func() *Editor) (bool, error) {
+	// Note that the builtin make is shadowed by the parameter here.
+	input := append([]byte{}, source...)
+
+	once, err := make().apply(input)
+	if err != nil {
+		return false, err
+	}
+
+	twice, err := make().apply(once)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(once, twice), nil
+}
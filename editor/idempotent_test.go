@@ -0,0 +1,82 @@
+package editor
+
+import (
+	"testing"
+)
+
+func TestCheckIdempotent(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		filter func() Filter
+		ok     bool
+		want   bool
+	}{
+		{
+			name: "idempotent",
+			src: `
+b1 {
+  a1 = v1
+}
+`,
+			filter: func() Filter {
+				return &attributeSet{address: "b1.a1", value: "v2"}
+			},
+			ok:   true,
+			want: true,
+		},
+		{
+			name: "not idempotent",
+			src: `
+b1 {
+}
+`,
+			filter: func() Filter {
+				return &blockAppend{parent: "b1", child: "b2"}
+			},
+			ok:   true,
+			want: false,
+		},
+		{
+			name: "error",
+			src: `
+a1 = v1
+`,
+			filter: func() Filter {
+				return &attributeSet{address: "", value: "v2"}
+			},
+			ok:   false,
+			want: false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			source := []byte(tc.src)
+			make := func() *Editor {
+				return &Editor{
+					source:  &parser{filename: "test"},
+					filters: []Filter{tc.filter()},
+					sink:    &formater{},
+				}
+			}
+
+			got, err := CheckIdempotent(source, make)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error")
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %t, want: %t", got, tc.want)
+			}
+
+			if string(source) != tc.src {
+				t.Fatalf("source was mutated: %s", string(source))
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package editor
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// defaultIndent is an indentation unit of the hclwrite formatter.
+const defaultIndent = "  "
+
+// WithIndent returns an Option to re-indent the output with a given
+// indentation unit such as four spaces or a tab for each nesting level.
+// An empty string means the default of two spaces.
+func WithIndent(indent string) Option {
+	return func(e *Editor) {
+		e.indent = indent
+	}
+}
+
+// reindent replaces the leading whitespace of each line in formatted HCL
+// with a given indentation unit. The nesting depth of each line is derived
+// from the indentation of the hclwrite formatter, which indents two spaces
+// per level. Lines which do not start with a token, such as the rest of
+// multi-line comments, and contents of heredocs are left untouched, because
+// their whitespace is significant or not under control of the formatter.
+// If the input cannot be lexed as HCL, it is returned as it is.
+func reindent(src []byte, indent string) []byte {
+	if indent == "" || indent == defaultIndent {
+		return src
+	}
+
+	tokens, diags := hclsyntax.LexConfig(src, "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diags.HasErrors() {
+		return src
+	}
+
+	// Collect lines whose first non-whitespace character is a beginning of
+	// a token outside of heredocs.
+	structural := make(map[int]bool)
+	inHeredoc := false
+	for _, t := range tokens {
+		switch t.Type {
+		case hclsyntax.TokenOHeredoc:
+			inHeredoc = true
+			continue
+		case hclsyntax.TokenCHeredoc:
+			inHeredoc = false
+			continue
+		}
+		if !inHeredoc && startsLine(src, t.Range.Start.Byte) {
+			structural[t.Range.Start.Line] = true
+		}
+	}
+
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if !structural[i+1] {
+			buf.Write(line)
+			continue
+		}
+
+		content := bytes.TrimLeft(line, " ")
+		width := len(line) - len(content)
+		level := width / len(defaultIndent)
+		buf.Write(bytes.Repeat([]byte(indent), level))
+		buf.Write(bytes.Repeat([]byte(" "), width%len(defaultIndent)))
+		buf.Write(content)
+	}
+
+	return buf.Bytes()
+}
+
+// startsLine returns true if there are only spaces between a given offset
+// and the beginning of the line.
+func startsLine(src []byte, offset int) bool {
+	for i := offset - 1; i >= 0; i-- {
+		switch src[i] {
+		case '\n':
+			return true
+		case ' ':
+		default:
+			return false
+		}
+	}
+	return true
+}
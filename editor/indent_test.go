@@ -0,0 +1,136 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReindent(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		indent string
+		want   string
+	}{
+		{
+			name: "four spaces",
+			src: `b1 {
+  a1 = v1
+  b2 {
+    a2 = v2
+  }
+}
+`,
+			indent: "    ",
+			want: `b1 {
+    a1 = v1
+    b2 {
+        a2 = v2
+    }
+}
+`,
+		},
+		{
+			name: "tab",
+			src: `b1 {
+  a1 = [
+    "v1",
+  ]
+}
+`,
+			indent: "\t",
+			want:   "b1 {\n\ta1 = [\n\t\t\"v1\",\n\t]\n}\n",
+		},
+		{
+			name: "default",
+			src: `b1 {
+  a1 = v1
+}
+`,
+			indent: "",
+			want: `b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "heredoc contents are left untouched",
+			src: `b1 {
+  a1 = <<EOF
+  foo
+    bar
+EOF
+  a2 = <<-EOF
+    baz
+  EOF
+}
+`,
+			indent: "    ",
+			want: `b1 {
+    a1 = <<EOF
+  foo
+    bar
+EOF
+    a2 = <<-EOF
+    baz
+  EOF
+}
+`,
+		},
+		{
+			name: "multi-line comments",
+			src: `b1 {
+  /*
+    foo
+  */
+  a1 = v1
+}
+`,
+			indent: "    ",
+			want: `b1 {
+    /*
+    foo
+  */
+    a1 = v1
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(reindent([]byte(tc.src), tc.indent))
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithIndent(t *testing.T) {
+	src := `b1 {
+  a1 = v1
+  b2 {
+    a2 = v2
+  }
+}
+`
+	want := `b1 {
+    a1 = v3
+    b2 {
+        a2 = v2
+    }
+}
+`
+	inStream := bytes.NewBufferString(src)
+	outStream := new(bytes.Buffer)
+	err := SetAttribute(inStream, outStream, "test", "b1.a1", "v3", WithIndent("    "))
+	if err != nil {
+		t.Fatalf("unexpected err = %s", err)
+	}
+
+	got := outStream.String()
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// jsonResult is a matched item written in JSON.
+// It is also given to a template as metadata of the item.
+type jsonResult struct {
+	Address string `json:"address"`
+	// Labels is labels of a matched block. It is available only in a template
+	// to keep the JSON output compatible.
+	Labels []string   `json:"-"`
+	Value  string     `json:"value,omitempty"`
+	Range  *jsonRange `json:"range,omitempty"`
+}
+
+// jsonRange is a source range of a matched item written in JSON.
+type jsonRange struct {
+	Filename string  `json:"filename"`
+	Start    jsonPos `json:"start"`
+	End      jsonPos `json:"end"`
+}
+
+// jsonPos is a position in source written in JSON.
+// The line and column are 1-based, and the byte is a 0-based offset.
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// newJSONResult returns a jsonResult with a source range of given tokens.
+// If the range is not found, the range is omitted.
+func newJSONResult(address string, value string, tokens hclwrite.Tokens, ranges *sourceRanges) jsonResult {
+	result := jsonResult{Address: address, Value: value}
+	if r, ok := ranges.rangeOf(tokens); ok {
+		result.Range = toJSONRange(r)
+	}
+	return result
+}
+
+// toJSONRange converts hcl.Range to jsonRange.
+func toJSONRange(r hcl.Range) *jsonRange {
+	return &jsonRange{
+		Filename: r.Filename,
+		Start:    jsonPos{Line: r.Start.Line, Column: r.Start.Column, Byte: r.Start.Byte},
+		End:      jsonPos{Line: r.End.Line, Column: r.End.Column, Byte: r.End.Byte},
+	}
+}
+
+// marshalJSONResults returns a JSON array of results followed by a newline.
+// An empty result is written as an empty array instead of null.
+func marshalJSONResults(results []jsonResult) ([]byte, error) {
+	if results == nil {
+		results = []jsonResult{}
+	}
+
+	out, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal results as JSON: %s", err)
+	}
+
+	return append(out, '\n'), nil
+}
+
+// attributeJSON is a Sink implementation to write matched attributes in JSON
+// in order of addresses. It reads an output of the attributesGet filter.
+type attributeJSON struct {
+	addresses []string
+	ranges    *sourceRanges
+}
+
+// Sink reads HCL and writes matched attributes in JSON.
+func (s *attributeJSON) Sink(inFile *hclwrite.File) ([]byte, error) {
+	results := []jsonResult{}
+	for _, address := range s.addresses {
+		attr := inFile.Body().GetAttribute(address)
+		if attr == nil {
+			continue
+		}
+		value, err := getAttributeValueAsString(attr)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, newJSONResult(address, value, attr.Expr().BuildTokens(nil), s.ranges))
+	}
+
+	return marshalJSONResults(results)
+}
+
+// blockJSON is a Sink implementation to write top level blocks in JSON.
+type blockJSON struct {
+	ranges *sourceRanges
+	// withValue is a flag to write formatted contents of blocks as values.
+	withValue bool
+}
+
+// Sink reads HCL and writes top level blocks in JSON.
+func (s *blockJSON) Sink(inFile *hclwrite.File) ([]byte, error) {
+	results := []jsonResult{}
+	for _, b := range inFile.Body().Blocks() {
+		tokens := b.BuildTokens(nil)
+		value := ""
+		if s.withValue {
+			value = strings.TrimSpace(string(formatHCL(tokens.Bytes())))
+		}
+		results = append(results, newJSONResult(toAddress(b), value, tokens, s.ranges))
+	}
+
+	return marshalJSONResults(results)
+}
@@ -0,0 +1,256 @@
+package editor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The JSON syntax of HCL such as *.tf.json is a plain JSON document, where
+// block types, labels and nested blocks are represented as nested objects,
+// and repeated blocks may be represented as arrays of objects.
+// Since the hclwrite package only supports the native syntax, operations
+// which support the JSON syntax edit the document directly. An address is
+// mapped to a path of object keys, and only the bytes of the matched value
+// are replaced, so that the rest of the document including the order of
+// keys and the indentation is preserved as it is.
+
+// isJSONSyntax returns true if a given input is written in the JSON syntax.
+// It is detected by the .json extension of the filename, or the leading
+// brace, which never appears at the beginning of the native syntax.
+func isJSONSyntax(filename string, src []byte) bool {
+	if strings.HasSuffix(filename, ".json") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(src), []byte("{"))
+}
+
+// getJSONAttribute returns a value at a given address in a JSON document
+// followed by a newline. The value is written as raw JSON.
+// If strict is true, it returns a *NotFoundError when the value is not found.
+// Otherwise nothing is returned.
+func getJSONAttribute(src []byte, address string, strict bool) ([]byte, error) {
+	node, err := findJSONValue(src, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		if strict {
+			return nil, &NotFoundError{Address: address}
+		}
+		return []byte{}, nil
+	}
+
+	return append(src[node.start:node.end:node.end], '\n'), nil
+}
+
+// setJSONAttribute replaces a value at a given address in a JSON document
+// with a given value, which must be a valid JSON value.
+// If the value is not found, the document is returned as it is.
+func setJSONAttribute(src []byte, address string, value string) ([]byte, error) {
+	if !json.Valid([]byte(value)) {
+		return nil, fmt.Errorf("failed to set a value in the JSON syntax. the value must be a JSON value: %s", value)
+	}
+
+	node, err := findJSONValue(src, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		return src, nil
+	}
+
+	out := make([]byte, 0, len(src)+len(value))
+	out = append(out, src[:node.start]...)
+	out = append(out, value...)
+	out = append(out, src[node.end:]...)
+
+	return out, nil
+}
+
+// findJSONValue returns a value at a given address in a JSON document.
+// Each segment of the address is a key of objects. If an array is found on
+// the way, the first element which contains the rest of the address is
+// used, unless an index suffix selects an element explicitly.
+// It returns nil if the value is not found.
+func findJSONValue(src []byte, address string) (*jsonNode, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	if containsPattern(a) {
+		return nil, fmt.Errorf("a wildcard, a regular expression and a recursive descent are not supported in the JSON syntax: %s", address)
+	}
+
+	root, err := parseJSONNode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.find(a)
+}
+
+// jsonNode is a value in a JSON document with its byte offsets.
+type jsonNode struct {
+	// start and end are byte offsets of the value in the document.
+	start int
+	end   int
+	// members is a list of members in order if the value is an object.
+	members []jsonMember
+	// elems is a list of elements if the value is an array.
+	elems []*jsonNode
+	// isArray is a flag which indicates that the value is an array.
+	isArray bool
+}
+
+// jsonMember is a member of a JSON object.
+type jsonMember struct {
+	key   string
+	value *jsonNode
+}
+
+// find returns a descendant value at given segments of address.
+func (n *jsonNode) find(segments []string) (*jsonNode, error) {
+	if len(segments) == 0 {
+		return n, nil
+	}
+
+	if n.isArray {
+		for _, elem := range n.elems {
+			found, err := elem.find(segments)
+			if err != nil || found != nil {
+				return found, err
+			}
+		}
+		return nil, nil
+	}
+
+	name, index, err := parseIndexedSegment(segments[0])
+	if err != nil {
+		return nil, err
+	}
+	name = unquoteSegment(name)
+
+	for _, m := range n.members {
+		if m.key != name {
+			continue
+		}
+		value := m.value
+		if index >= 0 {
+			if !value.isArray || index >= len(value.elems) {
+				return nil, nil
+			}
+			value = value.elems[index]
+		}
+		return value.find(segments[1:])
+	}
+
+	return nil, nil
+}
+
+// parseJSONNode parses a JSON document and returns the root value.
+func parseJSONNode(src []byte) (*jsonNode, error) {
+	if !json.Valid(src) {
+		// report a detailed error by the standard decoder.
+		var v interface{}
+		err := json.Unmarshal(src, &v)
+		return nil, fmt.Errorf("failed to parse input as JSON: %s", err)
+	}
+
+	s := &jsonScanner{src: src}
+	return s.value()
+}
+
+// jsonScanner is a minimal scanner of a valid JSON document, which records
+// byte offsets of values.
+type jsonScanner struct {
+	src []byte
+	pos int
+}
+
+// skipSpace skips whitespaces.
+func (s *jsonScanner) skipSpace() {
+	for s.pos < len(s.src) && strings.IndexByte(" \t\r\n", s.src[s.pos]) >= 0 {
+		s.pos++
+	}
+}
+
+// value scans a value at the current position.
+func (s *jsonScanner) value() (*jsonNode, error) {
+	s.skipSpace()
+	n := &jsonNode{start: s.pos}
+	switch s.src[s.pos] {
+	case '{':
+		s.pos++
+		for {
+			s.skipSpace()
+			if s.src[s.pos] == '}' {
+				s.pos++
+				break
+			}
+			if s.src[s.pos] == ',' {
+				s.pos++
+				s.skipSpace()
+			}
+			keyStart := s.pos
+			s.skipString()
+			var key string
+			if err := json.Unmarshal(s.src[keyStart:s.pos], &key); err != nil {
+				return nil, fmt.Errorf("failed to parse a key of object: %s", err)
+			}
+			s.skipSpace()
+			// skip a colon
+			s.pos++
+			value, err := s.value()
+			if err != nil {
+				return nil, err
+			}
+			n.members = append(n.members, jsonMember{key: key, value: value})
+		}
+	case '[':
+		n.isArray = true
+		s.pos++
+		for {
+			s.skipSpace()
+			if s.src[s.pos] == ']' {
+				s.pos++
+				break
+			}
+			if s.src[s.pos] == ',' {
+				s.pos++
+			}
+			elem, err := s.value()
+			if err != nil {
+				return nil, err
+			}
+			n.elems = append(n.elems, elem)
+		}
+	case '"':
+		s.skipString()
+	default:
+		// a number, true, false or null
+		for s.pos < len(s.src) && strings.IndexByte(",}] \t\r\n", s.src[s.pos]) < 0 {
+			s.pos++
+		}
+	}
+	n.end = s.pos
+
+	return n, nil
+}
+
+// skipString skips a string at the current position including quotes.
+func (s *jsonScanner) skipString() {
+	// skip the opening quote
+	s.pos++
+	for s.src[s.pos] != '"' {
+		if s.src[s.pos] == '\\' {
+			s.pos++
+		}
+		s.pos++
+	}
+	// skip the closing quote
+	s.pos++
+}
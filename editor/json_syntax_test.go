@@ -0,0 +1,208 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONSyntax(t *testing.T) {
+	src := `{
+  "resource": {
+    "aws_instance": {
+      "foo": {
+        "ami": "ami-123",
+        "tags": {"Name": "foo"},
+        "ebs_block_device": [
+          {"device_name": "/dev/sda1", "volume_size": 8},
+          {"device_name": "/dev/sdb1", "volume_size": 16}
+        ]
+      }
+    }
+  },
+  "locals": [
+    {"env": "dev"}
+  ]
+}
+`
+
+	getCases := []struct {
+		name    string
+		address string
+		strict  bool
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "string",
+			address: "resource.aws_instance.foo.ami",
+			ok:      true,
+			want:    "\"ami-123\"\n",
+		},
+		{
+			name:    "object",
+			address: "resource.aws_instance.foo.tags",
+			ok:      true,
+			want:    "{\"Name\": \"foo\"}\n",
+		},
+		{
+			name:    "first element of array",
+			address: "resource.aws_instance.foo.ebs_block_device.volume_size",
+			ok:      true,
+			want:    "8\n",
+		},
+		{
+			name:    "index",
+			address: "resource.aws_instance.foo.ebs_block_device[1].volume_size",
+			ok:      true,
+			want:    "16\n",
+		},
+		{
+			name:    "block in array",
+			address: "locals.env",
+			ok:      true,
+			want:    "\"dev\"\n",
+		},
+		{
+			name:    "not found",
+			address: "resource.aws_instance.bar.ami",
+			ok:      true,
+			want:    "",
+		},
+		{
+			name:    "not found in strict mode",
+			address: "resource.aws_instance.bar.ami",
+			strict:  true,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "wildcard is not supported",
+			address: "resource.*.foo.ami",
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range getCases {
+		t.Run("get "+tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			opts := []Option{}
+			if tc.strict {
+				opts = append(opts, WithStrict())
+			}
+			err := GetAttribute(inStream, outStream, "test.tf.json", tc.address, opts...)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+
+	setCases := []struct {
+		name    string
+		src     string
+		address string
+		value   string
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "string",
+			src:     `{"locals": {"env": "dev", "region": "us-east-1"}}`,
+			address: "locals.env",
+			value:   `"prod"`,
+			ok:      true,
+			want:    `{"locals": {"env": "prod", "region": "us-east-1"}}`,
+		},
+		{
+			name: "preserve formatting",
+			src: `{
+  "locals": {
+    "b": 1,
+    "a": [1, 2]
+  }
+}
+`,
+			address: "locals.a",
+			value:   `[3]`,
+			ok:      true,
+			want: `{
+  "locals": {
+    "b": 1,
+    "a": [3]
+  }
+}
+`,
+		},
+		{
+			name:    "escaped key",
+			src:     `{"locals": {"a\"b": 1}}`,
+			address: `locals.a\"b`,
+			value:   `2`,
+			ok:      true,
+			want:    `{"locals": {"a\"b": 2}}`,
+		},
+		{
+			name:    "not found",
+			src:     `{"locals": {"env": "dev"}}`,
+			address: "locals.region",
+			value:   `"us-east-1"`,
+			ok:      true,
+			want:    `{"locals": {"env": "dev"}}`,
+		},
+		{
+			name:    "invalid value",
+			src:     `{"locals": {"env": "dev"}}`,
+			address: "locals.env",
+			value:   `var.env`,
+			ok:      false,
+			want:    "",
+		},
+		{
+			name:    "invalid json",
+			src:     `{"locals": {"env": "dev"}`,
+			address: "locals.env",
+			value:   `"prod"`,
+			ok:      false,
+			want:    "",
+		},
+	}
+
+	for _, tc := range setCases {
+		t.Run("set "+tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetAttribute(inStream, outStream, "-", tc.address, tc.value)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("not supported", func(t *testing.T) {
+		inStream := bytes.NewBufferString(src)
+		outStream := new(bytes.Buffer)
+		err := RemoveAttribute(inStream, outStream, "test.tf.json", "locals.env")
+		if err == nil {
+			t.Fatalf("expected to return an error, but no error, outStream: \n%s", outStream.String())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// Merge reads a base HCL from io.Reader and an overlay HCL from another
+// io.Reader, and deep-merges the overlay into the base, and writes the merged
+// HCL to io.Writer.
+// Blocks are matched by type and labels. If there are multiple blocks with
+// the same type and labels in a body, they are matched in order of
+// appearance. Matched blocks are merged recursively, and the rest of the
+// overlay blocks are appended to the body.
+// Attributes in the overlay replace values of the base ones with the same
+// name, and the rest of the overlay attributes are appended to the body.
+// Comments immediately above appended items move with them.
+// Note that filenames are used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func Merge(r io.Reader, w io.Writer, filename string, overlay io.Reader, overlayFilename string, opts ...Option) error {
+	src, err := ioutil.ReadAll(overlay)
+	if err != nil {
+		return fmt.Errorf("failed to read overlay: %s", err)
+	}
+
+	overlayFile, err := safeParseConfig(src, overlayFilename, hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return err
+	}
+
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&merger{overlay: overlayFile},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// merger is a filter implementation for merging an overlay file.
+type merger struct {
+	overlay *hclwrite.File
+}
+
+// Filter reads HCL and merges the overlay into it.
+// Appended items are added as tokens to preserve their comments, so the file
+// is parsed again.
+func (f *merger) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	mergeBody(inFile.Body(), f.overlay.Body())
+
+	return safeParseConfig(inFile.BuildTokens(nil).Bytes(), "generated_by_merger", hcl.Pos{Line: 1, Column: 1})
+}
+
+// mergeBody merges an overlay body into a base body recursively.
+func mergeBody(base *hclwrite.Body, overlay *hclwrite.Body) {
+	overlayTokens := overlay.BuildTokens(nil)
+	appended := hclwrite.Tokens{}
+
+	for _, name := range attributeNames(overlay) {
+		attr := overlay.GetAttribute(name)
+		if base.GetAttribute(name) != nil {
+			base.SetAttributeRaw(name, attr.Expr().BuildTokens(nil))
+			continue
+		}
+		start, end := attributeWithLeadingComments(overlayTokens, attr)
+		appended = append(appended, withTrailingNewline(copyTokens(overlayTokens[start:end]))...)
+	}
+
+	// counts is the number of overlay blocks seen so far for each address,
+	// used for matching repeated blocks in order.
+	counts := make(map[string]int)
+	baseBlocks := base.Blocks()
+	hasItems := len(base.Attributes()) != 0 || len(baseBlocks) != 0
+	for _, b := range overlay.Blocks() {
+		addr := toAddress(b)
+		n := counts[addr]
+		counts[addr]++
+
+		if matched := nthBlockByAddress(baseBlocks, addr, n); matched != nil {
+			mergeBody(matched.Body(), b.Body())
+			continue
+		}
+
+		if hasItems || len(appended) != 0 {
+			// separate blocks with a blank line for readability.
+			appended = append(appended, &hclwrite.Token{
+				Type:  hclsyntax.TokenNewline,
+				Bytes: []byte("\n"),
+			})
+		}
+		start, end := itemWithLeadingComments(overlayTokens, b.BuildTokens(nil))
+		appended = append(appended, withTrailingNewline(copyTokens(overlayTokens[start:end]))...)
+	}
+
+	if len(appended) != 0 {
+		base.AppendUnstructuredTokens(appended)
+	}
+}
+
+// nthBlockByAddress returns the n-th block with a given address in blocks.
+// If not found, it returns nil.
+func nthBlockByAddress(blocks []*hclwrite.Block, addr string, n int) *hclwrite.Block {
+	for _, b := range blocks {
+		if toAddress(b) != addr {
+			continue
+		}
+		if n == 0 {
+			return b
+		}
+		n--
+	}
+	return nil
+}
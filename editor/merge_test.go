@@ -0,0 +1,157 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	cases := []struct {
+		name    string
+		base    string
+		overlay string
+		ok      bool
+		want    string
+	}{
+		{
+			name: "attributes",
+			base: `
+a = 1
+b = 2
+`,
+			overlay: `
+b = 3
+# comment c
+c = 4
+`,
+			ok: true,
+			want: `
+a = 1
+b = 3
+# comment c
+c = 4
+`,
+		},
+		{
+			name: "blocks",
+			base: `resource "foo" "bar" {
+  attr1 = "val1"
+  nested {
+    attr2 = "val2"
+  }
+}
+`,
+			overlay: `resource "foo" "bar" {
+  attr1 = "val3"
+  nested {
+    attr3 = "val3"
+  }
+}
+
+# comment baz
+resource "foo" "baz" {
+  attr1 = "val1"
+}
+`,
+			ok: true,
+			want: `resource "foo" "bar" {
+  attr1 = "val3"
+  nested {
+    attr2 = "val2"
+    attr3 = "val3"
+  }
+}
+
+# comment baz
+resource "foo" "baz" {
+  attr1 = "val1"
+}
+`,
+		},
+		{
+			name: "repeated blocks are matched in order",
+			base: `resource "foo" "bar" {
+  ingress {
+    port = 80
+  }
+  ingress {
+    port = 443
+  }
+}
+`,
+			overlay: `resource "foo" "bar" {
+  ingress {
+    cidr = "a"
+  }
+  ingress {
+    cidr = "b"
+  }
+  ingress {
+    port = 22
+  }
+}
+`,
+			ok: true,
+			want: `resource "foo" "bar" {
+  ingress {
+    port = 80
+    cidr = "a"
+  }
+  ingress {
+    port = 443
+    cidr = "b"
+  }
+
+  ingress {
+    port = 22
+  }
+}
+`,
+		},
+		{
+			name: "empty base",
+			base: ``,
+			overlay: `a = 1
+
+b {
+}
+`,
+			ok: true,
+			want: `a = 1
+
+b {
+}
+`,
+		},
+		{
+			name: "invalid overlay",
+			base: `a = 1
+`,
+			overlay: `a = 
+`,
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.base)
+			overlay := bytes.NewBufferString(tc.overlay)
+			outStream := new(bytes.Buffer)
+			err := Merge(inStream, outStream, "base", overlay, "overlay")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package editor
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// WithNormalizeNewlines returns an Option to normalize blank lines of the
+// output, which is useful for generated files with strict style expectations.
+// Consecutive blank lines are collapsed into one, blank lines at the beginning
+// and the end of the file and of each block body are removed, and the output
+// ends with exactly one newline. Contents of heredocs and multi-line comments
+// are left untouched.
+func WithNormalizeNewlines() Option {
+	return func(e *Editor) {
+		e.normalizeNewlines = true
+	}
+}
+
+// normalizeNewlines removes redundant blank lines in formatted HCL.
+// If the input cannot be lexed as HCL, it is returned as it is.
+func normalizeNewlines(src []byte) []byte {
+	tokens, diags := hclsyntax.LexConfig(src, "", hcl.Pos{Line: 1, Column: 1, Byte: 0})
+	if diags.HasErrors() {
+		return src
+	}
+
+	// Collect lines whose content is not under control of the formatter.
+	verbatim := make(map[int]bool)
+	inHeredoc := false
+	for _, t := range tokens {
+		switch t.Type {
+		case hclsyntax.TokenOHeredoc:
+			inHeredoc = true
+			continue
+		case hclsyntax.TokenCHeredoc:
+			inHeredoc = false
+			continue
+		}
+		end := t.Range.End.Line
+		if t.Range.End.Column == 1 {
+			// a token ending with a newline, such as a single-line comment.
+			end--
+		}
+		start := t.Range.Start.Line + 1
+		if inHeredoc {
+			start = t.Range.Start.Line
+		}
+		for l := start; l <= end; l++ {
+			verbatim[l] = true
+		}
+	}
+
+	lines := bytes.SplitAfter(src, []byte("\n"))
+	var kept [][]byte
+	// blank is a flag that a blank line is pending. It is written only if the
+	// next line is neither the end of a body nor the end of the file.
+	blank := false
+	for i, line := range lines {
+		content := bytes.TrimSpace(line)
+		if len(content) == 0 && !verbatim[i+1] {
+			blank = len(kept) != 0 && !bytes.HasSuffix(bytes.TrimSpace(kept[len(kept)-1]), []byte("{"))
+			continue
+		}
+		if blank && !(bytes.HasPrefix(content, []byte("}")) && !verbatim[i+1]) {
+			kept = append(kept, []byte("\n"))
+		}
+		blank = false
+		kept = append(kept, line)
+	}
+
+	out := bytes.Join(kept, nil)
+	if len(out) != 0 && !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, '\n')
+	}
+
+	return out
+}
@@ -0,0 +1,142 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNormalizeNewlines(t *testing.T) {
+	cases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "consecutive blank lines",
+			src: `a0 = v0
+
+
+b1 {
+  a1 = v1
+
+
+  a2 = v2
+}
+`,
+			want: `a0 = v0
+
+b1 {
+  a1 = v1
+
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "leading and trailing blank lines",
+			src: `
+
+b1 {
+
+  a1 = v1
+
+}
+
+
+`,
+			want: `b1 {
+  a1 = v1
+}
+`,
+		},
+		{
+			name: "missing trailing newline",
+			src:  `a0 = v0`,
+			want: `a0 = v0
+`,
+		},
+		{
+			name: "empty",
+			src:  ``,
+			want: ``,
+		},
+		{
+			name: "comments",
+			src: `# comment
+
+a0 = v0 # comment
+
+
+# comment
+a1 = v1
+`,
+			want: `# comment
+
+a0 = v0 # comment
+
+# comment
+a1 = v1
+`,
+		},
+		{
+			name: "heredoc and multi-line comments are left untouched",
+			src: `b1 {
+  a1 = <<EOF
+foo
+
+
+}
+EOF
+
+
+  /*
+
+  */
+}
+`,
+			want: `b1 {
+  a1 = <<EOF
+foo
+
+
+}
+EOF
+
+  /*
+
+  */
+}
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := string(normalizeNewlines([]byte(tc.src)))
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithNormalizeNewlines(t *testing.T) {
+	src := `b1 {
+}
+`
+	want := `b1 {
+  a1 = v1
+}
+`
+	inStream := bytes.NewBufferString(src)
+	outStream := new(bytes.Buffer)
+	err := AppendAttribute(inStream, outStream, "test", "b1.a1", "v1", true, WithNormalizeNewlines())
+	if err != nil {
+		t.Fatalf("unexpected err = %s", err)
+	}
+
+	got := outStream.String()
+	if got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
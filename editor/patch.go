@@ -0,0 +1,263 @@
+package editor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// PatchFormatHCL is a format of patch written in HCL.
+	PatchFormatHCL = "hcl"
+	// PatchFormatJSON is a format of patch written in JSON.
+	PatchFormatJSON = "json"
+)
+
+// CreatePatch reads HCL from io.Reader and another HCL from another
+// io.Reader, and returns a patch, that is, a list of operations to edit the
+// former into the latter. The patch can be applied with ApplyScript to any
+// file which has the same structure.
+// Attributes are compared by name in the same way as SemanticDiff, and
+// changed, added and removed ones become set, append-attribute and rm
+// operations respectively. Added blocks become append operations with their
+// bodies, and removed top level blocks become rm-block operations.
+// It returns an error for a change which cannot be addressed, such as a change
+// in repeated blocks with the same address or a removal of nested block.
+// Note that filenames are used only for an error message.
+func CreatePatch(r io.Reader, filename string, other io.Reader, otherFilename string) ([]Operation, error) {
+	from, err := parsePatchInput(r, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := parsePatchInput(other, otherFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := []Operation{}
+	if err := createPatchBody(&patch, "", from.Body(), to.Body()); err != nil {
+		return nil, err
+	}
+
+	return patch, nil
+}
+
+// parsePatchInput reads and parses HCL for creating a patch.
+func parsePatchInput(r io.Reader, filename string) (*hclwrite.File, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %s", err)
+	}
+
+	return safeParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+}
+
+// createPatchBody appends operations to edit a body of a into b recursively.
+// A prefix is an address of the block containing them.
+func createPatchBody(patch *[]Operation, prefix string, a *hclwrite.Body, b *hclwrite.Body) error {
+	for _, name := range attributeNames(a) {
+		other := b.GetAttribute(name)
+		if other == nil {
+			*patch = append(*patch, Operation{Kind: OperationRemove, Address: joinAddress(prefix, name)})
+			continue
+		}
+		if !equalTokens(a.GetAttribute(name).Expr().BuildTokens(nil), other.Expr().BuildTokens(nil)) {
+			*patch = append(*patch, Operation{Kind: OperationSet, Address: joinAddress(prefix, name), Value: expressionSource(other)})
+		}
+	}
+	for _, name := range attributeNames(b) {
+		if a.GetAttribute(name) == nil {
+			*patch = append(*patch, Operation{Kind: OperationAppendAttribute, Address: joinAddress(prefix, name), Value: expressionSource(b.GetAttribute(name))})
+		}
+	}
+
+	blocks := a.Blocks()
+	otherBlocks := b.Blocks()
+	counts := countBlocksByAddress(blocks)
+	otherCounts := countBlocksByAddress(otherBlocks)
+
+	// done records addresses already handled.
+	done := make(map[string]bool)
+	for _, blk := range blocks {
+		addr := toAddress(blk)
+		if done[addr] {
+			continue
+		}
+		done[addr] = true
+
+		full := joinAddress(prefix, addr)
+		switch {
+		case otherCounts[addr] == 0:
+			if len(prefix) != 0 {
+				return fmt.Errorf("failed to create patch. removing a nested block is not supported: %s", full)
+			}
+			*patch = append(*patch, Operation{Kind: OperationRemoveBlock, Address: full})
+		case counts[addr] == 1 && otherCounts[addr] == 1:
+			if err := createPatchBody(patch, full, blk.Body(), nthBlockByAddress(otherBlocks, addr, 0).Body()); err != nil {
+				return err
+			}
+		default:
+			if !equalBlocksByAddress(blocks, otherBlocks, addr) {
+				return fmt.Errorf("failed to create patch. changing repeated blocks is not supported: %s", full)
+			}
+		}
+	}
+
+	hasItems := len(a.Attributes()) != 0 || len(blocks) != 0
+	for _, blk := range otherBlocks {
+		addr := toAddress(blk)
+		if done[addr] {
+			continue
+		}
+		done[addr] = true
+
+		if otherCounts[addr] > 1 {
+			return fmt.Errorf("failed to create patch. adding repeated blocks is not supported: %s", joinAddress(prefix, addr))
+		}
+		*patch = append(*patch, Operation{
+			Kind:    OperationAppend,
+			Address: prefix,
+			Value:   addr,
+			Newline: hasItems,
+			Body:    string(trimLeadingNewLine(blk.Body().BuildTokens(nil)).Bytes()),
+		})
+	}
+
+	return nil
+}
+
+// expressionSource returns a source of expression of a given attribute.
+func expressionSource(attr *hclwrite.Attribute) string {
+	return strings.TrimSpace(string(attr.Expr().BuildTokens(nil).Bytes()))
+}
+
+// countBlocksByAddress returns the number of blocks for each address.
+func countBlocksByAddress(blocks []*hclwrite.Block) map[string]int {
+	counts := make(map[string]int)
+	for _, b := range blocks {
+		counts[toAddress(b)]++
+	}
+	return counts
+}
+
+// equalBlocksByAddress returns true if blocks with a given address in a and b
+// are the same in order except for whitespace, newlines and comments.
+func equalBlocksByAddress(a []*hclwrite.Block, b []*hclwrite.Block, addr string) bool {
+	for n := 0; ; n++ {
+		x := nthBlockByAddress(a, addr, n)
+		y := nthBlockByAddress(b, addr, n)
+		if x == nil || y == nil {
+			return x == nil && y == nil
+		}
+		if !equalTokens(x.BuildTokens(nil), y.BuildTokens(nil)) {
+			return false
+		}
+	}
+}
+
+// WritePatch writes a given patch to io.Writer in a given format.
+// The format is either PatchFormatHCL or PatchFormatJSON.
+// In HCL, each operation is an operation block labeled with its kind.
+func WritePatch(w io.Writer, patch []Operation, format string) error {
+	var out []byte
+	switch format {
+	case PatchFormatHCL:
+		f := hclwrite.NewEmptyFile()
+		for i, op := range patch {
+			if i != 0 {
+				f.Body().AppendNewline()
+			}
+			body := f.Body().AppendNewBlock("operation", []string{string(op.Kind)}).Body()
+			body.SetAttributeValue("address", cty.StringVal(op.Address))
+			if len(op.Value) != 0 {
+				body.SetAttributeValue("value", cty.StringVal(op.Value))
+			}
+			if op.Newline {
+				body.SetAttributeValue("newline", cty.True)
+			}
+			if len(op.Body) != 0 {
+				body.SetAttributeValue("body", cty.StringVal(op.Body))
+			}
+		}
+		out = hclwrite.Format(f.Bytes())
+	case PatchFormatJSON:
+		b, err := json.MarshalIndent(patch, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode patch: %s", err)
+		}
+		out = append(b, '\n')
+	default:
+		return fmt.Errorf("unknown patch format: %s", format)
+	}
+
+	if _, err := w.Write(out); err != nil {
+		return fmt.Errorf("failed to write patch: %s", err)
+	}
+
+	return nil
+}
+
+// patchHCL is a schema of patch written in HCL.
+type patchHCL struct {
+	Operations []operationHCL `hcl:"operation,block"`
+}
+
+// operationHCL is a schema of an operation block in HCL.
+type operationHCL struct {
+	Kind    string `hcl:"kind,label"`
+	Address string `hcl:"address,optional"`
+	Value   string `hcl:"value,optional"`
+	Newline bool   `hcl:"newline,optional"`
+	Body    string `hcl:"body,optional"`
+}
+
+// ReadPatch reads a patch written by WritePatch from io.Reader in a given
+// format, and returns a list of operations for ApplyScript.
+// Note that a filename is used only for an error message.
+func ReadPatch(r io.Reader, filename string, format string) ([]Operation, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch: %s", err)
+	}
+
+	patch := []Operation{}
+	switch format {
+	case PatchFormatHCL:
+		f, diags := hclsyntax.ParseConfig(src, filename, hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse patch: %s", diags)
+		}
+
+		var p patchHCL
+		if diags := gohcl.DecodeBody(f.Body, nil, &p); diags.HasErrors() {
+			return nil, fmt.Errorf("failed to decode patch: %s", diags)
+		}
+
+		for _, op := range p.Operations {
+			patch = append(patch, Operation{
+				Kind:    OperationKind(op.Kind),
+				Address: op.Address,
+				Value:   op.Value,
+				Newline: op.Newline,
+				Body:    op.Body,
+			})
+		}
+	case PatchFormatJSON:
+		if err := json.Unmarshal(src, &patch); err != nil {
+			return nil, fmt.Errorf("failed to decode patch: %s", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown patch format: %s", format)
+	}
+
+	return patch, nil
+}
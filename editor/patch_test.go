@@ -0,0 +1,311 @@
+package editor
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCreatePatch(t *testing.T) {
+	cases := []struct {
+		name  string
+		src   string
+		other string
+		ok    bool
+		want  []Operation
+	}{
+		{
+			name: "attributes",
+			src: `
+a0 = v0
+a1 = v1
+b1 "l1" {
+  a2 = v2 # comment
+}
+`,
+			other: `
+a0 = v3
+b1 "l1" {
+  a2 = v2
+  a3 = [1, 2]
+}
+`,
+			ok: true,
+			want: []Operation{
+				{Kind: OperationSet, Address: "a0", Value: "v3"},
+				{Kind: OperationRemove, Address: "a1"},
+				{Kind: OperationAppendAttribute, Address: "b1.l1.a3", Value: "[1, 2]"},
+			},
+		},
+		{
+			name: "blocks",
+			src: `
+b1 "l1" {
+}
+b1 "l2" {
+}
+`,
+			other: `
+b1 "l1" {
+  b2 {
+    a0 = v0
+  }
+}
+b1 "l3" {
+}
+`,
+			ok: true,
+			want: []Operation{
+				{Kind: OperationAppend, Address: "b1.l1", Value: "b2", Body: "    a0 = v0\n"},
+				{Kind: OperationRemoveBlock, Address: "b1.l2"},
+				{Kind: OperationAppend, Address: "", Value: "b1.l3", Newline: true},
+			},
+		},
+		{
+			name: "no change",
+			src: `
+a0 = v0 # comment
+`,
+			other: `
+a0 = v0
+`,
+			ok:   true,
+			want: []Operation{},
+		},
+		{
+			name: "unchanged repeated blocks",
+			src: `
+b1 {
+  a0 = v0
+}
+b1 {
+  a0 = v1
+}
+a1 = v1
+`,
+			other: `
+b1 {
+  a0 = v0
+}
+b1 {
+  a0 = v1
+}
+a1 = v2
+`,
+			ok: true,
+			want: []Operation{
+				{Kind: OperationSet, Address: "a1", Value: "v2"},
+			},
+		},
+		{
+			name: "changed repeated blocks",
+			src: `
+b1 {
+  a0 = v0
+}
+b1 {
+  a0 = v1
+}
+`,
+			other: `
+b1 {
+  a0 = v0
+}
+b1 {
+  a0 = v2
+}
+`,
+			ok:   false,
+			want: nil,
+		},
+		{
+			name: "removed nested block",
+			src: `
+b1 {
+  b2 {
+  }
+}
+`,
+			other: `
+b1 {
+}
+`,
+			ok:   false,
+			want: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := CreatePatch(strings.NewReader(tc.src), "test", strings.NewReader(tc.other), "other")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %#v", got)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWritePatch(t *testing.T) {
+	patch := []Operation{
+		{Kind: OperationSet, Address: "b1.l1.a0", Value: `"v0"`},
+		{Kind: OperationAppend, Address: "", Value: "b1.l2", Newline: true, Body: "a1 = v1\n"},
+	}
+
+	cases := []struct {
+		name   string
+		format string
+		ok     bool
+		want   string
+	}{
+		{
+			name:   "hcl",
+			format: PatchFormatHCL,
+			ok:     true,
+			want: `operation "set" {
+  address = "b1.l1.a0"
+  value   = "\"v0\""
+}
+
+operation "append" {
+  address = ""
+  value   = "b1.l2"
+  newline = true
+  body    = "a1 = v1\n"
+}
+`,
+		},
+		{
+			name:   "json",
+			format: PatchFormatJSON,
+			ok:     true,
+			want: `[
+  {
+    "kind": "set",
+    "address": "b1.l1.a0",
+    "value": "\"v0\""
+  },
+  {
+    "kind": "append",
+    "address": "",
+    "value": "b1.l2",
+    "newline": true,
+    "body": "a1 = v1\n"
+  }
+]
+`,
+		},
+		{
+			name:   "unknown format",
+			format: "yaml",
+			ok:     false,
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			outStream := new(bytes.Buffer)
+			err := WritePatch(outStream, patch, tc.format)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+
+			if !tc.ok {
+				return
+			}
+
+			read, err := ReadPatch(strings.NewReader(got), "test", tc.format)
+			if err != nil {
+				t.Fatalf("failed to read patch: %s", err)
+			}
+			if !reflect.DeepEqual(read, patch) {
+				t.Fatalf("got: %#v, want: %#v", read, patch)
+			}
+		})
+	}
+}
+
+func TestReadPatch(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		format string
+		ok     bool
+		want   []Operation
+	}{
+		{
+			name: "hcl",
+			src: `
+# comment
+operation "rm" {
+  address = "a0"
+}
+`,
+			format: PatchFormatHCL,
+			ok:     true,
+			want: []Operation{
+				{Kind: OperationRemove, Address: "a0"},
+			},
+		},
+		{
+			name:   "empty",
+			src:    "",
+			format: PatchFormatHCL,
+			ok:     true,
+			want:   []Operation{},
+		},
+		{
+			name: "unknown attribute",
+			src: `
+operation "rm" {
+  foo = "a0"
+}
+`,
+			format: PatchFormatHCL,
+			ok:     false,
+			want:   nil,
+		},
+		{
+			name:   "invalid json",
+			src:    `{"kind": "rm"}`,
+			format: PatchFormatJSON,
+			ok:     false,
+			want:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadPatch(strings.NewReader(tc.src), "test", tc.format)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %#v", got)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,518 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Query reads HCL from io.Reader, and writes results of a given query to
+// io.Writer. The query is a jq-style expression, which is an alternative
+// frontend to addresses for filtering and projection.
+//
+// A query is a pipeline of filters separated by |, and each filter is applied
+// to every result of the previous one. The input of the first filter is the
+// whole file. The supported filters are:
+//
+//	.                  the input as it is
+//	.a.b.c             a path relative to the input
+//	select(PATH)       the input only if PATH exists and is neither null nor false
+//	select(PATH == V)  the input only if a value at PATH equals a literal V
+//	select(PATH != V)  the input only if a value at PATH does not equal V
+//
+// A path is resolved in the same way as an address. A segment can be quoted
+// such as ."a.b", and followed by an index such as .a[0] or an iterator []
+// which iterates all matched blocks, elements or keys at the position, such
+// as .resource[], .resource.aws_instance[] or .tags[]. Blocks are iterated
+// even if they have more labels than the path. A missing value is treated as
+// null.
+// Each block in results is written as it is, and each value is written in a
+// line.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func Query(r io.Reader, w io.Writer, filename string, query string) error {
+	filters, err := parseQuery(query)
+	if err != nil {
+		return err
+	}
+
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &querySink{filters: filters},
+	}
+
+	return e.Apply(r, w)
+}
+
+// querySink is a Sink implementation to write results of a query.
+type querySink struct {
+	filters []queryFilter
+}
+
+// Sink reads HCL and writes results of the query.
+func (s *querySink) Sink(inFile *hclwrite.File) ([]byte, error) {
+	nodes := []queryNode{{body: inFile.Body()}}
+	for _, f := range s.filters {
+		var next []queryNode
+		for _, n := range nodes {
+			results, err := f.apply(n)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, results...)
+		}
+		nodes = next
+	}
+
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		buf.WriteString(n.String())
+	}
+
+	return buf.Bytes(), nil
+}
+
+// queryNode is a result of a query filter, which is either a body of the
+// file, a block or a value of attribute.
+type queryNode struct {
+	// body is a body of the file or a block. It is nil for a value.
+	body *hclwrite.Body
+	// block is a block of the body. It is nil for the file.
+	block *hclwrite.Block
+	// elem is a value of attribute or an element in it.
+	elem *elementMatch
+}
+
+// String returns a text representation of the node for output.
+func (n queryNode) String() string {
+	switch {
+	case n.elem != nil:
+		return n.elem.value() + "\n"
+	case n.block != nil:
+		return string(withTrailingNewline(trimLeadingNewLine(n.block.BuildTokens(nil))).Bytes())
+	default:
+		return string(withTrailingNewline(trimLeadingNewLine(n.body.BuildTokens(nil))).Bytes())
+	}
+}
+
+// queryFilter is a filter of a query pipeline.
+type queryFilter interface {
+	// apply returns results of the filter for a given input.
+	apply(n queryNode) ([]queryNode, error)
+}
+
+// queryIterate is an index of querySegment which iterates all labels,
+// elements or keys at the position.
+const queryIterate = -1
+
+// querySegment is a segment of a query path such as .a[0][].
+type querySegment struct {
+	// segment is a segment of address without suffixes, which may be quoted
+	// or a pattern such as a wildcard. It is empty for a segment such as .[].
+	segment string
+	// indexes are suffixes of the segment. queryIterate means [].
+	indexes []int
+}
+
+// name returns a name of the segment without quotes.
+func (s querySegment) name() string {
+	return unquoteSegment(s.segment)
+}
+
+// queryPath is a filter which resolves a path relative to the input.
+type queryPath struct {
+	segments []querySegment
+}
+
+// apply returns values or blocks at the path.
+func (p *queryPath) apply(n queryNode) ([]queryNode, error) {
+	if len(p.segments) == 0 {
+		return []queryNode{n}, nil
+	}
+
+	if n.elem != nil {
+		return walkElement(n.elem, p.segments), nil
+	}
+
+	// The path is resolved as blocks first, and then as a value of attribute
+	// in the longest matching blocks, in the same way as an address.
+	blocks, err := p.findBlocks(n.body, p.segments)
+	if err != nil {
+		return nil, err
+	}
+	if len(blocks) != 0 {
+		results := []queryNode{}
+		for _, b := range blocks {
+			results = append(results, queryNode{body: b.Body(), block: b})
+		}
+		return results, nil
+	}
+
+	for k := len(p.segments) - 1; k >= 0; k-- {
+		bodies := []*hclwrite.Body{n.body}
+		if k > 0 {
+			blocks, err := p.findBlocks(n.body, p.segments[:k])
+			if err != nil {
+				return nil, err
+			}
+			bodies = []*hclwrite.Body{}
+			for _, b := range blocks {
+				bodies = append(bodies, b.Body())
+			}
+		}
+
+		results := []queryNode{}
+		s := p.segments[k]
+		for _, body := range bodies {
+			attr := body.GetAttribute(s.name())
+			if attr == nil {
+				continue
+			}
+			elem, err := locateElement(attributeMatch{name: s.name(), attr: attr, body: body}, nil)
+			if err != nil {
+				return nil, err
+			}
+			rest := append([]querySegment{{indexes: s.indexes}}, p.segments[k+1:]...)
+			results = append(results, walkElement(elem, rest)...)
+		}
+		if len(results) != 0 {
+			return results, nil
+		}
+	}
+
+	return []queryNode{}, nil
+}
+
+// findBlocks returns blocks at given segments of the path.
+// An iterator on a block path iterates the blocks matched so far, even if they
+// have more labels than the path such as .resource[], and the rest of the
+// segments is resolved in each of them.
+func (p *queryPath) findBlocks(body *hclwrite.Body, segments []querySegment) ([]*hclwrite.Block, error) {
+	for k, s := range segments {
+		for j, i := range s.indexes {
+			if i != queryIterate {
+				continue
+			}
+
+			head := append(append([]querySegment{}, segments[:k]...), querySegment{segment: s.segment, indexes: s.indexes[:j]})
+			blocks, err := findBlocksByPrefix(body, p.address(head))
+			if err != nil {
+				return nil, err
+			}
+
+			rest := segments[k+1:]
+			for _, i := range s.indexes[j+1:] {
+				// an index after an iterator selects one of the iterated blocks.
+				if i != queryIterate {
+					blocks = selectIndex(blocks, i)
+				}
+			}
+			if len(rest) == 0 {
+				return blocks, nil
+			}
+
+			results := []*hclwrite.Block{}
+			for _, b := range blocks {
+				nested, err := p.findBlocks(b.Body(), rest)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, nested...)
+			}
+			return results, nil
+		}
+	}
+
+	return findLongestMatchingBlocks(body, p.address(segments))
+}
+
+// address returns an address of blocks for given segments without iterators.
+func (p *queryPath) address(segments []querySegment) string {
+	a := []string{}
+	for _, s := range segments {
+		if len(s.segment) != 0 {
+			a = append(a, s.segment)
+		}
+		for _, i := range s.indexes {
+			if len(a) == 0 {
+				a = append(a, wildcardSegment)
+				continue
+			}
+			a[len(a)-1] += fmt.Sprintf("[%d]", i)
+		}
+	}
+	return strings.Join(a, ".")
+}
+
+// walkElement returns elements at given segments in a value.
+func walkElement(elem *elementMatch, segments []querySegment) []queryNode {
+	current := []*elementMatch{elem}
+	for _, s := range segments {
+		if len(s.segment) != 0 {
+			next := []*elementMatch{}
+			for _, m := range current {
+				if c := m.child(valueStep{key: s.name(), index: -1}); c != nil {
+					next = append(next, c)
+				}
+			}
+			current = next
+		}
+
+		for _, i := range s.indexes {
+			next := []*elementMatch{}
+			for _, m := range current {
+				if i == queryIterate {
+					next = append(next, m.children()...)
+					continue
+				}
+				if c := m.child(valueStep{index: i}); c != nil {
+					next = append(next, c)
+				}
+			}
+			current = next
+		}
+	}
+
+	results := []queryNode{}
+	for _, m := range current {
+		results = append(results, queryNode{elem: m})
+	}
+	return results
+}
+
+// children returns all elements of a list or values of an object.
+func (m *elementMatch) children() []*elementMatch {
+	children := []*elementMatch{}
+	switch e := m.expr.(type) {
+	case *hclsyntax.TupleConsExpr:
+		for i := range e.Exprs {
+			children = append(children, m.child(valueStep{index: i}))
+		}
+	case *hclsyntax.ObjectConsExpr:
+		for _, item := range e.Items {
+			if c := m.child(valueStep{key: objectKey(item.KeyExpr), index: -1}); c != nil {
+				children = append(children, c)
+			}
+		}
+	}
+	return children
+}
+
+// querySelect is a filter which passes the input only if a condition holds.
+type querySelect struct {
+	path *queryPath
+	// op is a comparison operator, which is either ==, != or empty.
+	// If empty, the input passes if the path exists and is truthy.
+	op string
+	// literal is a value to be compared.
+	literal cty.Value
+}
+
+// apply returns the input if the condition holds, or nothing otherwise.
+func (s *querySelect) apply(n queryNode) ([]queryNode, error) {
+	results, err := s.path.apply(n)
+	if err != nil {
+		return nil, err
+	}
+
+	// A missing value is treated as null.
+	v := cty.NullVal(cty.DynamicPseudoType)
+	// src is a source of the value used for comparing non-literal values.
+	src := "null"
+	if len(results) != 0 {
+		r := results[0]
+		if r.elem == nil {
+			// a block is not null, but cannot be compared.
+			v = cty.EmptyObjectVal
+			src = ""
+		} else {
+			src = r.elem.value()
+			if lv, ok := queryLiteral(src); ok {
+				v = lv
+			} else {
+				v = cty.UnknownVal(cty.DynamicPseudoType)
+			}
+		}
+	}
+
+	var ok bool
+	switch s.op {
+	case "":
+		ok = !v.IsKnown() || (!v.IsNull() && !v.RawEquals(cty.False))
+	case "==", "!=":
+		if v.IsKnown() {
+			ok = v.Equals(s.literal).True()
+		} else {
+			ok = false
+		}
+		if s.op == "!=" {
+			ok = !ok
+		}
+	}
+
+	if !ok {
+		return []queryNode{}, nil
+	}
+	return []queryNode{n}, nil
+}
+
+// queryLiteral returns a value of a given source if it is a literal such as
+// a string, a number, a bool or null. Otherwise it returns false.
+func queryLiteral(src string) (cty.Value, bool) {
+	expr, diags := hclsyntax.ParseExpression([]byte(src), "", hcl.Pos{Line: 1, Column: 1})
+	if diags.HasErrors() {
+		return cty.NilVal, false
+	}
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() {
+		return cty.NilVal, false
+	}
+	return v, true
+}
+
+// parseQuery parses a query into a list of filters.
+func parseQuery(query string) ([]queryFilter, error) {
+	filters := []queryFilter{}
+	for _, stage := range splitQuery(query, "|") {
+		stage = strings.TrimSpace(stage)
+		var f queryFilter
+		var err error
+		if strings.HasPrefix(stage, "select(") && strings.HasSuffix(stage, ")") {
+			f, err = parseQuerySelect(strings.TrimSuffix(strings.TrimPrefix(stage, "select("), ")"))
+		} else {
+			f, err = parseQueryPath(stage)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse query: %s", err)
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+// parseQuerySelect parses a condition of select.
+func parseQuerySelect(cond string) (*querySelect, error) {
+	for _, op := range []string{"==", "!="} {
+		parts := splitQuery(cond, op)
+		if len(parts) == 1 {
+			continue
+		}
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("too many operators: %s", cond)
+		}
+
+		path, err := parseQueryPath(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		literal, ok := queryLiteral(strings.TrimSpace(parts[1]))
+		if !ok {
+			return nil, fmt.Errorf("expected a literal: %s", parts[1])
+		}
+		return &querySelect{path: path, op: op, literal: literal}, nil
+	}
+
+	path, err := parseQueryPath(strings.TrimSpace(cond))
+	if err != nil {
+		return nil, err
+	}
+	return &querySelect{path: path}, nil
+}
+
+// parseQueryPath parses a path such as .a."b.c"[0][].
+func parseQueryPath(path string) (*queryPath, error) {
+	if !strings.HasPrefix(path, ".") {
+		return nil, fmt.Errorf("a path must start with a dot: %q", path)
+	}
+
+	p := &queryPath{segments: []querySegment{}}
+	if path == "." {
+		return p, nil
+	}
+
+	rest := path
+	for len(rest) != 0 {
+		if rest[0] != '.' {
+			return nil, fmt.Errorf("unexpected character in path: %s", path)
+		}
+		rest = rest[1:]
+
+		var s querySegment
+		if strings.HasPrefix(rest, `"`) {
+			end := indexQuoteEnd(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed quote in path: %s", path)
+			}
+			s.segment = rest[:end+1]
+			rest = rest[end+1:]
+		} else {
+			i := strings.IndexAny(rest, ".[")
+			if i < 0 {
+				i = len(rest)
+			}
+			s.segment = rest[:i]
+			rest = rest[i:]
+		}
+
+		for strings.HasPrefix(rest, "[") {
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed bracket in path: %s", path)
+			}
+			index := queryIterate
+			if inner := rest[1:end]; len(inner) != 0 {
+				n, err := strconv.Atoi(inner)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid index in path: %s", path)
+				}
+				index = n
+			}
+			s.indexes = append(s.indexes, index)
+			rest = rest[end+1:]
+		}
+
+		if len(s.segment) == 0 && len(s.indexes) == 0 {
+			return nil, fmt.Errorf("empty segment in path: %s", path)
+		}
+		p.segments = append(p.segments, s)
+	}
+
+	return p, nil
+}
+
+// splitQuery splits a query by a given separator outside of quotes and
+// parentheses.
+func splitQuery(query string, sep string) []string {
+	parts := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(query[i:], sep):
+			parts = append(parts, query[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, query[start:])
+}
@@ -0,0 +1,189 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	src := `resource "aws_instance" "web" {
+  ami           = "ami-123"
+  instance_type = "t3.micro"
+  tags = {
+    Name = "web"
+    Env  = "prod"
+  }
+}
+
+resource "aws_instance" "db" {
+  instance_type = "t3.large"
+  subnets       = ["a", "b"]
+}
+
+resource "aws_s3_bucket" "logs" {
+  versioning {
+    enabled = true
+  }
+}
+`
+
+	cases := []struct {
+		name  string
+		src   string
+		query string
+		ok    bool
+		want  string
+	}{
+		{
+			name:  "path to attribute",
+			src:   src,
+			query: ".resource.aws_instance.web.ami",
+			ok:    true,
+			want:  "\"ami-123\"\n",
+		},
+		{
+			name:  "iterate blocks",
+			src:   src,
+			query: ".resource.aws_instance[] | .instance_type",
+			ok:    true,
+			want:  "\"t3.micro\"\n\"t3.large\"\n",
+		},
+		{
+			name:  "iterate blocks by type",
+			src:   src,
+			query: ".resource[] | .instance_type",
+			ok:    true,
+			want:  "\"t3.micro\"\n\"t3.large\"\n",
+		},
+		{
+			name:  "iterate blocks by a partial label path",
+			src:   src,
+			query: ".resource.aws_s3_bucket[].versioning.enabled",
+			ok:    true,
+			want:  "true\n",
+		},
+		{
+			name: "iterate unlabeled nested blocks",
+			src: `resource "aws_security_group" "web" {
+  ingress {
+    from_port = 80
+  }
+  ingress {
+    from_port = 443
+  }
+}
+`,
+			query: ".resource.aws_security_group.web.ingress[] | .from_port",
+			ok:    true,
+			want:  "80\n443\n",
+		},
+		{
+			name: "index after iterator",
+			src: `resource "aws_security_group" "web" {
+  ingress {
+    from_port = 80
+  }
+  ingress {
+    from_port = 443
+  }
+}
+`,
+			query: ".resource.aws_security_group.web.ingress[][1].from_port",
+			ok:    true,
+			want:  "443\n",
+		},
+		{
+			name:  "select exists",
+			src:   src,
+			query: ".resource.aws_instance[] | select(.ami != null) | .ami",
+			ok:    true,
+			want:  "\"ami-123\"\n",
+		},
+		{
+			name:  "select equal",
+			src:   src,
+			query: `.resource.aws_instance[] | select(.instance_type == "t3.large") | .subnets[1]`,
+			ok:    true,
+			want:  "\"b\"\n",
+		},
+		{
+			name:  "select truthy",
+			src:   src,
+			query: ".resource.aws_s3_bucket[] | select(.versioning.enabled) | .versioning.enabled",
+			ok:    true,
+			want:  "true\n",
+		},
+		{
+			name:  "object keys",
+			src:   src,
+			query: ".resource.aws_instance.web.tags.Name",
+			ok:    true,
+			want:  "\"web\"\n",
+		},
+		{
+			name:  "iterate values",
+			src:   src,
+			query: ".resource.aws_instance.web.tags[]",
+			ok:    true,
+			want:  "\"web\"\n\"prod\"\n",
+		},
+		{
+			name:  "iterate list",
+			src:   src,
+			query: ".resource.aws_instance.db | .subnets | .[]",
+			ok:    true,
+			want:  "\"a\"\n\"b\"\n",
+		},
+		{
+			name:  "block",
+			src:   src,
+			query: ".resource.aws_s3_bucket.logs.versioning",
+			ok:    true,
+			want: `  versioning {
+    enabled = true
+  }
+`,
+		},
+		{
+			name:  "not found",
+			src:   src,
+			query: ".resource.aws_instance.web.foo",
+			ok:    true,
+			want:  "",
+		},
+		{
+			name:  "invalid path",
+			src:   src,
+			query: "resource",
+			ok:    false,
+			want:  "",
+		},
+		{
+			name:  "invalid literal",
+			src:   src,
+			query: ".resource.aws_instance[] | select(.ami == foo)",
+			ok:    false,
+			want:  "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := Query(inStream, outStream, "test", tc.query)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package editor
+
+import (
+	"unicode/utf8"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// sourceRanges is a record of source ranges of tokens in the input.
+// The hclwrite doesn't retain positions of tokens, so we calculate them when
+// parsing the input. Tokens are looked up by identity, so they must be built
+// from the parsed tree. A filter which moves items into a new file keeps the
+// identity of tokens.
+type sourceRanges struct {
+	// filename is a metadata of input stream and used only for reporting ranges.
+	filename string
+	ranges   map[*hclwrite.Token]hcl.Range
+}
+
+// record calculates source ranges of all tokens in a given file.
+// The hclwrite parser is lossless, so the positions are reconstructed from
+// the leading spaces and bytes of each token.
+func (s *sourceRanges) record(f *hclwrite.File) {
+	s.ranges = make(map[*hclwrite.Token]hcl.Range)
+	pos := hcl.Pos{Line: 1, Column: 1, Byte: 0}
+	for _, t := range f.BuildTokens(nil) {
+		pos.Byte += t.SpacesBefore
+		pos.Column += t.SpacesBefore
+		start := pos
+		for b := t.Bytes; len(b) > 0; {
+			r, size := utf8.DecodeRune(b)
+			b = b[size:]
+			pos.Byte += size
+			if r == '\n' {
+				pos.Line++
+				pos.Column = 1
+			} else {
+				pos.Column++
+			}
+		}
+		s.ranges[t] = hcl.Range{Filename: s.filename, Start: start, End: pos}
+	}
+}
+
+// rangeOf returns a source range of given tokens.
+// Leading and trailing comments and newlines are excluded, so that the range
+// points to the item itself. It returns false if the tokens are not found.
+func (s *sourceRanges) rangeOf(tokens hclwrite.Tokens) (hcl.Range, bool) {
+	begin := 0
+	for begin < len(tokens) && isTrivia(tokens[begin]) {
+		begin++
+	}
+	end := len(tokens)
+	for end > begin && isTrivia(tokens[end-1]) {
+		end--
+	}
+
+	if begin == end {
+		return hcl.Range{}, false
+	}
+
+	first, ok := s.ranges[tokens[begin]]
+	if !ok {
+		return hcl.Range{}, false
+	}
+	last, ok := s.ranges[tokens[end-1]]
+	if !ok {
+		return hcl.Range{}, false
+	}
+
+	return hcl.Range{Filename: s.filename, Start: first.Start, End: last.End}, true
+}
+
+// isTrivia returns true if a given token is a comment, a newline or an EOF.
+func isTrivia(t *hclwrite.Token) bool {
+	switch t.Type {
+	case hclsyntax.TokenComment, hclsyntax.TokenNewline, hclsyntax.TokenEOF:
+		return true
+	}
+	return false
+}
+
+// rangeParser is a Source implementation to parse HCL and record source
+// ranges of tokens.
+type rangeParser struct {
+	parser
+	ranges *sourceRanges
+}
+
+// Source parses HCL and returns *hclwrite.File
+func (p *rangeParser) Source(src []byte) (*hclwrite.File, error) {
+	f, err := p.parser.Source(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p.ranges.record(f)
+	return f, nil
+}
@@ -0,0 +1,92 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestSourceRangesRangeOf(t *testing.T) {
+	src := `# comment
+a0 = v0
+b1 "é" {
+  a1 = "ü" # comment
+}
+`
+	f, err := safeParseConfig([]byte(src), "test", hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		t.Fatalf("failed to parse input: %s", err)
+	}
+
+	ranges := &sourceRanges{filename: "test"}
+	ranges.record(f)
+
+	cases := []struct {
+		name string
+		got  func() (hcl.Range, bool)
+		ok   bool
+		want hcl.Range
+	}{
+		{
+			name: "attribute with leading comments",
+			got: func() (hcl.Range, bool) {
+				return ranges.rangeOf(f.Body().GetAttribute("a0").BuildTokens(nil))
+			},
+			ok: true,
+			want: hcl.Range{
+				Filename: "test",
+				Start:    hcl.Pos{Line: 2, Column: 1, Byte: 10},
+				End:      hcl.Pos{Line: 2, Column: 8, Byte: 17},
+			},
+		},
+		{
+			name: "block with multibyte label",
+			got: func() (hcl.Range, bool) {
+				return ranges.rangeOf(f.Body().Blocks()[0].BuildTokens(nil))
+			},
+			ok: true,
+			want: hcl.Range{
+				Filename: "test",
+				Start:    hcl.Pos{Line: 3, Column: 1, Byte: 18},
+				End:      hcl.Pos{Line: 5, Column: 2, Byte: 51},
+			},
+		},
+		{
+			name: "nested attribute with trailing comment",
+			got: func() (hcl.Range, bool) {
+				return ranges.rangeOf(f.Body().Blocks()[0].Body().GetAttribute("a1").BuildTokens(nil))
+			},
+			ok: true,
+			want: hcl.Range{
+				Filename: "test",
+				Start:    hcl.Pos{Line: 4, Column: 3, Byte: 30},
+				End:      hcl.Pos{Line: 4, Column: 11, Byte: 39},
+			},
+		},
+		{
+			name: "tokens not in the tree",
+			got: func() (hcl.Range, bool) {
+				other, err := safeParseConfig([]byte("a0 = v0\n"), "other", hcl.Pos{Line: 1, Column: 1})
+				if err != nil {
+					t.Fatalf("failed to parse input: %s", err)
+				}
+				return ranges.rangeOf(other.BuildTokens(nil))
+			},
+			ok:   false,
+			want: hcl.Range{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := tc.got()
+			if ok != tc.ok {
+				t.Fatalf("got ok = %t, want ok = %t", ok, tc.ok)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
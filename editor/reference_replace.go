@@ -0,0 +1,97 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// ReplaceReference reads HCL from io.Reader, and renames references in
+// expressions of all attributes, and writes the updated HCL to io.Writer.
+// The from and to are dot-separated names following the root of traversal.
+// For example, given from = "x" and to = "y", var.x is renamed to var.y and
+// local.x.z is renamed to local.y.z.
+// If rootFilter is not empty, only traversals whose root name equals to
+// rootFilter are eligible. This allows users to scope rewrites to a namespace
+// such as var or local.
+// Matching is segment-wise from the beginning of the traversal, so var.xx is
+// not renamed for from = "x".
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ReplaceReference(r io.Reader, w io.Writer, filename string, from string, to string, rootFilter string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&referenceReplace{from: from, to: to, rootFilter: rootFilter},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// referenceReplace is a filter implementation for renaming references.
+type referenceReplace struct {
+	from       string
+	to         string
+	rootFilter string
+}
+
+// Filter reads HCL and renames matched references in all attributes.
+func (f *referenceReplace) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	if len(f.from) == 0 || len(f.to) == 0 {
+		return nil, fmt.Errorf("failed to replace reference. from and to must not be empty: from = %s, to = %s", f.from, f.to)
+	}
+
+	from := strings.Split(f.from, ".")
+	to := strings.Split(f.to, ".")
+	if len(from) != len(to) {
+		return nil, fmt.Errorf("failed to replace reference. from and to must have the same number of segments: from = %s, to = %s", f.from, f.to)
+	}
+
+	replaceReferenceInBody(inFile.Body(), from, to, f.rootFilter)
+
+	return inFile, nil
+}
+
+// replaceReferenceInBody renames references in attributes of a given body and
+// nested blocks recursively.
+func replaceReferenceInBody(body *hclwrite.Body, from []string, to []string, rootFilter string) {
+	for _, attr := range body.Attributes() {
+		expr := attr.Expr()
+		for _, root := range traversalRoots(expr) {
+			if len(rootFilter) != 0 && root != rootFilter {
+				continue
+			}
+			search := append([]string{root}, from...)
+			replacement := append([]string{root}, to...)
+			expr.RenameVariablePrefix(search, replacement)
+		}
+	}
+
+	for _, b := range body.Blocks() {
+		replaceReferenceInBody(b.Body(), from, to, rootFilter)
+	}
+}
+
+// traversalRoots returns unique root names of traversals in a given expression.
+func traversalRoots(expr *hclwrite.Expression) []string {
+	roots := []string{}
+	seen := make(map[string]bool)
+	for _, t := range expr.Variables() {
+		tokens := t.BuildTokens(nil)
+		if len(tokens) == 0 {
+			continue
+		}
+		root := string(tokens[0].Bytes)
+		if !seen[root] {
+			seen[root] = true
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
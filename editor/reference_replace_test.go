@@ -0,0 +1,126 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestReplaceReference(t *testing.T) {
+	src := `
+a0 = var.x
+a1 = local.x
+b1 "l1" {
+  a2 = "${var.x.y}-${local.x}"
+  a3 = var.xx
+  b2 {
+    a4 = [var.x, module.x.out]
+  }
+}
+`
+
+	cases := []struct {
+		name       string
+		src        string
+		from       string
+		to         string
+		rootFilter string
+		ok         bool
+		want       string
+	}{
+		{
+			name:       "any root",
+			src:        src,
+			from:       "x",
+			to:         "z",
+			rootFilter: "",
+			ok:         true,
+			want: `
+a0 = var.z
+a1 = local.z
+b1 "l1" {
+  a2 = "${var.z.y}-${local.z}"
+  a3 = var.xx
+  b2 {
+    a4 = [var.z, module.z.out]
+  }
+}
+`,
+		},
+		{
+			name:       "var only",
+			src:        src,
+			from:       "x",
+			to:         "z",
+			rootFilter: "var",
+			ok:         true,
+			want: `
+a0 = var.z
+a1 = local.x
+b1 "l1" {
+  a2 = "${var.z.y}-${local.x}"
+  a3 = var.xx
+  b2 {
+    a4 = [var.z, module.x.out]
+  }
+}
+`,
+		},
+		{
+			name:       "multiple segments",
+			src:        src,
+			from:       "x.out",
+			to:         "x.output",
+			rootFilter: "module",
+			ok:         true,
+			want: `
+a0 = var.x
+a1 = local.x
+b1 "l1" {
+  a2 = "${var.x.y}-${local.x}"
+  a3 = var.xx
+  b2 {
+    a4 = [var.x, module.x.output]
+  }
+}
+`,
+		},
+		{
+			name:       "segments mismatch",
+			src:        src,
+			from:       "x",
+			to:         "x.y",
+			rootFilter: "",
+			ok:         false,
+			want:       "",
+		},
+		{
+			name:       "empty",
+			src:        src,
+			from:       "",
+			to:         "x",
+			rootFilter: "",
+			ok:         false,
+			want:       "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ReplaceReference(inStream, outStream, "test", tc.from, tc.to, tc.rootFilter)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
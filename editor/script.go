@@ -0,0 +1,223 @@
+package editor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// OperationKind is a kind of Operation.
+type OperationKind string
+
+const (
+	// OperationSet sets Value to the attribute at Address.
+	OperationSet OperationKind = "set"
+	// OperationRemove removes the attribute at Address.
+	OperationRemove OperationKind = "rm"
+	// OperationRename renames blocks at Address to a new address of Value.
+	OperationRename OperationKind = "rename"
+	// OperationAppend appends a new block of an address of Value to blocks at
+	// Address. If Address is empty, the new block is appended to the top level.
+	OperationAppend OperationKind = "append"
+	// OperationAppendAttribute appends Value as a new attribute at Address
+	// only if it doesn't already exist.
+	OperationAppendAttribute OperationKind = "append-attribute"
+	// OperationMoveAttribute moves the attribute at Address to a new address
+	// of Value.
+	OperationMoveAttribute OperationKind = "mv-attribute"
+	// OperationRemoveBlock removes blocks at Address.
+	OperationRemoveBlock OperationKind = "rm-block"
+)
+
+// Operation is an edit operation used in a batch script.
+type Operation struct {
+	// Kind is a kind of operation.
+	Kind OperationKind `json:"kind"`
+	// Address is an address of the target attribute or block.
+	Address string `json:"address"`
+	// Value is a value of attribute or a new address of block.
+	// The meaning of Value depends on Kind.
+	Value string `json:"value,omitempty"`
+	// Newline is a flag to insert a new line before a new block.
+	// It is used only for OperationAppend.
+	Newline bool `json:"newline,omitempty"`
+	// Body is an HCL snippet of the body of a new block.
+	// It is used only for OperationAppend.
+	Body string `json:"body,omitempty"`
+}
+
+// ApplyScript reads HCL from io.Reader, applies a given list of operations in
+// order, and writes the updated HCL to io.Writer.
+// All operations are applied to one parsed file, so intermediate results
+// feed later operations.
+// If any of operations fails, it aborts the whole batch and returns an error
+// with the index of the failed operation.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func ApplyScript(r io.Reader, w io.Writer, filename string, script []Operation, opts ...Option) error {
+	filters := []Filter{}
+	for i, op := range script {
+		filter, err := op.BuildFilter()
+		if err != nil {
+			return fmt.Errorf("failed to build operation[%d]: %s", i, err)
+		}
+		filters = append(filters, &operationFilter{index: i, op: op, filter: filter})
+	}
+
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: filters,
+		sink:    &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// ParseScript reads a batch script in a line oriented text format, and
+// returns a list of operations for ApplyScript.
+// Each line is an operation in the form of the hcledit subcommand:
+//
+//	[attribute|block] <OPERATION> <ADDRESS> [<VALUE>]
+//
+// If the leading attribute or block is omitted, attribute is assumed.
+// The supported operations are attribute set, rm, append, mv, and block mv,
+// append, rm. For attribute set and append, the VALUE is the rest of the line, so
+// it can contain spaces. Otherwise it is a single field.
+// Blank lines and lines starting with # are ignored.
+func ParseScript(r io.Reader) ([]Operation, error) {
+	script := []Operation{}
+	scanner := bufio.NewScanner(r)
+	n := 0
+	for scanner.Scan() {
+		n++
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		op, err := parseOperation(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse script at line %d: %s", n, err)
+		}
+		script = append(script, op)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read script: %s", err)
+	}
+
+	return script, nil
+}
+
+// parseOperation parses a line of script into an Operation.
+func parseOperation(line string) (Operation, error) {
+	noun := "attribute"
+	verb, rest := nextField(line)
+	if verb == "attribute" || verb == "block" {
+		noun = verb
+		verb, rest = nextField(rest)
+	}
+
+	address, rest := nextField(rest)
+	if len(address) == 0 {
+		return Operation{}, fmt.Errorf("expected an address: %s", line)
+	}
+
+	var kind OperationKind
+	// valueRest is a flag to take the rest of the line as a value.
+	valueRest := false
+	switch noun + " " + verb {
+	case "attribute set":
+		kind, valueRest = OperationSet, true
+	case "attribute rm":
+		kind = OperationRemove
+	case "attribute append":
+		kind, valueRest = OperationAppendAttribute, true
+	case "attribute mv":
+		kind = OperationMoveAttribute
+	case "block mv":
+		kind = OperationRename
+	case "block append":
+		kind = OperationAppend
+	case "block rm":
+		kind = OperationRemoveBlock
+	default:
+		return Operation{}, fmt.Errorf("unknown operation: %s %s", noun, verb)
+	}
+
+	value := strings.TrimSpace(rest)
+	if !valueRest {
+		var extra string
+		value, extra = nextField(rest)
+		if len(extra) != 0 {
+			return Operation{}, fmt.Errorf("too many arguments: %s", line)
+		}
+	}
+
+	if kind == OperationRemove || kind == OperationRemoveBlock {
+		if len(value) != 0 {
+			return Operation{}, fmt.Errorf("too many arguments: %s", line)
+		}
+	} else if len(value) == 0 {
+		return Operation{}, fmt.Errorf("expected a value: %s", line)
+	}
+
+	return Operation{Kind: kind, Address: address, Value: value}, nil
+}
+
+// nextField splits a given string into the first whitespace separated field
+// and the rest without leading spaces.
+func nextField(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t")
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimLeft(s[i:], " \t")
+}
+
+// BuildFilter returns a built-in Filter implementation corresponding to the
+// operation. This is useful for combining built-in edits with custom filters
+// in a MultiFilter.
+func (op Operation) BuildFilter() (Filter, error) {
+	switch op.Kind {
+	case OperationSet:
+		return &attributeSet{address: op.Address, value: op.Value}, nil
+	case OperationRemove:
+		return &attributeRemove{address: op.Address}, nil
+	case OperationRename:
+		return &blockRename{from: op.Address, to: op.Value}, nil
+	case OperationAppend:
+		return &blockAppend{parent: op.Address, child: op.Value, newline: op.Newline, body: op.Body}, nil
+	case OperationAppendAttribute:
+		return &attributeAppend{address: op.Address, value: op.Value, newline: op.Newline}, nil
+	case OperationMoveAttribute:
+		return &attributeMove{from: op.Address, to: op.Value}, nil
+	case OperationRemoveBlock:
+		return &blockRemove{address: op.Address}, nil
+	default:
+		return nil, fmt.Errorf("unknown operation kind: %s", op.Kind)
+	}
+}
+
+// operationFilter is a filter implementation which wraps a filter of
+// operation to report which operation failed.
+type operationFilter struct {
+	index  int
+	op     Operation
+	filter Filter
+}
+
+// Filter applies the wrapped filter and annotates an error with the index of operation.
+func (f *operationFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	outFile, err := f.filter.Filter(inFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply operation[%d] (%s %s): %s", f.index, f.op.Kind, f.op.Address, err)
+	}
+
+	return outFile, nil
+}
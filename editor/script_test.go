@@ -0,0 +1,195 @@
+package editor
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyScript(t *testing.T) {
+	cases := []struct {
+		name   string
+		src    string
+		script []Operation
+		ok     bool
+		errMsg string
+		want   string
+	}{
+		{
+			name: "intermediate results feed later operations",
+			src: `
+b1 "l1" {
+  a1 = v1
+  a2 = v2
+}
+`,
+			script: []Operation{
+				{Kind: OperationRename, Address: "b1.l1", Value: "b1.l2"},
+				{Kind: OperationSet, Address: "b1.l2.a1", Value: "v3"},
+				{Kind: OperationRemove, Address: "b1.l2.a2"},
+				{Kind: OperationAppend, Address: "b1.l2", Value: "b2", Newline: true},
+			},
+			ok: true,
+			want: `
+b1 "l2" {
+  a1 = v3
+
+  b2 {
+  }
+}
+`,
+		},
+		{
+			name: "append to top level",
+			src: `a0 = v0
+`,
+			script: []Operation{
+				{Kind: OperationAppend, Address: "", Value: "b1.l1"},
+			},
+			ok: true,
+			want: `a0 = v0
+b1 "l1" {
+}
+`,
+		},
+		{
+			name: "empty script",
+			src: `a0 = v0
+`,
+			script: []Operation{},
+			ok:     true,
+			want: `a0 = v0
+`,
+		},
+		{
+			name: "failed operation aborts the whole batch",
+			src: `a0 = v0
+`,
+			script: []Operation{
+				{Kind: OperationSet, Address: "a0", Value: "v1"},
+				{Kind: OperationRename, Address: "", Value: "b1"},
+			},
+			ok:     false,
+			errMsg: "operation[1]",
+			want:   "",
+		},
+		{
+			name: "unknown operation kind",
+			src: `a0 = v0
+`,
+			script: []Operation{
+				{Kind: OperationSet, Address: "a0", Value: "v1"},
+				{Kind: "foo", Address: "a0"},
+			},
+			ok:     false,
+			errMsg: "operation[1]",
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := ApplyScript(inStream, outStream, "test", tc.script)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+				}
+				if !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected an error message containing %q, but got: %s", tc.errMsg, err)
+				}
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	cases := []struct {
+		name   string
+		script string
+		ok     bool
+		want   []Operation
+	}{
+		{
+			name: "simple",
+			script: `# comment
+set b1.l1.a1 "foo bar"
+
+attribute rm b1.l1.a2
+append b1.l1.a3 [1, 2]
+mv b1.l1.a4 b1.l1.a5
+block mv b1.l1 b1.l2
+block append b1.l2 b2
+block rm b3
+`,
+			ok: true,
+			want: []Operation{
+				{Kind: OperationSet, Address: "b1.l1.a1", Value: `"foo bar"`},
+				{Kind: OperationRemove, Address: "b1.l1.a2"},
+				{Kind: OperationAppendAttribute, Address: "b1.l1.a3", Value: "[1, 2]"},
+				{Kind: OperationMoveAttribute, Address: "b1.l1.a4", Value: "b1.l1.a5"},
+				{Kind: OperationRename, Address: "b1.l1", Value: "b1.l2"},
+				{Kind: OperationAppend, Address: "b1.l2", Value: "b2"},
+				{Kind: OperationRemoveBlock, Address: "b3"},
+			},
+		},
+		{
+			name:   "empty",
+			script: "",
+			ok:     true,
+			want:   []Operation{},
+		},
+		{
+			name:   "unknown operation",
+			script: "block set b1 v1",
+			ok:     false,
+			want:   nil,
+		},
+		{
+			name:   "missing address",
+			script: "rm",
+			ok:     false,
+			want:   nil,
+		},
+		{
+			name:   "missing value",
+			script: "set a0",
+			ok:     false,
+			want:   nil,
+		},
+		{
+			name:   "too many arguments",
+			script: "mv a0 a1 a2",
+			ok:     false,
+			want:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseScript(strings.NewReader(tc.script))
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %#v", got)
+			}
+
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("got: %#v, want: %#v", got, tc.want)
+			}
+		})
+	}
+}
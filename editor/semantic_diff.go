@@ -0,0 +1,158 @@
+package editor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// SemanticDiff reads HCL from io.Reader and another HCL to be compared from
+// another io.Reader, and writes a structural diff between them to io.Writer.
+// Blocks are compared by address and attributes are compared by name.
+// Each line of the output is an address prefixed by a kind of change, that is,
+// "+" for an address added in other, "-" for an address removed in other, and
+// "~" for an attribute whose value changed.
+// Values are compared by tokens, so a difference of whitespace, comments and
+// trailing commas is ignored. Items in an added or removed block are not
+// reported individually. If there are multiple blocks with the same address in a body,
+// they are matched in order of appearance and the second and subsequent ones
+// are addressed with an index suffix such as foo[1].
+// Note that filenames are used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SemanticDiff(r io.Reader, w io.Writer, filename string, other io.Reader, otherFilename string) error {
+	src, err := ioutil.ReadAll(other)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %s", err)
+	}
+
+	otherFile, err := safeParseConfig(src, otherFilename, hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return err
+	}
+
+	e := &Editor{
+		source:  &parser{filename: filename},
+		filters: []Filter{},
+		sink:    &semanticDiff{other: otherFile},
+	}
+
+	return e.Apply(r, w)
+}
+
+// semanticDiff is a Sink implementation to write a structural diff.
+type semanticDiff struct {
+	other *hclwrite.File
+}
+
+// Sink reads HCL and writes a structural diff against the other file.
+func (s *semanticDiff) Sink(inFile *hclwrite.File) ([]byte, error) {
+	var out bytes.Buffer
+	diffBody(&out, "", inFile.Body(), s.other.Body())
+
+	return out.Bytes(), nil
+}
+
+// diffBody writes a structural diff between bodies of a and b recursively.
+// A prefix is an address of the block containing them.
+func diffBody(w *bytes.Buffer, prefix string, a *hclwrite.Body, b *hclwrite.Body) {
+	for _, name := range attributeNames(a) {
+		other := b.GetAttribute(name)
+		if other == nil {
+			fmt.Fprintf(w, "- %s\n", joinAddress(prefix, name))
+			continue
+		}
+		if !equalTokens(a.GetAttribute(name).Expr().BuildTokens(nil), other.Expr().BuildTokens(nil)) {
+			fmt.Fprintf(w, "~ %s\n", joinAddress(prefix, name))
+		}
+	}
+	for _, name := range attributeNames(b) {
+		if a.GetAttribute(name) == nil {
+			fmt.Fprintf(w, "+ %s\n", joinAddress(prefix, name))
+		}
+	}
+
+	otherBlocks := b.Blocks()
+	// counts is the number of blocks seen so far for each address.
+	counts := make(map[string]int)
+	for _, blk := range a.Blocks() {
+		addr := toAddress(blk)
+		n := counts[addr]
+		counts[addr]++
+
+		indexed := joinAddress(prefix, indexedAddress(addr, n))
+		if matched := nthBlockByAddress(otherBlocks, addr, n); matched != nil {
+			diffBody(w, indexed, blk.Body(), matched.Body())
+			continue
+		}
+		fmt.Fprintf(w, "- %s\n", indexed)
+	}
+
+	otherCounts := make(map[string]int)
+	for _, blk := range otherBlocks {
+		addr := toAddress(blk)
+		n := otherCounts[addr]
+		otherCounts[addr]++
+
+		if n >= counts[addr] {
+			fmt.Fprintf(w, "+ %s\n", joinAddress(prefix, indexedAddress(addr, n)))
+		}
+	}
+}
+
+// joinAddress returns an address of a given name in a block at a prefix.
+func joinAddress(prefix string, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// indexedAddress returns an address with an index suffix for the n-th block
+// with the same address. The first one has no suffix.
+func indexedAddress(addr string, n int) string {
+	if n == 0 {
+		return addr
+	}
+	return fmt.Sprintf("%s[%d]", addr, n)
+}
+
+// equalTokens returns true if given tokens are the same except for whitespace,
+// newlines, comments and trailing commas.
+func equalTokens(a hclwrite.Tokens, b hclwrite.Tokens) bool {
+	a = significantTokens(a)
+	b = significantTokens(b)
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Type != b[i].Type || !bytes.Equal(a[i].Bytes, b[i].Bytes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// significantTokens returns tokens except for newlines, comments and trailing
+// commas before a closing bracket or brace.
+func significantTokens(tokens hclwrite.Tokens) hclwrite.Tokens {
+	ret := hclwrite.Tokens{}
+	for _, t := range tokens {
+		switch t.Type {
+		case hclsyntax.TokenNewline, hclsyntax.TokenComment:
+			continue
+		case hclsyntax.TokenCBrack, hclsyntax.TokenCBrace:
+			if len(ret) != 0 && ret[len(ret)-1].Type == hclsyntax.TokenComma {
+				ret = ret[:len(ret)-1]
+			}
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSemanticDiff(t *testing.T) {
+	cases := []struct {
+		name  string
+		src   string
+		other string
+		ok    bool
+		want  string
+	}{
+		{
+			name: "no difference except for formatting",
+			src: `
+a = [1,2] # comment
+b {
+  c = 1
+}
+`,
+			other: `a = [
+  1,
+  2,
+]
+b {
+  # comment
+  c   =   1
+}
+`,
+			ok:   true,
+			want: "",
+		},
+		{
+			name: "attributes",
+			src: `
+a = 1
+b = 2
+`,
+			other: `
+b = 3
+c = 4
+`,
+			ok: true,
+			want: `- a
+~ b
++ c
+`,
+		},
+		{
+			name: "blocks",
+			src: `
+resource "foo" "bar" {
+  attr1 = "val1"
+  nested {
+    attr2 = "val2"
+  }
+}
+
+resource "foo" "baz" {
+}
+`,
+			other: `
+resource "foo" "bar" {
+  attr1 = "val1"
+  nested {
+    attr2 = "val3"
+  }
+  nested {
+  }
+}
+
+resource "foo" "qux" {
+  attr1 = "val1"
+}
+`,
+			ok: true,
+			want: `~ resource.foo.bar.nested.attr2
++ resource.foo.bar.nested[1]
+- resource.foo.baz
++ resource.foo.qux
+`,
+		},
+		{
+			name: "invalid other",
+			src: `
+a = 1
+`,
+			other: `
+a =
+`,
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			other := bytes.NewBufferString(tc.other)
+			outStream := new(bytes.Buffer)
+			err := SemanticDiff(inStream, outStream, "a", other, "b")
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
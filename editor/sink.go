@@ -1,6 +1,12 @@
 package editor
 
 import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
@@ -11,6 +17,191 @@ type Sink interface {
 	Sink(*hclwrite.File) ([]byte, error)
 }
 
+// formatMutex serializes calls of hclwrite.Format.
+var formatMutex sync.Mutex
+
+// formatHCL is a wrapper of hclwrite.Format which is safe for concurrent use.
+// The hclwrite formatter writes to a shared sentinel token internally, so
+// concurrent calls are racy when multiple files are processed in parallel.
+func formatHCL(src []byte) []byte {
+	formatMutex.Lock()
+	defer formatMutex.Unlock()
+	return hclwrite.Format(src)
+}
+
+// NewFormatSink returns a Sink which writes formatted HCL as it is.
+// This is the default sink of NewEditor and commands which edit HCL.
+func NewFormatSink() Sink {
+	return &formater{}
+}
+
+// NewValueSink returns a Sink which writes values of top level attributes
+// and formatted contents of top level blocks, one per line in order.
+// Attributes are written before blocks.
+func NewValueSink() Sink {
+	return &itemSink{
+		write: func(items []jsonResult) ([]byte, error) {
+			var out bytes.Buffer
+			for _, item := range items {
+				out.WriteString(item.Value + "\n")
+			}
+			return out.Bytes(), nil
+		},
+	}
+}
+
+// NewJSONSink returns a Sink which writes top level attributes and blocks as
+// a JSON array of objects with their addresses, values and source ranges in
+// the same order as NewValueSink. A source range is omitted if it is unknown,
+// for example, an item is rebuilt by a filter.
+func NewJSONSink() Sink {
+	return &itemSink{write: marshalJSONResults}
+}
+
+// NewTemplateSink returns a Sink which executes a given template for each of
+// top level attributes and blocks in the same order as NewValueSink, and
+// writes the concatenated results. The template can refer to .Address,
+// .Value and .Range of the item, and .Labels of a block. The range has
+// .Filename, .Start and .End, each of which has .Line, .Column and .Byte, and
+// is nil if unknown. Note that no newline is added between items.
+func NewTemplateSink(tmpl *template.Template) Sink {
+	return &itemSink{
+		write: func(items []jsonResult) ([]byte, error) {
+			var out bytes.Buffer
+			for _, item := range items {
+				if err := tmpl.Execute(&out, item); err != nil {
+					return nil, fmt.Errorf("failed to execute template: %s", err)
+				}
+			}
+			return out.Bytes(), nil
+		},
+	}
+}
+
+// MultiSink is a Sink which passes the same input to multiple sinks, and
+// writes their outputs concatenated in order, so that results of multiple
+// queries can be aggregated in one parse.
+// If any of sinks returns an error, it returns the error and nothing is
+// written. An empty MultiSink writes nothing.
+type MultiSink []Sink
+
+// Sink calls sinks in order and concatenates their outputs.
+func (m MultiSink) Sink(inFile *hclwrite.File) ([]byte, error) {
+	return m.sinkWithContext(inFile, newSinkContext())
+}
+
+// sinkWithContext calls sinks in order with a given context and concatenates
+// their outputs.
+func (m MultiSink) sinkWithContext(inFile *hclwrite.File, ctx *sinkContext) ([]byte, error) {
+	var out bytes.Buffer
+	for _, sink := range m {
+		b, err := sinkWithContext(sink, inFile, ctx)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(b)
+	}
+
+	return out.Bytes(), nil
+}
+
+// sinkContext is a context of the pipeline given to sinks, which is not
+// available from the filtered file itself.
+type sinkContext struct {
+	// ranges is source ranges of tokens in the input.
+	ranges *sourceRanges
+	// get is a set of options for getting values.
+	get getOptions
+}
+
+// newSinkContext returns an empty context, where no source range is known.
+func newSinkContext() *sinkContext {
+	return &sinkContext{ranges: &sourceRanges{}}
+}
+
+// contextSink is a Sink which also reads a context of the pipeline.
+// Editor records source ranges of the input only for this kind of sink.
+type contextSink interface {
+	Sink
+	sinkWithContext(inFile *hclwrite.File, ctx *sinkContext) ([]byte, error)
+}
+
+// sinkWithContext calls a given sink with a context if it reads one.
+func sinkWithContext(sink Sink, inFile *hclwrite.File, ctx *sinkContext) ([]byte, error) {
+	if s, ok := sink.(contextSink); ok {
+		return s.sinkWithContext(inFile, ctx)
+	}
+	return sink.Sink(inFile)
+}
+
+// itemSink is a Sink implementation which converts top level attributes and
+// blocks to a list of items, and writes them in a given way.
+type itemSink struct {
+	write func(items []jsonResult) ([]byte, error)
+}
+
+// Sink reads HCL and writes top level attributes and blocks.
+func (s *itemSink) Sink(inFile *hclwrite.File) ([]byte, error) {
+	return s.sinkWithContext(inFile, newSinkContext())
+}
+
+// sinkWithContext reads HCL and writes top level attributes and blocks with
+// their source ranges. Values of attributes are written with options for
+// getting values.
+func (s *itemSink) sinkWithContext(inFile *hclwrite.File, ctx *sinkContext) ([]byte, error) {
+	items := []jsonResult{}
+	body := inFile.Body()
+	for _, name := range attributeNames(body) {
+		attr := body.GetAttribute(name)
+		value, err := ctx.get.formatValue(attr)
+		if err != nil {
+			return nil, err
+		}
+		tokens := attr.BuildTokens(nil)
+		if isBuiltAttribute(attr) {
+			// the name of the attribute is replaced with the address.
+			tokens = attr.Expr().BuildTokens(nil)
+		}
+		items = append(items, newJSONResult(name, value, tokens, ctx.ranges))
+	}
+
+	for _, b := range body.Blocks() {
+		tokens := b.BuildTokens(nil)
+		value := strings.TrimSpace(string(formatHCL(tokens.Bytes())))
+		item := newJSONResult(toAddress(b), value, tokens, ctx.ranges)
+		item.Labels = b.Labels()
+		items = append(items, item)
+	}
+
+	return s.write(items)
+}
+
+// attributeValue returns a value of a given attribute as string.
+// It accepts both an ordinary attribute and an attribute built by filters
+// for getting attributes, which is detected by isBuiltAttribute.
+func attributeValue(attr *hclwrite.Attribute) (string, error) {
+	if isBuiltAttribute(attr) {
+		return getAttributeValueAsString(attr)
+	}
+
+	return getExpressionAsString(attr.Expr()), nil
+}
+
+// isBuiltAttribute returns true if a given attribute is built by filters for
+// getting attributes such as attributeGet, whose expression contains all
+// tokens of the matched attribute including its name and comments. It is
+// detected by the leading identifier and equal tokens, which never appear at
+// the beginning of an expression.
+func isBuiltAttribute(attr *hclwrite.Attribute) bool {
+	tokens := attr.Expr().BuildTokens(nil)
+	i := 0
+	for i < len(tokens) && isTrivia(tokens[i]) {
+		i++
+	}
+
+	return i+1 < len(tokens) && tokens[i].Type == hclsyntax.TokenIdent && tokens[i+1].Type == hclsyntax.TokenEqual
+}
+
 // formater is a Sink implementation to format HCL.
 type formater struct {
 }
@@ -18,7 +209,7 @@ type formater struct {
 // Sink reads HCL and writes formatted contents.
 func (f *formater) Sink(inFile *hclwrite.File) ([]byte, error) {
 	raw := inFile.BuildTokens(nil).Bytes()
-	out := hclwrite.Format(raw)
+	out := formatHCL(raw)
 	return out, nil
 }
 
@@ -42,7 +233,7 @@ func (f *verticalFormater) Sink(inFile *hclwrite.File) ([]byte, error) {
 	vertical := VerticalFormat(tokens)
 
 	// default horizontal format
-	out := hclwrite.Format(vertical.Bytes())
+	out := formatHCL(vertical.Bytes())
 	return out, nil
 }
 
@@ -0,0 +1,178 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
+
+func TestBuiltinSinks(t *testing.T) {
+	src := `a0 = v0
+a1 = "v1" # comment
+b1 "l1" {
+  a2 = v2
+}
+`
+
+	cases := []struct {
+		name string
+		sink Sink
+		want string
+	}{
+		{
+			name: "format",
+			sink: NewFormatSink(),
+			want: src,
+		},
+		{
+			name: "value",
+			sink: NewValueSink(),
+			want: `v0
+"v1"
+b1 "l1" {
+  a2 = v2
+}
+`,
+		},
+		{
+			name: "json",
+			sink: NewJSONSink(),
+			want: `[
+  {
+    "address": "a0",
+    "value": "v0",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 1,
+        "column": 1,
+        "byte": 0
+      },
+      "end": {
+        "line": 1,
+        "column": 8,
+        "byte": 7
+      }
+    }
+  },
+  {
+    "address": "a1",
+    "value": "\"v1\"",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 2,
+        "column": 1,
+        "byte": 8
+      },
+      "end": {
+        "line": 2,
+        "column": 10,
+        "byte": 17
+      }
+    }
+  },
+  {
+    "address": "b1.l1",
+    "value": "b1 \"l1\" {\n  a2 = v2\n}",
+    "range": {
+      "filename": "-",
+      "start": {
+        "line": 3,
+        "column": 1,
+        "byte": 28
+      },
+      "end": {
+        "line": 5,
+        "column": 2,
+        "byte": 49
+      }
+    }
+  }
+]
+`,
+		},
+		{
+			name: "template",
+			sink: NewTemplateSink(template.Must(template.New("test").Parse("{{.Address}}\n"))),
+			want: `a0
+a1
+b1.l1
+`,
+		},
+		{
+			name: "multi",
+			sink: MultiSink{
+				NewTemplateSink(template.Must(template.New("test").Parse("{{.Address}} "))),
+				NewTemplateSink(template.Must(template.New("test").Parse("{{.Value}} "))),
+			},
+			want: `a0 a1 b1.l1 v0 "v1" b1 "l1" {
+  a2 = v2
+} `,
+		},
+		{
+			name: "empty multi",
+			sink: MultiSink{},
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := NewEditor(WithSink(tc.sink)).Apply(inStream, outStream)
+			if err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGetAttributeWithSink(t *testing.T) {
+	src := `b1 "l1" {
+  # comment
+  a1 = { k = "v" } # comment
+}
+`
+
+	cases := []struct {
+		name string
+		sink Sink
+		want string
+	}{
+		{
+			name: "value",
+			sink: NewValueSink(),
+			want: `{ k = "v" }
+`,
+		},
+		{
+			name: "template",
+			sink: NewTemplateSink(template.Must(template.New("test").Parse("{{.Address}}={{.Value}}\n"))),
+			want: `b1.l1.a1={ k = "v" }
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := GetAttribute(inStream, outStream, "test", "b1.l1.a1", WithSink(tc.sink))
+			if err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package editor
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"runtime/debug"
 
@@ -26,6 +28,18 @@ func (p *parser) Source(src []byte) (*hclwrite.File, error) {
 	return safeParseConfig(src, p.filename, hcl.Pos{Line: 1, Column: 1})
 }
 
+// parseInput reads HCL from io.Reader and parses it.
+// This is useful for read-only operations which don't write any output.
+func parseInput(r io.Reader, filename string) (*hclwrite.File, error) {
+	input, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input: %s", err)
+	}
+
+	source := &parser{filename: filename}
+	return source.Source(input)
+}
+
 // safeParseConfig parses config and recovers if panic occurs.
 // The current hclwrite implementation is no perfect and will panic if
 // unparseable input is given. We just treat it as a parse error so as not to
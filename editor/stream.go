@@ -0,0 +1,193 @@
+package editor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// WithStream returns an Option to process the input in streaming mode, which
+// is useful for very large files such as generated Terraform configurations.
+// The input is split into chunks of top level attributes and blocks, and each
+// chunk is parsed separately. Chunks which cannot match the address of the
+// operation are written as they are without formatting, and only the matched
+// chunks are edited and formatted. Thus the memory usage is bounded by the
+// size of the largest chunk instead of the whole file. Since each chunk is
+// formatted separately, alignment across top level attributes is not fixed.
+// Only operations whose effect is limited to each top level item support
+// streaming, such as SetAttribute and RemoveAttribute, and others return an
+// error. An address with an index suffix is not supported either, because
+// it depends on preceding blocks.
+// Note that unlike the default mode, a part of the output may be written if
+// an error occurs.
+func WithStream() Option {
+	return func(e *Editor) {
+		e.stream = true
+	}
+}
+
+// maxStreamRetries is the max number of times to parse a chunk again after
+// reading more lines. Without the limit, malformed input would be parsed again
+// for each line until the end of the input, which takes quadratic time.
+const maxStreamRetries = 100
+
+// applyStream reads an input stream chunk by chunk, applies filters to
+// chunks which may match streamAddress, and writes an output stream.
+func (e *Editor) applyStream(r io.Reader, w io.Writer) error {
+	if e.err != nil {
+		return e.err
+	}
+	if len(e.streamAddress) == 0 {
+		return fmt.Errorf("streaming is not supported for this operation")
+	}
+	source, ok := e.source.(*parser)
+	if !ok {
+		return fmt.Errorf("streaming is not supported for this source")
+	}
+	match, err := streamMatcher(e.streamAddress)
+	if err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	var chunk bytes.Buffer
+	// start is a position of the beginning of the current chunk.
+	start := hcl.Pos{Line: 1, Column: 1, Byte: 0}
+	lines := 0
+	depth := 0
+	content := false
+	retries := 0
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("failed to read input: %s", readErr)
+		}
+		eof := readErr == io.EOF
+
+		chunk.Write(line)
+		if len(line) != 0 {
+			lines++
+			depth += bracketDepth(line)
+			if !isTriviaLine(line) {
+				content = true
+			}
+		}
+
+		if !content {
+			if eof {
+				// write trailing comments and blank lines as they are.
+				return writeChunk(w, chunk.Bytes())
+			}
+			continue
+		}
+
+		// A closing brace at the beginning of line is also a candidate of the
+		// end of a top level block in the canonical format.
+		if depth > 0 && !bytes.HasPrefix(line, []byte("}")) && !eof {
+			continue
+		}
+
+		inFile, err := safeParseConfig(chunk.Bytes(), source.filename, start)
+		if err != nil {
+			if eof || retries >= maxStreamRetries {
+				return err
+			}
+			// The chunk may be incomplete, because brackets in strings and
+			// heredocs are also counted. Read the next line and try again.
+			retries++
+			continue
+		}
+
+		out := chunk.Bytes()
+		if match(inFile) {
+			out, err = e.applyFile(inFile)
+			if err != nil {
+				return err
+			}
+		}
+		if err := writeChunk(w, out); err != nil {
+			return err
+		}
+
+		if eof {
+			return nil
+		}
+
+		start = hcl.Pos{Line: start.Line + lines, Column: 1, Byte: start.Byte + chunk.Len()}
+		chunk.Reset()
+		lines = 0
+		depth = 0
+		content = false
+		retries = 0
+	}
+}
+
+// writeChunk writes a given chunk to the output stream.
+func writeChunk(w io.Writer, chunk []byte) error {
+	if _, err := w.Write(chunk); err != nil {
+		return fmt.Errorf("failed to write output: %s", err)
+	}
+	return nil
+}
+
+// streamMatcher returns a function which reports whether a given chunk may
+// contain an attribute or a block at a given address.
+// If the first segment of the address is a pattern, all chunks may match.
+func streamMatcher(address string) (func(*hclwrite.File) bool, error) {
+	a, err := splitAddress(address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range a {
+		if _, index, err := parseIndexedSegment(s); err != nil || index >= 0 {
+			return nil, fmt.Errorf("streaming is not supported for an address with an index: %s", address)
+		}
+	}
+
+	if containsPattern(a[:1]) {
+		return func(*hclwrite.File) bool { return true }, nil
+	}
+
+	name := unquoteSegment(a[0])
+	return func(inFile *hclwrite.File) bool {
+		body := inFile.Body()
+		if body.GetAttribute(name) != nil {
+			return true
+		}
+		for _, b := range body.Blocks() {
+			if b.Type() == name {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// bracketDepth returns the number of opening brackets minus the number of
+// closing brackets in a given line. Brackets in strings and comments are
+// also counted, so it is used only for finding candidates of the end of
+// chunk.
+func bracketDepth(line []byte) int {
+	depth := 0
+	for _, c := range line {
+		switch c {
+		case '{', '[', '(':
+			depth++
+		case '}', ']', ')':
+			depth--
+		}
+	}
+	return depth
+}
+
+// isTriviaLine returns true if a given line is blank or a single line
+// comment, which should be attached to the next item.
+func isTriviaLine(line []byte) bool {
+	trimmed := bytes.TrimSpace(line)
+	return len(trimmed) == 0 || bytes.HasPrefix(trimmed, []byte("#")) || bytes.HasPrefix(trimmed, []byte("//"))
+}
@@ -0,0 +1,230 @@
+package editor
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStream(t *testing.T) {
+	src := `# leading comment
+b1 "l1" {
+  a1   =   "{"
+}
+
+b2 "l2" {
+  a1 = <<EOT
+}
+EOT
+  a2 = v2
+}
+
+// unrelated blocks are not formatted
+b3   "l3" {
+  a1 =   v1
+}
+a0 = v0
+`
+
+	cases := []struct {
+		name   string
+		src    string
+		edit   func(r *bytes.Buffer, w *bytes.Buffer) error
+		ok     bool
+		errMsg string
+		want   string
+	}{
+		{
+			name: "set attribute in matched chunk only",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return SetAttribute(r, w, "test", "b2.l2.a2", "v3", WithStream())
+			},
+			ok: true,
+			want: `# leading comment
+b1 "l1" {
+  a1   =   "{"
+}
+
+b2 "l2" {
+  a1 = <<EOT
+}
+EOT
+  a2 = v3
+}
+
+// unrelated blocks are not formatted
+b3   "l3" {
+  a1 =   v1
+}
+a0 = v0
+`,
+		},
+		{
+			name: "set attribute with a string containing a bracket",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return SetAttribute(r, w, "test", "b1.l1.a1", `"}"`, WithStream())
+			},
+			ok: true,
+			want: `# leading comment
+b1 "l1" {
+  a1 = "}"
+}
+
+b2 "l2" {
+  a1 = <<EOT
+}
+EOT
+  a2 = v2
+}
+
+// unrelated blocks are not formatted
+b3   "l3" {
+  a1 =   v1
+}
+a0 = v0
+`,
+		},
+		{
+			name: "remove top level attribute",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return RemoveAttribute(r, w, "test", "a0", WithStream())
+			},
+			ok: true,
+			want: `# leading comment
+b1 "l1" {
+  a1   =   "{"
+}
+
+b2 "l2" {
+  a1 = <<EOT
+}
+EOT
+  a2 = v2
+}
+
+// unrelated blocks are not formatted
+b3   "l3" {
+  a1 =   v1
+}
+`,
+		},
+		{
+			name: "wildcard",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return RemoveAttribute(r, w, "test", "*.l3.a1", WithStream())
+			},
+			ok: true,
+			want: `# leading comment
+b1 "l1" {
+  a1 = "{"
+}
+
+b2 "l2" {
+  a1 = <<EOT
+}
+EOT
+  a2 = v2
+}
+
+// unrelated blocks are not formatted
+b3 "l3" {
+}
+a0 = v0
+`,
+		},
+		{
+			name: "parse error with line number",
+			src: `b1 {
+}
+b2 {
+  a1 =
+}
+`,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return SetAttribute(r, w, "test", "b2.a1", "v1", WithStream())
+			},
+			ok:     false,
+			errMsg: "test:4",
+			want: `b1 {
+}
+`,
+		},
+		{
+			name: "index is not supported",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return SetAttribute(r, w, "test", "b1[0].a1", "v1", WithStream())
+			},
+			ok:     false,
+			errMsg: "index",
+			want:   "",
+		},
+		{
+			name: "operation is not supported",
+			src:  src,
+			edit: func(r *bytes.Buffer, w *bytes.Buffer) error {
+				return RemoveAttributes(r, w, "test", []string{"a0"}, WithStream())
+			},
+			ok:     false,
+			errMsg: "not supported",
+			want:   "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := tc.edit(inStream, outStream)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok {
+				if err == nil {
+					t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+				}
+				if !strings.Contains(err.Error(), tc.errMsg) {
+					t.Fatalf("expected an error message containing %q, but got: %s", tc.errMsg, err)
+				}
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+// endlessReader is an io.Reader which repeats a given line forever.
+type endlessReader struct {
+	line []byte
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	n := 0
+	for n+len(r.line) <= len(p) {
+		n += copy(p[n:], r.line)
+	}
+	return n, nil
+}
+
+func TestStreamMalformedInput(t *testing.T) {
+	// Without a limit of retries, it would read the endless input forever.
+	r := io.MultiReader(strings.NewReader("b1 {\n  a1 =\n}\n"), &endlessReader{line: []byte("a0 = v0\n")})
+	outStream := new(bytes.Buffer)
+	err := SetAttribute(r, outStream, "test", "b1.a1", "v1", WithStream())
+	if err == nil {
+		t.Fatalf("expected to return an error, but no error, outStream: \n%s", outStream)
+	}
+
+	if !strings.Contains(err.Error(), "test:2") {
+		t.Fatalf("expected an error message containing %q, but got: %s", "test:2", err)
+	}
+}
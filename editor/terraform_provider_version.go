@@ -0,0 +1,144 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SetProviderVersion reads HCL from io.Reader, and sets a given version
+// constraint to an entry of a given provider in required_providers of the
+// terraform block, and writes the updated HCL to io.Writer.
+// The constraint is validated before writing. If source is not empty, the
+// source of the provider is also set.
+// Missing structure is created as needed, that is, the terraform block, the
+// required_providers block and the entry of the provider. An existing entry
+// in the object form is updated in place, and the rest of keys are kept.
+// An entry in the legacy string form is updated as it is, or converted to the
+// object form if source is given.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetProviderVersion(r io.Reader, w io.Writer, filename string, name string, constraint string, source string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&providerVersionSet{name: name, constraint: constraint, source: source},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// providerVersionSet is a filter implementation for setting a version of provider.
+type providerVersionSet struct {
+	name       string
+	constraint string
+	// source is a source address of the provider such as hashicorp/aws.
+	// If empty, the source is not changed.
+	source string
+}
+
+// Filter reads HCL and sets a version of provider in required_providers.
+func (f *providerVersionSet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	if _, err := version.NewConstraint(f.constraint); err != nil {
+		return nil, fmt.Errorf("failed to parse version constraint: %s", err)
+	}
+	if !hclsyntax.ValidIdentifier(f.name) {
+		return nil, fmt.Errorf("failed to set provider version. invalid provider name: %s", f.name)
+	}
+
+	body := inFile.Body()
+	terraforms := findBlocks(body, "terraform", []string{})
+	var providers []*hclwrite.Block
+	for _, b := range terraforms {
+		providers = append(providers, findBlocks(b.Body(), "required_providers", []string{})...)
+	}
+
+	if len(providers) == 0 {
+		if len(terraforms) == 0 {
+			if len(body.Attributes()) != 0 || len(body.Blocks()) != 0 {
+				body.AppendNewline()
+			}
+			terraforms = append(terraforms, body.AppendNewBlock("terraform", nil))
+		}
+		providers = append(providers, terraforms[0].Body().AppendNewBlock("required_providers", nil))
+	}
+
+	// objects is a flag to update keys of entries in the object form.
+	objects := false
+	for _, b := range providers {
+		attr := b.Body().GetAttribute(f.name)
+		if attr == nil {
+			if err := f.setEntry(b.Body()); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		expr, diags := hclsyntax.ParseExpression(attr.Expr().BuildTokens(nil).Bytes(), "", hcl.Pos{Line: 1, Column: 1})
+		if diags.HasErrors() {
+			return nil, fmt.Errorf("failed to parse provider entry: %s", diags)
+		}
+		switch expr.(type) {
+		case *hclsyntax.ObjectConsExpr:
+			objects = true
+		case *hclsyntax.TemplateExpr:
+			if len(f.source) != 0 {
+				if err := f.setEntry(b.Body()); err != nil {
+					return nil, err
+				}
+			} else {
+				b.Body().SetAttributeValue(f.name, cty.StringVal(f.constraint))
+			}
+		default:
+			return nil, fmt.Errorf("failed to set provider version. unsupported provider entry: %s", f.name)
+		}
+	}
+
+	if !objects {
+		return inFile, nil
+	}
+
+	// update keys of the object form in place.
+	address := "terraform.required_providers." + f.name
+	versionValue, err := TypedValue(f.constraint, ValueTypeString)
+	if err != nil {
+		return nil, err
+	}
+	keys := MultiFilter{
+		&attributeSet{address: address + ".version", value: versionValue},
+	}
+	if len(f.source) != 0 {
+		sourceValue, err := TypedValue(f.source, ValueTypeString)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &attributeSet{address: address + ".source", value: sourceValue})
+	}
+
+	return keys.Filter(inFile)
+}
+
+// setEntry sets a new entry of the provider in the object form to a body.
+// Each key is placed on its own line as the Terraform style.
+func (f *providerVersionSet) setEntry(body *hclwrite.Body) error {
+	entry := "{\n"
+	if len(f.source) != 0 {
+		entry += "source = " + string(hclwrite.TokensForValue(cty.StringVal(f.source)).Bytes()) + "\n"
+	}
+	entry += "version = " + string(hclwrite.TokensForValue(cty.StringVal(f.constraint)).Bytes()) + "\n}"
+
+	expr, err := buildExpression(f.name, entry)
+	if err != nil {
+		return err
+	}
+	body.SetAttributeRaw(f.name, expr.BuildTokens(nil))
+	return nil
+}
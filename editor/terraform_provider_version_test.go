@@ -0,0 +1,223 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetProviderVersion(t *testing.T) {
+	cases := []struct {
+		name       string
+		src        string
+		provider   string
+		constraint string
+		source     string
+		ok         bool
+		want       string
+	}{
+		{
+			name: "update object",
+			src: `terraform {
+  required_providers {
+    aws = {
+      # comment
+      source  = "hashicorp/aws"
+      version = "~> 2.0" # trailing
+    }
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "",
+			ok:         true,
+			want: `terraform {
+  required_providers {
+    aws = {
+      # comment
+      source  = "hashicorp/aws"
+      version = "~> 3.0" # trailing
+    }
+  }
+}
+`,
+		},
+		{
+			name: "add version and source to object",
+			src: `terraform {
+  required_providers {
+    aws = {
+      configuration_aliases = [aws.east]
+    }
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "hashicorp/aws",
+			ok:         true,
+			want: `terraform {
+  required_providers {
+    aws = {
+      configuration_aliases = [aws.east]
+      version               = "~> 3.0"
+      source                = "hashicorp/aws"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "legacy string",
+			src: `terraform {
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "",
+			ok:         true,
+			want: `terraform {
+  required_providers {
+    aws = "~> 3.0"
+  }
+}
+`,
+		},
+		{
+			name: "legacy string with source",
+			src: `terraform {
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "hashicorp/aws",
+			ok:         true,
+			want: `terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "add entry",
+			src: `terraform {
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+`,
+			provider:   "google",
+			constraint: "~> 3.0",
+			source:     "hashicorp/google",
+			ok:         true,
+			want: `terraform {
+  required_providers {
+    aws = "~> 2.0"
+    google = {
+      source  = "hashicorp/google"
+      version = "~> 3.0"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "create required_providers",
+			src: `terraform {
+  required_version = ">= 0.13"
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "",
+			ok:         true,
+			want: `terraform {
+  required_version = ">= 0.13"
+  required_providers {
+    aws = {
+      version = "~> 3.0"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "create terraform block",
+			src: `provider "aws" {}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "hashicorp/aws",
+			ok:         true,
+			want: `provider "aws" {}
+
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 3.0"
+    }
+  }
+}
+`,
+		},
+		{
+			name: "invalid constraint",
+			src: `terraform {
+  required_providers {
+    aws = "~> 2.0"
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0.",
+			source:     "",
+			ok:         false,
+			want:       "",
+		},
+		{
+			name: "unsupported entry",
+			src: `terraform {
+  required_providers {
+    aws = local.aws
+  }
+}
+`,
+			provider:   "aws",
+			constraint: "~> 3.0",
+			source:     "",
+			ok:         false,
+			want:       "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetProviderVersion(inStream, outStream, "test", tc.provider, tc.constraint, tc.source)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
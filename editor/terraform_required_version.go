@@ -0,0 +1,62 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SetRequiredVersion reads HCL from io.Reader, and sets a given version
+// constraint to the required_version attribute of the terraform block, and
+// writes the updated HCL to io.Writer.
+// The constraint is validated before writing, so that an invalid constraint
+// such as ">= 0.13." is never written.
+// If the terraform block doesn't have the required_version attribute, it is
+// added. If there is no terraform block and create is true, a new terraform
+// block is appended to the file. Otherwise, the input is written as it is.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func SetRequiredVersion(r io.Reader, w io.Writer, filename string, constraint string, create bool, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&requiredVersionSet{constraint: constraint, create: create},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// requiredVersionSet is a filter implementation for setting required_version.
+type requiredVersionSet struct {
+	constraint string
+	// create is a flag to create the terraform block if not found.
+	create bool
+}
+
+// Filter reads HCL and sets required_version of the terraform block.
+func (f *requiredVersionSet) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	if _, err := version.NewConstraint(f.constraint); err != nil {
+		return nil, fmt.Errorf("failed to parse version constraint: %s", err)
+	}
+
+	body := inFile.Body()
+	blocks := findBlocks(body, "terraform", []string{})
+	if len(blocks) == 0 && f.create {
+		if len(body.Attributes()) != 0 || len(body.Blocks()) != 0 {
+			body.AppendNewline()
+		}
+		blocks = append(blocks, body.AppendNewBlock("terraform", nil))
+	}
+
+	for _, b := range blocks {
+		b.Body().SetAttributeValue("required_version", cty.StringVal(f.constraint))
+	}
+
+	return inFile, nil
+}
@@ -0,0 +1,117 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetRequiredVersion(t *testing.T) {
+	cases := []struct {
+		name       string
+		src        string
+		constraint string
+		create     bool
+		ok         bool
+		want       string
+	}{
+		{
+			name: "update",
+			src: `terraform {
+  # comment
+  required_version = ">= 0.12" # trailing
+  backend "s3" {}
+}
+`,
+			constraint: ">= 0.13",
+			create:     false,
+			ok:         true,
+			want: `terraform {
+  # comment
+  required_version = ">= 0.13" # trailing
+  backend "s3" {}
+}
+`,
+		},
+		{
+			name: "add attribute",
+			src: `terraform {
+  backend "s3" {}
+}
+`,
+			constraint: "~> 0.13.0",
+			create:     false,
+			ok:         true,
+			want: `terraform {
+  backend "s3" {}
+  required_version = "~> 0.13.0"
+}
+`,
+		},
+		{
+			name: "no terraform block",
+			src: `provider "aws" {}
+`,
+			constraint: ">= 0.13",
+			create:     false,
+			ok:         true,
+			want: `provider "aws" {}
+`,
+		},
+		{
+			name: "create",
+			src: `provider "aws" {}
+`,
+			constraint: ">= 0.13",
+			create:     true,
+			ok:         true,
+			want: `provider "aws" {}
+
+terraform {
+  required_version = ">= 0.13"
+}
+`,
+		},
+		{
+			name:       "create in empty file",
+			src:        "",
+			constraint: ">= 0.13, < 0.14",
+			create:     true,
+			ok:         true,
+			want: `terraform {
+  required_version = ">= 0.13, < 0.14"
+}
+`,
+		},
+		{
+			name: "invalid constraint",
+			src: `terraform {
+  required_version = ">= 0.12"
+}
+`,
+			constraint: ">= 0.13.",
+			create:     false,
+			ok:         false,
+			want:       "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := SetRequiredVersion(inStream, outStream, "test", tc.constraint, tc.create)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// TokensFunc is a function which transforms tokens of an attribute or a block.
+// It is a low-level hook for transformations which are not supported natively.
+// The given tokens are a copy and can be modified in place.
+type TokensFunc func(tokens hclwrite.Tokens) hclwrite.Tokens
+
+// transform calls f(tokens) and never returns an error.
+func (f TokensFunc) transform(tokens hclwrite.Tokens) (hclwrite.Tokens, error) {
+	return f(tokens), nil
+}
+
+// NewAttributeTokensFilter returns a Filter which replaces tokens of matched
+// attributes at a given address with a result of a given function.
+// The function receives tokens of the whole attribute including leading
+// comments, a trailing comment and a trailing newline, and is called for each
+// matched attribute in order.
+// Use it with WithFilters to wire it into a pipeline built by NewEditor.
+// The filter returns an error if the result is not valid HCL.
+func NewAttributeTokensFilter(address string, fn TokensFunc) Filter {
+	return &tokensFilter{
+		address: address,
+		fn:      fn.transform,
+		find:    findAttributeTokens,
+	}
+}
+
+// NewBlockTokensFilter returns a Filter which replaces tokens of matched top
+// level blocks at a given address with a result of a given function.
+// The function receives tokens of the whole block from the type to the
+// closing brace including a trailing newline, and is called for each matched
+// block in order.
+// Use it with WithFilters to wire it into a pipeline built by NewEditor.
+// The filter returns an error if the result is not valid HCL.
+func NewBlockTokensFilter(address string, fn TokensFunc) Filter {
+	return &tokensFilter{
+		address: address,
+		fn:      fn.transform,
+		find:    findBlockTokens,
+	}
+}
+
+// findAttributeTokens returns tokens of matched attributes in a given body.
+func findAttributeTokens(body *hclwrite.Body, address string) ([]hclwrite.Tokens, error) {
+	matches, err := findTargetAttributes(body, address)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []hclwrite.Tokens{}
+	for _, m := range matches {
+		found = append(found, m.attr.BuildTokens(nil))
+	}
+	return found, nil
+}
+
+// findBlockTokens returns tokens of matched top level blocks in a given body.
+func findBlockTokens(body *hclwrite.Body, address string) ([]hclwrite.Tokens, error) {
+	blocks, err := findBlocksByAddress(body, address)
+	if err != nil {
+		return nil, err
+	}
+
+	found := []hclwrite.Tokens{}
+	for _, b := range blocks {
+		found = append(found, b.BuildTokens(nil))
+	}
+	return found, nil
+}
+
+// tokensFilter is a filter implementation to transform tokens of matched items.
+type tokensFilter struct {
+	address string
+	fn      func(tokens hclwrite.Tokens) (hclwrite.Tokens, error)
+	// find returns tokens of matched items in a given body.
+	find func(body *hclwrite.Body, address string) ([]hclwrite.Tokens, error)
+}
+
+// Filter reads HCL and replaces tokens of matched items with transformed ones.
+func (f *tokensFilter) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	found, err := f.find(inFile.Body(), f.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(found) == 0 {
+		return inFile, nil
+	}
+
+	// Replace all items in one token stream and parse it once, because
+	// parsing again discards the identity of tokens of other items.
+	tokens := inFile.BuildTokens(nil)
+	for _, old := range found {
+		start, end := findTokens(tokens, old)
+		if start < 0 {
+			return nil, fmt.Errorf("failed to find tokens to be transformed: %s", f.address)
+		}
+
+		transformed, err := f.fn(cloneTokens(tokens[start:end]))
+		if err != nil {
+			return nil, err
+		}
+
+		var replaced hclwrite.Tokens
+		replaced = append(replaced, tokens[:start]...)
+		replaced = append(replaced, transformed...)
+		replaced = append(replaced, tokens[end:]...)
+		tokens = replaced
+	}
+
+	outFile, err := safeParseConfig(tokens.Bytes(), "generated_by_tokensFilter", hcl.Pos{Line: 1, Column: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to transform tokens: %s", err)
+	}
+
+	return outFile, nil
+}
+
+// cloneTokens returns a deep copy of given tokens, so that a modification of
+// them doesn't affect the original tree.
+func cloneTokens(tokens hclwrite.Tokens) hclwrite.Tokens {
+	cloned := make(hclwrite.Tokens, 0, len(tokens))
+	for _, t := range tokens {
+		c := *t
+		c.Bytes = append([]byte{}, t.Bytes...)
+		cloned = append(cloned, &c)
+	}
+
+	return cloned
+}
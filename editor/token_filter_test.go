@@ -0,0 +1,229 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// upperStrings returns tokens with string literals in upper case.
+func upperStrings(tokens hclwrite.Tokens) hclwrite.Tokens {
+	for _, t := range tokens {
+		if t.Type == hclsyntax.TokenQuotedLit {
+			t.Bytes = bytes.ToUpper(t.Bytes)
+		}
+	}
+	return tokens
+}
+
+// dropComments returns tokens without comments.
+func dropComments(tokens hclwrite.Tokens) hclwrite.Tokens {
+	var ret hclwrite.Tokens
+	for _, t := range tokens {
+		if t.Type == hclsyntax.TokenComment {
+			if bytes.HasSuffix(t.Bytes, []byte("\n")) && len(ret) != 0 && ret[len(ret)-1].Type != hclsyntax.TokenNewline {
+				// keep a newline of a trailing comment.
+				ret = append(ret, &hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")})
+			}
+			continue
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
+
+func TestAttributeTokensFilter(t *testing.T) {
+	src := `a0 = "v0"
+b1 "l1" {
+  // comment
+  a1 = "v1" # comment
+  a2 = "v2"
+}
+b1 "l2" {
+  a1 = "v3"
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		fn      TokensFunc
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "simple",
+			address: "a0",
+			fn:      upperStrings,
+			ok:      true,
+			want: `a0 = "V0"
+b1 "l1" {
+  // comment
+  a1 = "v1" # comment
+  a2 = "v2"
+}
+b1 "l2" {
+  a1 = "v3"
+}
+`,
+		},
+		{
+			name:    "multiple matches",
+			address: "b1.*.a1",
+			fn:      upperStrings,
+			ok:      true,
+			want: `a0 = "v0"
+b1 "l1" {
+  // comment
+  a1 = "V1" # comment
+  a2 = "v2"
+}
+b1 "l2" {
+  a1 = "V3"
+}
+`,
+		},
+		{
+			name:    "comments",
+			address: "b1.l1.a1",
+			fn:      dropComments,
+			ok:      true,
+			want: `a0 = "v0"
+b1 "l1" {
+  a1 = "v1"
+  a2 = "v2"
+}
+b1 "l2" {
+  a1 = "v3"
+}
+`,
+		},
+		{
+			name:    "not found",
+			address: "a3",
+			fn:      upperStrings,
+			ok:      true,
+			want:    src,
+		},
+		{
+			name:    "invalid result",
+			address: "a0",
+			fn: func(tokens hclwrite.Tokens) hclwrite.Tokens {
+				return tokens[:len(tokens)-2]
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := NewEditor(WithFilters(NewAttributeTokensFilter(tc.address, tc.fn))).Apply(inStream, outStream)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBlockTokensFilter(t *testing.T) {
+	src := `a0 = "v0"
+b1 "l1" {
+  a1 = "v1"
+}
+b1 "l2" {
+  a1 = "v2"
+}
+`
+
+	cases := []struct {
+		name    string
+		address string
+		fn      TokensFunc
+		ok      bool
+		want    string
+	}{
+		{
+			name:    "simple",
+			address: "b1.l1",
+			fn:      upperStrings,
+			ok:      true,
+			want: `a0 = "v0"
+b1 "L1" {
+  a1 = "V1"
+}
+b1 "l2" {
+  a1 = "v2"
+}
+`,
+		},
+		{
+			name:    "wildcard",
+			address: "b1.*",
+			fn: func(tokens hclwrite.Tokens) hclwrite.Tokens {
+				comment := &hclwrite.Token{Type: hclsyntax.TokenComment, Bytes: []byte("# generated\n")}
+				return append(hclwrite.Tokens{comment}, tokens...)
+			},
+			ok: true,
+			want: `a0 = "v0"
+# generated
+b1 "l1" {
+  a1 = "v1"
+}
+# generated
+b1 "l2" {
+  a1 = "v2"
+}
+`,
+		},
+		{
+			name:    "not found",
+			address: "b2",
+			fn:      upperStrings,
+			ok:      true,
+			want:    src,
+		},
+		{
+			name:    "invalid result",
+			address: "b1.l2",
+			fn: func(tokens hclwrite.Tokens) hclwrite.Tokens {
+				return tokens[:len(tokens)-2]
+			},
+			ok:   false,
+			want: "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(src)
+			outStream := new(bytes.Buffer)
+			err := NewEditor(WithFilters(NewBlockTokensFilter(tc.address, tc.fn))).Apply(inStream, outStream)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package editor
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// findTokens returns a range [start, end) of sub in tokens.
+// Tokens are compared by identity, so sub must be built from the same tree.
+// If not found, it returns (-1, -1).
+func findTokens(tokens hclwrite.Tokens, sub hclwrite.Tokens) (int, int) {
+	if len(sub) == 0 {
+		return -1, -1
+	}
+
+	for i := range tokens {
+		if tokens[i] == sub[0] {
+			if i+len(sub) > len(tokens) {
+				return -1, -1
+			}
+			return i, i + len(sub)
+		}
+	}
+
+	return -1, -1
+}
+
+// withTrailingNewline returns tokens which end with a newline.
+// This is useful when relocating the last item in a body without a trailing
+// newline.
+func withTrailingNewline(tokens hclwrite.Tokens) hclwrite.Tokens {
+	if len(tokens) != 0 {
+		last := tokens[len(tokens)-1]
+		if last.Type == hclsyntax.TokenNewline || endsWithNewline(last) {
+			return tokens
+		}
+	}
+
+	return append(tokens, &hclwrite.Token{
+		Type:  hclsyntax.TokenNewline,
+		Bytes: []byte("\n"),
+	})
+}
+
+// trimEOF trims a trailing TokenEOF from tokens of file.
+// It is useful when embedding tokens of a file into another.
+func trimEOF(tokens hclwrite.Tokens) hclwrite.Tokens {
+	if len(tokens) != 0 && tokens[len(tokens)-1].Type == hclsyntax.TokenEOF {
+		return tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// replaceTokens replaces tokens of old in a given file with new tokens, and
+// returns a new file by parsing the result.
+// Tokens are compared by identity, so old must be built from the same tree.
+// The hclwrite doesn't provide a way to insert or replace an arbitrary item
+// at an arbitrary position, so we edit tokens directly and parse them again.
+func replaceTokens(inFile *hclwrite.File, old hclwrite.Tokens, new hclwrite.Tokens) (*hclwrite.File, error) {
+	tokens := inFile.BuildTokens(nil)
+	start, end := findTokens(tokens, old)
+	if start < 0 {
+		return nil, fmt.Errorf("failed to find tokens to be replaced: %s", string(old.Bytes()))
+	}
+
+	var replaced hclwrite.Tokens
+	replaced = append(replaced, tokens[:start]...)
+	replaced = append(replaced, new...)
+	replaced = append(replaced, tokens[end:]...)
+
+	return safeParseConfig(replaced.Bytes(), "generated_by_replaceTokens", hcl.Pos{Line: 1, Column: 1})
+}
@@ -0,0 +1,121 @@
+package editor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// Value types which can be given to TypedValue.
+const (
+	// ValueTypeRaw means that a value is an expression and used as it is.
+	ValueTypeRaw = "raw"
+	// ValueTypeString means that a value is a string literal to be quoted.
+	ValueTypeString = "string"
+	// ValueTypeNumber means that a value is a number literal.
+	ValueTypeNumber = "number"
+	// ValueTypeBool means that a value is true or false.
+	ValueTypeBool = "bool"
+	// ValueTypeList means that a value is a JSON array.
+	ValueTypeList = "list"
+	// ValueTypeMap means that a value is a JSON object.
+	ValueTypeMap = "map"
+)
+
+// TypedValue converts a given value to an HCL expression for a given type.
+// The expression is generated from tokens of a cty value, so that a string is
+// always quoted and escaped properly. A list and a map are given in JSON.
+// If the type is raw or empty, the value is returned as it is.
+func TypedValue(value string, valueType string) (string, error) {
+	var v cty.Value
+	switch valueType {
+	case "", ValueTypeRaw:
+		return value, nil
+	case ValueTypeString:
+		v = cty.StringVal(value)
+	case ValueTypeNumber:
+		n, err := cty.ParseNumberVal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse value as number: %s: %s", value, err)
+		}
+		v = n
+	case ValueTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse value as bool: %s: %s", value, err)
+		}
+		v = cty.BoolVal(b)
+	case ValueTypeList, ValueTypeMap:
+		j, err := jsonValue(value)
+		if err != nil {
+			return "", err
+		}
+		t := j.Type()
+		if valueType == ValueTypeList && !(t.IsTupleType() || t.IsListType()) {
+			return "", fmt.Errorf("failed to parse value as list. expected a JSON array: %s", value)
+		}
+		if valueType == ValueTypeMap && !(t.IsObjectType() || t.IsMapType()) {
+			return "", fmt.Errorf("failed to parse value as map. expected a JSON object: %s", value)
+		}
+		v = j
+	default:
+		return "", fmt.Errorf("unknown value type: %s", valueType)
+	}
+
+	return string(hclwrite.TokensForValue(v).Bytes()), nil
+}
+
+// jsonValue parses a given JSON and returns a cty value with an implied type.
+func jsonValue(value string) (cty.Value, error) {
+	src := []byte(value)
+	t, err := ctyjson.ImpliedType(src)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("failed to parse value as JSON: %s: %s", value, err)
+	}
+
+	v, err := ctyjson.Unmarshal(src, t)
+	if err != nil {
+		return cty.NilVal, fmt.Errorf("failed to parse value as JSON: %s: %s", value, err)
+	}
+
+	return v, nil
+}
+
+// HeredocValue wraps given content in a heredoc expression with a given
+// delimiter such as EOF, so that a multi-line payload such as an IAM policy
+// can be set as it is. If indent is true, a flush heredoc (<<-) is used and
+// the content is indented to the nesting level of the attribute when it is
+// set by SetAttributeRawMultiline.
+// The content is set literally. Template sequences such as ${ are escaped,
+// because a payload like an IAM policy may contain ${aws:username}, which is
+// not a valid interpolation. Line endings are normalized and trailing
+// newlines are trimmed.
+// It returns an error if the delimiter is not a valid identifier or appears
+// in the content as a line by itself, which would close the heredoc early.
+func HeredocValue(content string, delimiter string, indent bool) (string, error) {
+	if !hclsyntax.ValidIdentifier(delimiter) {
+		return "", fmt.Errorf("invalid heredoc delimiter: %s", delimiter)
+	}
+
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	content = strings.TrimRight(content, "\n")
+	for _, l := range strings.Split(content, "\n") {
+		if strings.TrimSpace(l) == delimiter {
+			return "", fmt.Errorf("failed to build heredoc: the content contains the delimiter: %s", delimiter)
+		}
+	}
+
+	content = strings.NewReplacer("${", "$${", "%{", "%%{").Replace(content)
+
+	opener := "<<"
+	if indent {
+		opener = "<<-"
+	}
+
+	return opener + delimiter + "\n" + content + "\n" + delimiter, nil
+}
@@ -0,0 +1,189 @@
+package editor
+
+import (
+	"testing"
+)
+
+func TestTypedValue(t *testing.T) {
+	cases := []struct {
+		name      string
+		value     string
+		valueType string
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "raw",
+			value:     `var.foo`,
+			valueType: "raw",
+			ok:        true,
+			want:      `var.foo`,
+		},
+		{
+			name:      "empty means raw",
+			value:     `"foo"`,
+			valueType: "",
+			ok:        true,
+			want:      `"foo"`,
+		},
+		{
+			name:      "string",
+			value:     `foo "bar" ${baz}`,
+			valueType: "string",
+			ok:        true,
+			want:      `"foo \"bar\" $${baz}"`,
+		},
+		{
+			name:      "number",
+			value:     `1.5`,
+			valueType: "number",
+			ok:        true,
+			want:      `1.5`,
+		},
+		{
+			name:      "invalid number",
+			value:     `foo`,
+			valueType: "number",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "bool",
+			value:     `true`,
+			valueType: "bool",
+			ok:        true,
+			want:      `true`,
+		},
+		{
+			name:      "invalid bool",
+			value:     `yes`,
+			valueType: "bool",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "list",
+			value:     `["foo", 1]`,
+			valueType: "list",
+			ok:        true,
+			want:      `["foo", 1]`,
+		},
+		{
+			name:      "list not array",
+			value:     `{"foo": 1}`,
+			valueType: "list",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "map",
+			value:     `{"foo": "bar", "baz-qux": [true]}`,
+			valueType: "map",
+			ok:        true,
+			want:      `{ baz-qux = [true], foo = "bar" }`,
+		},
+		{
+			name:      "map not object",
+			value:     `[1]`,
+			valueType: "map",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "invalid json",
+			value:     `{foo`,
+			valueType: "map",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "unknown type",
+			value:     `foo`,
+			valueType: "hoge",
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := TypedValue(tc.value, tc.valueType)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHeredocValue(t *testing.T) {
+	cases := []struct {
+		name      string
+		content   string
+		delimiter string
+		indent    bool
+		ok        bool
+		want      string
+	}{
+		{
+			name:      "simple",
+			content:   "foo\n  bar\n",
+			delimiter: "EOF",
+			ok:        true,
+			want:      "<<EOF\nfoo\n  bar\nEOF",
+		},
+		{
+			name:      "indent",
+			content:   "foo\r\nbar",
+			delimiter: "EOT",
+			indent:    true,
+			ok:        true,
+			want:      "<<-EOT\nfoo\nbar\nEOT",
+		},
+		{
+			name:      "template sequences",
+			content:   "${aws:username} %{foo}",
+			delimiter: "EOF",
+			ok:        true,
+			want:      "<<EOF\n$${aws:username} %%{foo}\nEOF",
+		},
+		{
+			name:      "delimiter in content",
+			content:   "foo\n  EOF\nbar\n",
+			delimiter: "EOF",
+			ok:        false,
+			want:      "",
+		},
+		{
+			name:      "invalid delimiter",
+			content:   "foo\n",
+			delimiter: "",
+			ok:        false,
+			want:      "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := HeredocValue(tc.content, tc.delimiter, tc.indent)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, got: %s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got: %s, want: %s", got, tc.want)
+			}
+		})
+	}
+}
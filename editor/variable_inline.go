@@ -0,0 +1,119 @@
+package editor
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// InlineVariables reads HCL from io.Reader, and replaces simple variable
+// references such as var.NAME in expressions of attributes with values in a
+// given map, and writes the updated HCL to io.Writer.
+// Unlike ReplaceReference, the substitution is restricted to the subtree of
+// blocks matched by blockAddress, including their nested blocks, so that the
+// rest of the file is not affected.
+// Note that the value in vars is inlined as it is, so a string literal should
+// be quoted as well as attribute set.
+// Only a single-level variable reference is replaced, and references not
+// found in vars are left as they are.
+// Note that a filename is used only for an error message.
+// If an error occurs, Nothing is written to the output stream.
+func InlineVariables(r io.Reader, w io.Writer, filename string, blockAddress string, vars map[string]string, opts ...Option) error {
+	e := &Editor{
+		source: &parser{filename: filename},
+		filters: []Filter{
+			&variableInline{blockAddress: blockAddress, vars: vars},
+		},
+		sink: &formater{},
+	}
+	e.setOptions(opts)
+
+	return e.Apply(r, w)
+}
+
+// variableInline is a filter implementation for inlining variables.
+type variableInline struct {
+	blockAddress string
+	vars         map[string]string
+}
+
+// Filter reads HCL and inlines variables in matched blocks.
+func (f *variableInline) Filter(inFile *hclwrite.File) (*hclwrite.File, error) {
+	blocks, err := findLongestMatchingBlocks(inFile.Body(), f.blockAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range blocks {
+		if err := inlineVariablesInBody(b.Body(), f.vars); err != nil {
+			return nil, err
+		}
+	}
+
+	return inFile, nil
+}
+
+// inlineVariablesInBody replaces variable references in attributes of a given
+// body and nested blocks recursively.
+func inlineVariablesInBody(body *hclwrite.Body, vars map[string]string) error {
+	for _, name := range attributeNames(body) {
+		attr := body.GetAttribute(name)
+		tokens, changed, err := inlineVariablesInExpression(attr.Expr(), vars)
+		if err != nil {
+			return err
+		}
+		if changed {
+			body.SetAttributeRaw(name, tokens)
+		}
+	}
+
+	for _, b := range body.Blocks() {
+		if err := inlineVariablesInBody(b.Body(), vars); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// inlineVariablesInExpression returns tokens of a given expression whose
+// variable references are replaced with values in vars.
+// It also returns whether any reference was replaced.
+func inlineVariablesInExpression(expr *hclwrite.Expression, vars map[string]string) (hclwrite.Tokens, bool, error) {
+	tokens := expr.BuildTokens(nil)
+	changed := false
+	for _, t := range expr.Variables() {
+		traversal := t.BuildTokens(nil)
+		// A simple variable reference consists of var, a dot and a name.
+		if len(traversal) != 3 || string(traversal[0].Bytes) != "var" {
+			continue
+		}
+
+		value, ok := vars[string(traversal[2].Bytes)]
+		if !ok {
+			continue
+		}
+
+		start, end := findTokens(tokens, traversal)
+		if start < 0 {
+			continue
+		}
+
+		valueExpr, err := buildExpression("v", value)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to inline variable %s: %s", string(traversal[2].Bytes), err)
+		}
+		valueTokens := valueExpr.BuildTokens(nil)
+		valueTokens[0].SpacesBefore = traversal[0].SpacesBefore
+
+		newTokens := hclwrite.Tokens{}
+		newTokens = append(newTokens, tokens[:start]...)
+		newTokens = append(newTokens, valueTokens...)
+		newTokens = append(newTokens, tokens[end:]...)
+		tokens = newTokens
+		changed = true
+	}
+
+	return tokens, changed, nil
+}
@@ -0,0 +1,146 @@
+package editor
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVariableInline(t *testing.T) {
+	cases := []struct {
+		name         string
+		src          string
+		blockAddress string
+		vars         map[string]string
+		ok           bool
+		want         string
+	}{
+		{
+			name: "only in the subtree",
+			src: `
+resource "aws_instance" "web" {
+  ami = var.ami
+  ebs_block_device {
+    volume_size = var.size
+  }
+}
+
+resource "aws_instance" "db" {
+  ami = var.ami
+}
+`,
+			blockAddress: "resource.aws_instance.web",
+			vars:         map[string]string{"ami": `"ami-123"`, "size": "10"},
+			ok:           true,
+			want: `
+resource "aws_instance" "web" {
+  ami = "ami-123"
+  ebs_block_device {
+    volume_size = 10
+  }
+}
+
+resource "aws_instance" "db" {
+  ami = var.ami
+}
+`,
+		},
+		{
+			name: "references in complex expressions",
+			src: `
+b1 {
+  a1 = "${var.prefix}-web"
+  a2 = [var.x, var.y, var.x]
+  a3 = max(var.x, 1)
+}
+`,
+			blockAddress: "b1",
+			vars:         map[string]string{"prefix": `"dev"`, "x": "1"},
+			ok:           true,
+			want: `
+b1 {
+  a1 = "${"dev"}-web"
+  a2 = [1, var.y, 1]
+  a3 = max(1, 1)
+}
+`,
+		},
+		{
+			name: "nested traversal and other roots are left untouched",
+			src: `
+b1 {
+  a1 = var.x.y
+  a2 = local.x
+  a3 = foo.var.x
+}
+`,
+			blockAddress: "b1",
+			vars:         map[string]string{"x": "1"},
+			ok:           true,
+			want: `
+b1 {
+  a1 = var.x.y
+  a2 = local.x
+  a3 = foo.var.x
+}
+`,
+		},
+		{
+			name: "no match",
+			src: `
+b1 {
+  a1 = var.x
+}
+`,
+			blockAddress: "b2",
+			vars:         map[string]string{"x": "1"},
+			ok:           true,
+			want: `
+b1 {
+  a1 = var.x
+}
+`,
+		},
+		{
+			name: "invalid value",
+			src: `
+b1 {
+  a1 = var.x
+}
+`,
+			blockAddress: "b1",
+			vars:         map[string]string{"x": "{"},
+			ok:           false,
+			want:         "",
+		},
+		{
+			name: "empty block address",
+			src: `
+a1 = var.x
+`,
+			blockAddress: "",
+			vars:         map[string]string{"x": "1"},
+			ok:           false,
+			want:         "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			inStream := bytes.NewBufferString(tc.src)
+			outStream := new(bytes.Buffer)
+			err := InlineVariables(inStream, outStream, "test", tc.blockAddress, tc.vars)
+			if tc.ok && err != nil {
+				t.Fatalf("unexpected err = %s", err)
+			}
+
+			got := outStream.String()
+			if !tc.ok && err == nil {
+				t.Fatalf("expected to return an error, but no error, outStream: \n%s", got)
+			}
+
+			if got != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+//go:build !js && !plan9
+// +build !js,!plan9
+
+package editor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is a duration to wait for subsequent events before calling
+// a function, so that a burst of events such as an atomic write results in
+// a single call.
+const watchDebounce = 100 * time.Millisecond
+
+// WatchFiles watches given files, and calls a given function whenever any of
+// them changes until done is closed.
+// A change is detected by comparing contents of files with ones seen after the
+// last call, so that writes made by the function itself such as an in-place
+// update never trigger another call. Otherwise, a non-idempotent command would
+// be run again and again.
+// Parent directories of the files are watched instead of the files
+// themselves, so that a file replaced atomically by an editor or by
+// WriteFileAtomic is still tracked. Files created after the call are not
+// watched.
+// It returns an error if it fails to watch files.
+func WatchFiles(done <-chan struct{}, filenames []string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to watch files: %s", err)
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, filename := range filenames {
+		path, err := filepath.Abs(filename)
+		if err != nil {
+			return fmt.Errorf("failed to watch files: %s", err)
+		}
+		watched[path] = true
+
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch files: %s: %s", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	seen := hashFiles(watched)
+
+	// timer fires after events settle down. It is stopped until the first event.
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if !watched[filepath.Clean(event.Name)] {
+				continue
+			}
+			timer.Reset(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("failed to watch files: %s", err)
+		case <-timer.C:
+			current := hashFiles(watched)
+			if equalHashes(seen, current) {
+				continue
+			}
+			onChange()
+			// record contents after the call to ignore its own writes.
+			seen = hashFiles(watched)
+		}
+	}
+}
+
+// hashFiles returns hashes of contents of given files.
+// A file which cannot be read is hashed as empty.
+func hashFiles(paths map[string]bool) map[string][]byte {
+	hashes := make(map[string][]byte, len(paths))
+	for path := range paths {
+		// ignore an error because a file may be removed temporarily.
+		b, _ := ioutil.ReadFile(path)
+		sum := sha256.Sum256(b)
+		hashes[path] = sum[:]
+	}
+
+	return hashes
+}
+
+// equalHashes returns true if all hashes of files are the same.
+func equalHashes(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, sum := range a {
+		if !bytes.Equal(sum, b[path]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,161 @@
+//go:build !js && !plan9
+// +build !js,!plan9
+
+package editor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	watched := filepath.Join(dir, "a.tf")
+	other := filepath.Join(dir, "b.tf")
+	for _, path := range []string{watched, other} {
+		if err := ioutil.WriteFile(path, []byte("a0 = v0\n"), 0600); err != nil {
+			t.Fatalf("failed to write file: %s", err)
+		}
+	}
+
+	changed := make(chan struct{}, 10)
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- WatchFiles(done, []string{watched}, func() { changed <- struct{}{} })
+	}()
+	// wait for the watcher to start.
+	time.Sleep(100 * time.Millisecond)
+
+	cases := []struct {
+		name   string
+		change func() error
+		want   bool
+	}{
+		{
+			name:   "write",
+			change: func() error { return ioutil.WriteFile(watched, []byte("a0 = v1\n"), 0600) },
+			want:   true,
+		},
+		{
+			name:   "atomic write",
+			change: func() error { return WriteFileAtomic(watched, []byte("a0 = v2\n"), "") },
+			want:   true,
+		},
+		{
+			name:   "same contents",
+			change: func() error { return WriteFileAtomic(watched, []byte("a0 = v2\n"), "") },
+			want:   false,
+		},
+		{
+			name:   "not watched",
+			change: func() error { return ioutil.WriteFile(other, []byte("a0 = v1\n"), 0600) },
+			want:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.change(); err != nil {
+				t.Fatalf("failed to change file: %s", err)
+			}
+
+			got := false
+			select {
+			case <-changed:
+				got = true
+			case <-time.After(time.Second):
+			}
+
+			if got != tc.want {
+				t.Fatalf("got = %t, but want = %t", got, tc.want)
+			}
+
+			// the debounce collapses a burst of events into one call.
+			select {
+			case <-changed:
+				t.Fatalf("unexpected call")
+			case <-time.After(3 * watchDebounce):
+			}
+		})
+	}
+
+	close(done)
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected err = %s", err)
+	}
+}
+
+func TestWatchFilesIgnoresOwnWrites(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hcledit")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	watched := filepath.Join(dir, "a.tf")
+	if err := ioutil.WriteFile(watched, []byte("a0 = v0\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	// onChange appends a block to the watched file like an in-place update of
+	// a non-idempotent command, whose result differs on each run.
+	changed := make(chan struct{}, 10)
+	onChange := func() {
+		b, err := ioutil.ReadFile(watched)
+		if err != nil {
+			t.Errorf("failed to read file: %s", err)
+		}
+		if err := WriteFileAtomic(watched, append(b, []byte("b {}\n")...), ""); err != nil {
+			t.Errorf("failed to write file: %s", err)
+		}
+		changed <- struct{}{}
+	}
+
+	done := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- WatchFiles(done, []string{watched}, onChange)
+	}()
+	// wait for the watcher to start.
+	time.Sleep(100 * time.Millisecond)
+
+	if err := ioutil.WriteFile(watched, []byte("a0 = v1\n"), 0600); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected a call, but not called")
+	}
+
+	// the write of onChange itself must not trigger another call.
+	select {
+	case <-changed:
+		t.Fatalf("unexpected call triggered by its own write")
+	case <-time.After(5 * watchDebounce):
+	}
+
+	close(done)
+	if err := <-errCh; err != nil {
+		t.Fatalf("unexpected err = %s", err)
+	}
+
+	got, err := ioutil.ReadFile(watched)
+	if err != nil {
+		t.Fatalf("failed to read file: %s", err)
+	}
+	want := "a0 = v1\nb {}\n"
+	if string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", string(got), want)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build js || plan9
+// +build js plan9
+
+package editor
+
+import (
+	"fmt"
+)
+
+// WatchFiles is not supported on this platform, and always returns an error.
+func WatchFiles(done <-chan struct{}, filenames []string, onChange func()) error {
+	return fmt.Errorf("watching files is not supported on this platform")
+}
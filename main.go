@@ -15,8 +15,15 @@ func main() {
 	log.SetOutput(logOutput())
 	log.Printf("[INFO] CLI args: %#v", os.Args)
 	if err := cmd.RootCmd.Execute(); err != nil {
+		code := 1
+		if exitErr, ok := err.(*cmd.ExitError); ok {
+			code = exitErr.Code
+			if exitErr.Err == nil {
+				os.Exit(code)
+			}
+		}
 		fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(1)
+		os.Exit(code)
 	}
 }
 
@@ -0,0 +1,93 @@
+//go:build js && wasm
+// +build js,wasm
+
+// Package main is a WebAssembly module which exposes the editor to
+// JavaScript, so that web playgrounds and editor extensions can reuse the
+// same matching semantics as the CLI.
+//
+// It registers a global object named hcledit with the following functions.
+// Each function takes HCL source as a string and returns an object
+// {result, error}, where result is the output as a string and error is an
+// error message or null.
+//
+//	hcledit.attributeGet(src, address)
+//	hcledit.attributeSet(src, address, value)
+//	hcledit.attributeAppend(src, address, value, newline)
+//	hcledit.attributeRm(src, address)
+//	hcledit.blockGet(src, address)
+//	hcledit.blockAppend(src, parent, child, newline)
+//	hcledit.blockRm(src, address)
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"syscall/js"
+
+	"github.com/minamijoyo/hcledit/editor"
+)
+
+// edit is a function which reads HCL from io.Reader and writes a result to
+// io.Writer with given arguments.
+type edit func(r io.Reader, w io.Writer, args []js.Value) error
+
+func main() {
+	api := map[string]interface{}{
+		"attributeGet": newFunc(1, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.GetAttribute(r, w, "-", args[0].String())
+		}),
+		"attributeSet": newFunc(2, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.SetAttribute(r, w, "-", args[0].String(), args[1].String())
+		}),
+		"attributeAppend": newFunc(3, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.AppendAttribute(r, w, "-", args[0].String(), args[1].String(), args[2].Truthy())
+		}),
+		"attributeRm": newFunc(1, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.RemoveAttribute(r, w, "-", args[0].String())
+		}),
+		"blockGet": newFunc(1, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.GetBlock(r, w, "-", args[0].String())
+		}),
+		"blockAppend": newFunc(3, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.AppendBlock(r, w, "-", args[0].String(), args[1].String(), args[2].Truthy(), "")
+		}),
+		"blockRm": newFunc(1, func(r io.Reader, w io.Writer, args []js.Value) error {
+			return editor.RemoveBlock(r, w, "-", args[0].String())
+		}),
+	}
+	js.Global().Set("hcledit", js.ValueOf(api))
+
+	// keep the module alive so that the functions can be called.
+	select {}
+}
+
+// newFunc returns a JavaScript function which calls a given edit function
+// with HCL source as the first argument and n more arguments.
+func newFunc(n int, fn edit) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) != n+1 {
+			return result("", fmt.Errorf("expected %d arguments, but got %d arguments", n+1, len(args)))
+		}
+		if args[0].Type() != js.TypeString {
+			return result("", fmt.Errorf("expected HCL source as a string, but got %s", args[0].Type()))
+		}
+
+		r := bytes.NewBufferString(args[0].String())
+		w := new(bytes.Buffer)
+		if err := fn(r, w, args[1:]); err != nil {
+			return result("", err)
+		}
+
+		return result(w.String(), nil)
+	})
+}
+
+// result returns an object {result, error} passed to JavaScript.
+func result(out string, err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{"result": "", "error": err.Error()}
+	}
+
+	return map[string]interface{}{"result": out, "error": nil}
+}